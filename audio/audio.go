@@ -238,9 +238,10 @@ type playerImpl struct {
 	srcEOF     bool
 	sampleRate int
 
-	buf    []byte
-	pos    int64
-	volume float64
+	buf        []byte
+	pos        int64
+	volume     float64
+	volumeRamp volumeRamp
 
 	closeCh         chan struct{}
 	closedCh        chan struct{}
@@ -288,6 +289,7 @@ func NewPlayer(context *Context, src io.ReadCloser) (*Player, error) {
 			sampleRate:      context.sampleRate,
 			buf:             nil,
 			volume:          1,
+			volumeRamp:      newVolumeRamp(1),
 			closeCh:         make(chan struct{}),
 			closedCh:        make(chan struct{}),
 			readLoopEndedCh: make(chan struct{}),
@@ -512,8 +514,8 @@ func (p *playerImpl) readLoop() {
 			}
 			for i := 0; i < l/2; i++ {
 				buf[i] = int16(p.buf[2*i]) | (int16(p.buf[2*i+1]) << 8)
-				buf[i] = int16(float64(buf[i]) * p.volume)
 			}
+			p.volumeRamp.apply(buf[:l/2])
 			p.pos += int64(l)
 			p.buf = p.buf[l:]
 
@@ -668,6 +670,9 @@ func (p *playerImpl) Volume() float64 {
 
 // SetVolume sets the volume of this player.
 // volume must be in between 0 and 1. SetVolume panics otherwise.
+//
+// The volume of the sound changes smoothly in a short duration to avoid a clicking noise,
+// while Volume returns the new volume immediately.
 func (p *Player) SetVolume(volume float64) {
 	p.p.SetVolume(volume)
 }
@@ -680,5 +685,6 @@ func (p *playerImpl) SetVolume(volume float64) {
 
 	p.sync(func() {
 		p.volume = volume
+		p.volumeRamp.setTarget(volume, int(int64(p.sampleRate)*int64(volumeRampDuration)/int64(time.Second)))
 	})
 }
@@ -0,0 +1,73 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"time"
+)
+
+// volumeRampDuration is the duration to change the volume.
+const volumeRampDuration = 10 * time.Millisecond
+
+// volumeRamp applies a volume to samples.
+//
+// When the volume is changed, volumeRamp changes the applied volume linearly in a short duration
+// instead of immediately, since a sudden volume change causes a clicking noise.
+type volumeRamp struct {
+	current float64
+	target  float64
+
+	// step is the volume change per frame (a pair of left and right samples).
+	step float64
+
+	// frames is the number of the frames left to reach the target.
+	frames int
+}
+
+func newVolumeRamp(volume float64) volumeRamp {
+	return volumeRamp{
+		current: volume,
+		target:  volume,
+	}
+}
+
+// setTarget sets the volume to reach in the given number of frames.
+func (v *volumeRamp) setTarget(volume float64, frames int) {
+	v.target = volume
+	if frames <= 0 || v.current == volume {
+		v.current = volume
+		v.step = 0
+		v.frames = 0
+		return
+	}
+	v.step = (volume - v.current) / float64(frames)
+	v.frames = frames
+}
+
+// apply applies the volume to the stereo samples buf in place.
+func (v *volumeRamp) apply(buf []int16) {
+	for i := 0; i < len(buf); i++ {
+		// Update the volume per frame so that the left and the right samples have the same volume.
+		if i%2 == 0 && v.frames > 0 {
+			v.frames--
+			if v.frames == 0 {
+				v.current = v.target
+			} else {
+				v.current += v.step
+			}
+		}
+		buf[i] = int16(float64(buf[i]) * v.current)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"testing"
+)
+
+func TestVolumeRamp(t *testing.T) {
+	v := newVolumeRamp(1)
+
+	buf := []int16{1000, -1000, 1000, -1000}
+	v.apply(buf)
+	if want := []int16{1000, -1000, 1000, -1000}; !equalInt16s(buf, want) {
+		t.Errorf("without ramp: got: %v, want: %v", buf, want)
+	}
+
+	// The volume reaches 0 in 4 frames. The left and right samples in a frame have the same volume.
+	v.setTarget(0, 4)
+	buf = []int16{1000, -1000, 1000, -1000, 1000, -1000, 1000, -1000, 1000, -1000}
+	v.apply(buf)
+	want := []int16{750, -750, 500, -500, 250, -250, 0, 0, 0, 0}
+	if !equalInt16s(buf, want) {
+		t.Errorf("ramp to 0: got: %v, want: %v", buf, want)
+	}
+
+	// A ramp continues across buffers.
+	v.setTarget(0.5, 4)
+	buf = []int16{1000, 1000, 1000, 1000}
+	v.apply(buf)
+	if want := []int16{125, 125, 250, 250}; !equalInt16s(buf, want) {
+		t.Errorf("ramp in the first buffer: got: %v, want: %v", buf, want)
+	}
+	buf = []int16{1000, 1000, 1000, 1000, 1000, 1000}
+	v.apply(buf)
+	if want := []int16{375, 375, 500, 500, 500, 500}; !equalInt16s(buf, want) {
+		t.Errorf("ramp in the second buffer: got: %v, want: %v", buf, want)
+	}
+
+	// Setting the volume in the middle of a ramp starts a new ramp from the current volume.
+	v.setTarget(1, 2)
+	v.apply([]int16{0, 0})
+	v.setTarget(0, 3)
+	buf = []int16{1000, 1000, 1000, 1000, 1000, 1000}
+	v.apply(buf)
+	if want := []int16{500, 500, 250, 250, 0, 0}; !equalInt16s(buf, want) {
+		t.Errorf("ramp from the middle: got: %v, want: %v", buf, want)
+	}
+
+	// Without frames, the volume changes immediately.
+	v.setTarget(0.25, 0)
+	buf = []int16{1000, 1000}
+	v.apply(buf)
+	if want := []int16{250, 250}; !equalInt16s(buf, want) {
+		t.Errorf("immediate change: got: %v, want: %v", buf, want)
+	}
+}
+
+func equalInt16s(a, b []int16) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
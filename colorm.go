@@ -76,6 +76,25 @@ func (c *ColorM) Scale(r, g, b, a float64) {
 	c.impl = c.impl.Scale(float32(r), float32(g), float32(b), float32(a))
 }
 
+// ScaleWithColor scales the matrix by clr, i.e. tints the colors with clr.
+//
+// clr's values are converted to straight alpha, as ColorM is applied to straight-alpha colors.
+// For example, both color.RGBA{0x80, 0x80, 0x80, 0x80} and color.NRGBA{0xff, 0xff, 0xff, 0x80}
+// scale the matrix by (1, 1, 1, 0.5).
+// If clr is fully transparent, the matrix is scaled by (0, 0, 0, 0).
+func (c *ColorM) ScaleWithColor(clr color.Color) {
+	r, g, b, a := clr.RGBA()
+	if a == 0 {
+		c.Scale(0, 0, 0, 0)
+		return
+	}
+	rf := float64(r) / float64(a)
+	gf := float64(g) / float64(a)
+	bf := float64(b) / float64(a)
+	af := float64(a) / 0xffff
+	c.Scale(rf, gf, bf, af)
+}
+
 // Translate translates the matrix by (r, g, b, a).
 func (c *ColorM) Translate(r, g, b, a float64) {
 	c.impl = c.impl.Translate(float32(r), float32(g), float32(b), float32(a))
@@ -83,6 +102,9 @@ func (c *ColorM) Translate(r, g, b, a float64) {
 
 // RotateHue rotates the hue.
 // theta represents rotating angle in radian.
+//
+// The hue is rotated around the luma axis in the YCbCr space, so the luma and the alpha values are not changed.
+// Rotating by 2π results in the identity matrix within floating-point errors.
 func (c *ColorM) RotateHue(theta float64) {
 	c.ChangeHSV(theta, 1, 1)
 }
@@ -97,6 +119,84 @@ func (c *ColorM) ChangeHSV(hueTheta float64, saturationScale float64, valueScale
 	c.impl = c.impl.ChangeHSV(hueTheta, float32(saturationScale), float32(valueScale))
 }
 
+// ChangeSaturation scales the saturation by scale.
+//
+// 0 makes colors monochrome, which is same as ChangeHSV(0, 0, 1).
+// 1 doesn't change colors, and values greater than 1 oversaturate colors.
+func (c *ColorM) ChangeSaturation(scale float64) {
+	c.ChangeHSV(0, scale, 1)
+}
+
+// ChangeContrast scales the contrast by scale around the 50% gray.
+//
+// 0 makes all the colors 50% gray, 1 doesn't change colors, and values greater than 1 increase the contrast.
+// The alpha values are not changed.
+func (c *ColorM) ChangeContrast(scale float64) {
+	t := 0.5 * (1 - scale)
+	c.Scale(scale, scale, scale, 1)
+	c.Translate(t, t, t, 0)
+}
+
+// ChangeBrightness adds delta to the red, green and blue values.
+//
+// Positive values make colors brighter and negative values make them darker.
+// The alpha values are not changed. The result colors are clamped to [0, 1].
+//
+// Note that gamma correction can't be represented as a ColorM since it is not an affine transformation.
+// To apply gamma correction, use a user-defined shader (see NewShader) with a uniform variable for the gamma value.
+func (c *ColorM) ChangeBrightness(delta float64) {
+	c.Translate(delta, delta, delta, 0)
+}
+
+// Invert inverts the colors, i.e. maps each of the red, green and blue values v to 1 - v.
+//
+// The alpha values are not changed. Inverting twice results in the identity matrix.
+func (c *ColorM) Invert() {
+	c.Scale(-1, -1, -1, 1)
+	c.Translate(1, 1, 1, 0)
+}
+
+// LuminanceToAlpha converts the luminance of the colors to the alpha values, and makes the red,
+// green and blue values 0.
+//
+// LuminanceToAlpha is useful to use a grayscale image as a mask with CompositeModeDestinationIn:
+// the destination's alpha values are multiplied by the mask's luminance.
+//
+// The luminance is calculated from the straight-alpha color values, and the original alpha values are
+// not used. As a fully transparent color has 0 as its color values, its luminance is 0.
+func (c *ColorM) LuminanceToAlpha() {
+	m := ColorM{}
+	for i := 0; i < ColorMDim-1; i++ {
+		m.SetElement(i, i, 0)
+	}
+	m.SetElement(3, 0, 0.299)
+	m.SetElement(3, 1, 0.587)
+	m.SetElement(3, 2, 0.114)
+	c.Concat(m)
+}
+
+// Sepia converts the colors to the sepia tone with the standard coefficients:
+//
+//     R' = 0.393R + 0.769G + 0.189B
+//     G' = 0.349R + 0.686G + 0.168B
+//     B' = 0.272R + 0.534G + 0.131B
+//
+// The alpha values are not changed. Note that the results are clamped to [0, 1], e.g., white is
+// converted to a light yellow.
+func (c *ColorM) Sepia() {
+	m := ColorM{}
+	for i, row := range [...][3]float64{
+		{0.393, 0.769, 0.189},
+		{0.349, 0.686, 0.168},
+		{0.272, 0.534, 0.131},
+	} {
+		for j, v := range row {
+			m.SetElement(i, j, v)
+		}
+	}
+	c.Concat(m)
+}
+
 // Element returns a value of a matrix at (i, j).
 func (c *ColorM) Element(i, j int) float64 {
 	b, t := c.impl.UnsafeElements()
@@ -111,6 +211,14 @@ func (c *ColorM) SetElement(i, j int, element float64) {
 	c.impl = c.impl.SetElement(i, j, float32(element))
 }
 
+// IsIdentity reports whether c is an identity matrix.
+//
+// The check is exact: IsIdentity returns false for a matrix that is almost identity
+// due to floating-point errors, e.g., a matrix whose hue is rotated by 2π.
+func (c *ColorM) IsIdentity() bool {
+	return c.impl.IsIdentity()
+}
+
 // Monochrome is deprecated as of 1.6.0-alpha. Use ChangeHSV(0, 0, 1) instead.
 func Monochrome() ColorM {
 	c := ColorM{}
@@ -234,3 +234,305 @@ func TestColorMApply(t *testing.T) {
 		}
 	}
 }
+
+func TestColorMScaleWithColor(t *testing.T) {
+	cases := []struct {
+		Name  string
+		In    color.Color
+		Scale [4]float64
+	}{
+		{"opaque red", color.RGBA{0xff, 0, 0, 0xff}, [4]float64{1, 0, 0, 1}},
+		{"50% alpha white (straight)", color.NRGBA{0xff, 0xff, 0xff, 0x80}, [4]float64{1, 1, 1, 0x80 / 255.0}},
+		{"50% alpha white (premultiplied)", color.RGBA{0x80, 0x80, 0x80, 0x80}, [4]float64{1, 1, 1, 0x80 / 255.0}},
+		{"transparent", color.Transparent, [4]float64{0, 0, 0, 0}},
+		{"transparent (straight)", color.NRGBA{0xff, 0xff, 0xff, 0}, [4]float64{0, 0, 0, 0}},
+	}
+	for _, c := range cases {
+		m := ColorM{}
+		m.ScaleWithColor(c.In)
+		for i := 0; i < ColorMDim-1; i++ {
+			for j := 0; j < ColorMDim; j++ {
+				want := 0.0
+				if i == j {
+					want = c.Scale[i]
+				}
+				got := m.Element(i, j)
+				if math.IsNaN(got) || math.Abs(got-want) > 1e-6 {
+					t.Errorf("%s: m.Element(%d, %d): got %f, want %f", c.Name, i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestColorMChangeBrightness(t *testing.T) {
+	cases := []struct {
+		Delta float64
+		In    color.Color
+		Out   color.Color
+	}{
+		{0.25, color.RGBA{0x40, 0x80, 0xc0, 0xff}, color.RGBA{0x80, 0xc0, 0xff, 0xff}},
+		{-0.25, color.RGBA{0x40, 0x80, 0xc0, 0xff}, color.RGBA{0, 0x40, 0x80, 0xff}},
+		// The result is clamped to [0, 1].
+		{1, color.RGBA{0x40, 0x80, 0xc0, 0xff}, color.RGBA{0xff, 0xff, 0xff, 0xff}},
+		{-1, color.RGBA{0x40, 0x80, 0xc0, 0xff}, color.RGBA{0, 0, 0, 0xff}},
+		// The alpha value is not changed.
+		{0.5, color.NRGBA{0, 0, 0, 0x80}, color.NRGBA{0x80, 0x80, 0x80, 0x80}},
+		{0.5, color.Transparent, color.Transparent},
+	}
+	for _, c := range cases {
+		m := ColorM{}
+		m.ChangeBrightness(c.Delta)
+		out := m.Apply(c.In)
+		r0, g0, b0, a0 := out.RGBA()
+		r1, g1, b1, a1 := c.Out.RGBA()
+		const delta = 0x101
+		if absDiffU32(r0, r1) > delta || absDiffU32(g0, g1) > delta ||
+			absDiffU32(b0, b1) > delta || absDiffU32(a0, a1) > delta {
+			t.Errorf("ChangeBrightness(%f).Apply(%v) = {%d, %d, %d, %d}, want {%d, %d, %d, %d}", c.Delta, c.In, r0, g0, b0, a0, r1, g1, b1, a1)
+		}
+	}
+}
+
+func TestColorMInvert(t *testing.T) {
+	inv := ColorM{}
+	inv.Invert()
+
+	cases := []struct {
+		In  color.Color
+		Out color.Color
+	}{
+		{color.White, color.Black},
+		{color.Black, color.White},
+		{color.RGBA{0xff, 0x80, 0, 0xff}, color.RGBA{0, 0x7f, 0xff, 0xff}},
+		// The alpha value is not changed.
+		{color.NRGBA{0xff, 0xff, 0xff, 0x80}, color.NRGBA{0, 0, 0, 0x80}},
+	}
+	for _, c := range cases {
+		out := inv.Apply(c.In)
+		r0, g0, b0, a0 := out.RGBA()
+		r1, g1, b1, a1 := c.Out.RGBA()
+		const delta = 0x101
+		if absDiffU32(r0, r1) > delta || absDiffU32(g0, g1) > delta ||
+			absDiffU32(b0, b1) > delta || absDiffU32(a0, a1) > delta {
+			t.Errorf("Invert().Apply(%v) = {%d, %d, %d, %d}, want {%d, %d, %d, %d}", c.In, r0, g0, b0, a0, r1, g1, b1, a1)
+		}
+	}
+
+	// Inverting twice results in the identity.
+	m := ColorM{}
+	m.Invert()
+	m.Concat(inv)
+	for i := 0; i < ColorMDim-1; i++ {
+		for j := 0; j < ColorMDim; j++ {
+			want := 0.0
+			if i == j {
+				want = 1
+			}
+			if got := m.Element(i, j); math.Abs(got-want) > 1e-6 {
+				t.Errorf("m.Element(%d, %d): got %f, want %f", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestColorMLuminanceToAlpha(t *testing.T) {
+	m := ColorM{}
+	m.LuminanceToAlpha()
+
+	cases := []struct {
+		In  color.Color
+		Out color.Color
+	}{
+		{color.White, color.NRGBA{0, 0, 0, 0xff}},
+		{color.Black, color.Transparent},
+		{color.RGBA{0x80, 0x80, 0x80, 0xff}, color.NRGBA{0, 0, 0, 0x80}},
+		{color.RGBA{0xff, 0, 0, 0xff}, color.NRGBA{0, 0, 0, 0x4c}},
+		// The original alpha value is not used.
+		{color.NRGBA{0xff, 0xff, 0xff, 0x80}, color.NRGBA{0, 0, 0, 0xff}},
+		{color.Transparent, color.Transparent},
+	}
+	for _, c := range cases {
+		out := m.Apply(c.In)
+		r0, g0, b0, a0 := out.RGBA()
+		r1, g1, b1, a1 := c.Out.RGBA()
+		const delta = 0x101
+		if absDiffU32(r0, r1) > delta || absDiffU32(g0, g1) > delta ||
+			absDiffU32(b0, b1) > delta || absDiffU32(a0, a1) > delta {
+			t.Errorf("LuminanceToAlpha().Apply(%v) = {%d, %d, %d, %d}, want {%d, %d, %d, %d}", c.In, r0, g0, b0, a0, r1, g1, b1, a1)
+		}
+	}
+}
+
+func TestColorMSepia(t *testing.T) {
+	m := ColorM{}
+	m.Sepia()
+
+	cases := []struct {
+		In  color.Color
+		Out color.Color
+	}{
+		{color.RGBA{0x80, 0x80, 0x80, 0xff}, color.RGBA{0xad, 0x9a, 0x78, 0xff}},
+		{color.White, color.RGBA{0xff, 0xff, 0xef, 0xff}},
+		{color.Black, color.RGBA{0, 0, 0, 0xff}},
+		{color.RGBA{0xff, 0, 0, 0xff}, color.RGBA{0x64, 0x59, 0x45, 0xff}},
+		// The alpha value is kept.
+		{color.NRGBA{0x80, 0x80, 0x80, 0x80}, color.NRGBA{0xad, 0x9a, 0x78, 0x80}},
+		{color.Transparent, color.Transparent},
+	}
+	for _, c := range cases {
+		out := m.Apply(c.In)
+		r0, g0, b0, a0 := out.RGBA()
+		r1, g1, b1, a1 := c.Out.RGBA()
+		const delta = 0x101
+		if absDiffU32(r0, r1) > delta || absDiffU32(g0, g1) > delta ||
+			absDiffU32(b0, b1) > delta || absDiffU32(a0, a1) > delta {
+			t.Errorf("Sepia().Apply(%v) = {%d, %d, %d, %d}, want {%d, %d, %d, %d}", c.In, r0, g0, b0, a0, r1, g1, b1, a1)
+		}
+	}
+}
+
+func TestColorMIsIdentity(t *testing.T) {
+	var m ColorM
+	if !m.IsIdentity() {
+		t.Errorf("ColorM{}.IsIdentity(): got false; want true")
+	}
+
+	m.Scale(1, 1, 1, 1)
+	if !m.IsIdentity() {
+		t.Errorf("m.IsIdentity(): got false; want true")
+	}
+
+	m.Translate(0, 0, 0.5, 0)
+	if m.IsIdentity() {
+		t.Errorf("m.IsIdentity(): got true; want false")
+	}
+
+	m.Reset()
+	m.ChangeHSV(0, 0, 1)
+	if m.IsIdentity() {
+		t.Errorf("m.IsIdentity(): got true; want false")
+	}
+	m.Reset()
+	if !m.IsIdentity() {
+		t.Errorf("m.IsIdentity(): got false; want true")
+	}
+}
+
+func TestColorMRotateHue(t *testing.T) {
+	full := ColorM{}
+	full.RotateHue(2 * math.Pi)
+	for j := 0; j < ColorMDim-1; j++ {
+		for i := 0; i < ColorMDim-1; i++ {
+			want := 0.0
+			if i == j {
+				want = 1
+			}
+			if got := full.Element(i, j); math.Abs(got-want) > 1e-4 {
+				t.Errorf("full.Element(%d, %d): got: %f, want: %f", i, j, got, want)
+			}
+		}
+	}
+
+	third := ColorM{}
+	third.RotateHue(2 * math.Pi / 3)
+	r, g, b, a := third.Apply(color.RGBA{0xff, 0, 0, 0xff}).RGBA()
+	if g <= r || g <= b {
+		t.Errorf("red rotated by 2π/3: got: {%d, %d, %d, %d}, want: green-dominant color", r, g, b, a)
+	}
+	if a != 0xffff {
+		t.Errorf("red rotated by 2π/3: alpha: got: %d, want: %d", a, 0xffff)
+	}
+}
+
+func TestColorMChangeSaturation(t *testing.T) {
+	mono := ColorM{}
+	mono.ChangeHSV(0, 0, 1)
+
+	cases := []struct {
+		Scale float64
+		Want  ColorM
+	}{
+		{0, mono},
+		{1, ColorM{}},
+	}
+	for _, c := range cases {
+		m := ColorM{}
+		m.ChangeSaturation(c.Scale)
+		for i := 0; i < ColorMDim-1; i++ {
+			for j := 0; j < ColorMDim; j++ {
+				got := m.Element(i, j)
+				want := c.Want.Element(i, j)
+				if math.Abs(want-got) > 0.0001 {
+					t.Errorf("scale: %f, m.Element(%d, %d) = %f, want %f", c.Scale, i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestColorMChangeContrast(t *testing.T) {
+	cases := []struct {
+		Scale float64
+		In    color.RGBA
+		Out   color.RGBA
+	}{
+		{1, color.RGBA{0x10, 0x80, 0xf0, 0xff}, color.RGBA{0x10, 0x80, 0xf0, 0xff}},
+		{0, color.RGBA{0x10, 0x80, 0xf0, 0xff}, color.RGBA{0x80, 0x80, 0x80, 0xff}},
+		{2, color.RGBA{0x60, 0x80, 0xa0, 0xff}, color.RGBA{0x40, 0x80, 0xc0, 0xff}},
+	}
+	for _, c := range cases {
+		m := ColorM{}
+		m.ChangeContrast(c.Scale)
+		r0, g0, b0, a0 := m.Apply(c.In).RGBA()
+		r1, g1, b1, a1 := c.Out.RGBA()
+		const delta = 0x101
+		if absDiffU32(r0, r1) > delta || absDiffU32(g0, g1) > delta ||
+			absDiffU32(b0, b1) > delta || absDiffU32(a0, a1) > delta {
+			t.Errorf("scale: %f, Apply(%v) = {%d, %d, %d, %d}, want {%d, %d, %d, %d}", c.Scale, c.In, r0, g0, b0, a0, r1, g1, b1, a1)
+		}
+	}
+}
+
+func TestColorMConcatIdentityAndAssociativity(t *testing.T) {
+	var m0, m1, m2 ColorM
+	m0.ChangeSaturation(0.5)
+	m0.Translate(0.1, 0.2, 0.3, 0)
+	m1.RotateHue(1)
+	m2.Scale(0.5, 2, 1, 0.75)
+	m2.Translate(0.25, 0, -0.5, 0.125)
+
+	equal := func(a, b ColorM) bool {
+		for i := 0; i < ColorMDim-1; i++ {
+			for j := 0; j < ColorMDim; j++ {
+				if math.Abs(a.Element(i, j)-b.Element(i, j)) > 1e-5 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	for _, m := range []ColorM{m0, m1, m2} {
+		id := ColorM{}
+		id.Concat(m)
+		if !equal(id, m) {
+			t.Errorf("identity.Concat(%v): got: %v, want: %v", m, id, m)
+		}
+	}
+
+	// (m0 m1) m2
+	lhs := m0
+	lhs.Concat(m1)
+	lhs.Concat(m2)
+
+	// m0 (m1 m2)
+	m12 := m1
+	m12.Concat(m2)
+	rhs := m0
+	rhs.Concat(m12)
+
+	if !equal(lhs, rhs) {
+		t.Errorf("Concat must be associative: got: %v, want: %v", lhs, rhs)
+	}
+}
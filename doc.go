@@ -37,6 +37,12 @@
 //         ebiten.Run(update, 320, 240, 2, "Your game's title")
 //     }
 //
+// Ebiten doesn't have implicit render state like OpenGL's. All the state to render is specified at each
+// drawing call: the render target is the receiver of DrawImage or DrawTriangles, the clip rectangle is
+// specified by SubImage of the render target, and the composite mode, the color mask, the filter and the
+// address mode are the fields of DrawImageOptions or DrawTrianglesOptions. Then, there is no need to save
+// and restore render state: a function drawing with its own options never affects the other drawing calls.
+//
 // The EBITEN_SCREENSHOT_KEY environment variable specifies the key
 // to take a screenshot. For example, if you run your game with
 // `EBITEN_SCREENSHOT_KEY=q`, you can take a game screen's screenshot
@@ -46,6 +52,9 @@
 // to dump all the internal images. This is valid only when the build tag
 // 'ebitendebug' is specified. This works only on desktops.
 //
+// With the build tag 'ebitendebug', OpenGL errors after the main OpenGL operations like drawing and
+// replacing pixels are also logged. Without the build tag, the errors are not checked and this has no overhead.
+//
 // In the API document, 'the main thread' means the goroutine in init(), main() and their callees without 'go'
 // statement. It is assured that 'the main thread' runs on the OS main thread. There are some Ebiten functions that
 // must be called on the main thread under some conditions (typically, before ebiten.Run is called).
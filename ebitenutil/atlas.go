@@ -0,0 +1,143 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/internal/graphics"
+	"github.com/hajimehoshi/ebiten/internal/packing"
+)
+
+// Atlas packs images into shared images called pages.
+//
+// Ebiten already packs small images into internal textures automatically, so Atlas is not needed in most cases.
+// Atlas is useful when images must share the same source image explicitly, e.g., to render many sprites
+// with one DrawTriangles call.
+//
+// Drawing a sub-image of a page doesn't bleed the neighbor images even with FilterLinear, since the texels out of
+// the sub-image are treated as transparent. When the whole page is given to DrawTriangles as the source,
+// use a padding by NewAtlasWithOptions to avoid bleeding.
+type Atlas struct {
+	maxSize int
+	padding int
+	pages   []*atlasPage
+}
+
+// AtlasOptions represents options for NewAtlasWithOptions.
+type AtlasOptions struct {
+	// Padding is the width of the transparent gutter around each image in pixels.
+	// 1 is enough to avoid bleeding with FilterLinear.
+	//
+	// The default (zero) value is 0.
+	Padding int
+}
+
+type atlasPage struct {
+	image *ebiten.Image
+	page  *packing.Page
+}
+
+// NewAtlas creates a new Atlas. Each page is a maxSize x maxSize image.
+//
+// NewAtlas panics if maxSize is not positive.
+func NewAtlas(maxSize int) *Atlas {
+	return NewAtlasWithOptions(maxSize, nil)
+}
+
+// NewAtlasWithOptions creates a new Atlas with the given options. Each page is a maxSize x maxSize image.
+//
+// If options is nil, the default options are used.
+//
+// NewAtlasWithOptions panics if maxSize is not positive or the padding is negative.
+func NewAtlasWithOptions(maxSize int, options *AtlasOptions) *Atlas {
+	if maxSize <= 0 {
+		panic(fmt.Sprintf("ebitenutil: maxSize must be positive but %d", maxSize))
+	}
+	padding := 0
+	if options != nil {
+		padding = options.Padding
+	}
+	if padding < 0 {
+		panic(fmt.Sprintf("ebitenutil: padding must not be negative but %d", padding))
+	}
+	return &Atlas{
+		maxSize: maxSize,
+		padding: padding,
+	}
+}
+
+// Add copies img into a page and returns the page and the region of img in the page.
+//
+// A sub-image of the page with the region, page.SubImage(region), can be used as a regular image.
+// The region can also be used for the source coordinates of DrawTriangles.
+//
+// If img doesn't fit in the existing pages, Add creates a new page.
+// Add returns an error if img is empty or larger than the page size including the padding.
+func (a *Atlas) Add(img image.Image) (*ebiten.Image, image.Rectangle, error) {
+	size := img.Bounds().Size()
+	if size.X <= 0 || size.Y <= 0 {
+		return nil, image.ZR, fmt.Errorf("ebitenutil: the image must not be empty but %dx%d", size.X, size.Y)
+	}
+	w, h := size.X+2*a.padding, size.Y+2*a.padding
+	if w > a.maxSize || h > a.maxSize {
+		return nil, image.ZR, fmt.Errorf("ebitenutil: the image (%dx%d) with the padding is larger than the atlas page (%dx%d)", size.X, size.Y, a.maxSize, a.maxSize)
+	}
+
+	var p *atlasPage
+	var n *packing.Node
+	for _, page := range a.pages {
+		if n = page.page.Alloc(w, h); n != nil {
+			p = page
+			break
+		}
+	}
+	if n == nil {
+		// A new image is cleared, then the padding is transparent.
+		i, err := ebiten.NewImage(a.maxSize, a.maxSize, ebiten.FilterDefault)
+		if err != nil {
+			return nil, image.ZR, err
+		}
+		p = &atlasPage{
+			image: i,
+			page:  packing.NewPage(a.maxSize, a.maxSize),
+		}
+		a.pages = append(a.pages, p)
+		n = p.page.Alloc(w, h)
+		if n == nil {
+			panic("ebitenutil: the image must fit in a new page")
+		}
+	}
+
+	x, y, _, _ := n.Region()
+	x += a.padding
+	y += a.padding
+	r := image.Rect(x, y, x+size.X, y+size.Y)
+	if err := p.image.SubImage(r).(*ebiten.Image).ReplacePixels(graphics.CopyImage(img)); err != nil {
+		return nil, image.ZR, err
+	}
+	return p.image, r, nil
+}
+
+// Pages returns the pages of the atlas.
+func (a *Atlas) Pages() []*ebiten.Image {
+	ps := make([]*ebiten.Image, 0, len(a.pages))
+	for _, p := range a.pages {
+		ps = append(ps, p.image)
+	}
+	return ps
+}
@@ -0,0 +1,207 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+	. "github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+func TestAtlas(t *testing.T) {
+	const size = 64
+
+	a := NewAtlas(size)
+	type entry struct {
+		page   *ebiten.Image
+		region image.Rectangle
+	}
+	var entries []entry
+	for _, s := range []image.Point{
+		{32, 32}, {16, 48}, {48, 16}, {8, 8}, {64, 10}, {10, 64}, {1, 1}, {33, 33}, {20, 5},
+	} {
+		img := image.NewRGBA(image.Rect(0, 0, s.X, s.Y))
+		page, r, err := a.Add(img)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := r.Size(); got != s {
+			t.Errorf("region size: got: %v, want: %v", got, s)
+		}
+		if !r.In(image.Rect(0, 0, size, size)) {
+			t.Errorf("region %v must be in the page", r)
+		}
+		entries = append(entries, entry{page, r})
+	}
+
+	for i, e0 := range entries {
+		for _, e1 := range entries[i+1:] {
+			if e0.page != e1.page {
+				continue
+			}
+			if e0.region.Overlaps(e1.region) {
+				t.Errorf("regions must not overlap: %v and %v", e0.region, e1.region)
+			}
+		}
+	}
+
+	// The total area exceeds one page, then a new page must be created.
+	if got := len(a.Pages()); got < 2 {
+		t.Errorf("len(a.Pages()): got: %d, want: >= 2", got)
+	}
+}
+
+func TestAtlasTooLarge(t *testing.T) {
+	a := NewAtlas(16)
+	if _, _, err := a.Add(image.NewRGBA(image.Rect(0, 0, 17, 1))); err == nil {
+		t.Errorf("Add with a too large image must return an error")
+	}
+	if _, _, err := a.Add(image.NewRGBA(image.Rect(0, 0, 0, 0))); err == nil {
+		t.Errorf("Add with an empty image must return an error")
+	}
+	if got := len(a.Pages()); got != 0 {
+		t.Errorf("len(a.Pages()): got: %d, want: 0", got)
+	}
+}
+
+func TestAtlasPadding(t *testing.T) {
+	const (
+		size    = 32
+		padding = 1
+	)
+
+	a := NewAtlasWithOptions(size, &AtlasOptions{Padding: padding})
+	var regions []image.Rectangle
+	for i := 0; i < 6; i++ {
+		page, r, err := a.Add(image.NewRGBA(image.Rect(0, 0, 8, 8)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !r.In(image.Rect(padding, padding, size-padding, size-padding)) {
+			t.Errorf("region %v must be in the page without the padding", r)
+		}
+		if page != a.Pages()[0] {
+			t.Fatalf("six 10x10 cells must fit in one 32x32 page")
+		}
+		regions = append(regions, r)
+	}
+	for i, r0 := range regions {
+		for _, r1 := range regions[i+1:] {
+			if r0.Inset(-padding).Overlaps(r1) {
+				t.Errorf("regions must be separated by the padding: %v and %v", r0, r1)
+			}
+		}
+	}
+
+	// The image fits in the page but not with the padding.
+	if _, _, err := a.Add(image.NewRGBA(image.Rect(0, 0, size, 1))); err == nil {
+		t.Errorf("Add with an image larger than the page including the padding must return an error")
+	}
+}
+
+func TestAtlasCellWithoutBleeding(t *testing.T) {
+	const (
+		cellSize = 8
+		scale    = 4
+	)
+
+	// Pack two cells side by side without any padding.
+	a := NewAtlas(cellSize * 2)
+	red := image.NewRGBA(image.Rect(0, 0, cellSize, cellSize))
+	green := image.NewRGBA(image.Rect(0, 0, cellSize, cellSize))
+	for j := 0; j < cellSize; j++ {
+		for i := 0; i < cellSize; i++ {
+			red.Set(i, j, color.RGBA{0xff, 0, 0, 0xff})
+			green.Set(i, j, color.RGBA{0, 0xff, 0, 0xff})
+		}
+	}
+	page, r, err := a.Add(red)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := a.Add(green); err != nil {
+		t.Fatal(err)
+	}
+
+	dst, _ := ebiten.NewImage(cellSize*scale, cellSize*scale, ebiten.FilterDefault)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(scale, scale)
+	op.Filter = ebiten.FilterLinear
+	dst.DrawImage(page.SubImage(r).(*ebiten.Image), op)
+
+	for j := 0; j < cellSize*scale; j++ {
+		for i := 0; i < cellSize*scale; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			if got.G != 0 {
+				t.Errorf("dst.At(%d, %d): got: %v, want: no green", i, j, got)
+			}
+		}
+	}
+	if got, want := dst.At(cellSize*scale/2, cellSize*scale/2), (color.RGBA{0xff, 0, 0, 0xff}); got != want {
+		t.Errorf("dst.At(center): got: %v, want: %v", got, want)
+	}
+}
+
+func TestAtlasPageWithPadding(t *testing.T) {
+	const (
+		cellSize = 8
+		scale    = 4
+	)
+
+	a := NewAtlasWithOptions(cellSize*4, &AtlasOptions{Padding: 1})
+	var page *ebiten.Image
+	var r image.Rectangle
+	for _, clr := range []color.RGBA{{0xff, 0, 0, 0xff}, {0, 0xff, 0, 0xff}, {0, 0xff, 0, 0xff}, {0, 0xff, 0, 0xff}} {
+		img := image.NewRGBA(image.Rect(0, 0, cellSize, cellSize))
+		for j := 0; j < cellSize; j++ {
+			for i := 0; i < cellSize; i++ {
+				img.Set(i, j, clr)
+			}
+		}
+		p, rr, err := a.Add(img)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page == nil {
+			page, r = p, rr
+		}
+	}
+
+	// Give the whole page to DrawTriangles. The padding prevents the neighbors from bleeding.
+	dst, _ := ebiten.NewImage(cellSize*scale, cellSize*scale, ebiten.FilterDefault)
+	sx0, sy0, sx1, sy1 := float32(r.Min.X), float32(r.Min.Y), float32(r.Max.X), float32(r.Max.Y)
+	const d = cellSize * scale
+	vs := []ebiten.Vertex{
+		{DstX: 0, DstY: 0, SrcX: sx0, SrcY: sy0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: d, DstY: 0, SrcX: sx1, SrcY: sy0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 0, DstY: d, SrcX: sx0, SrcY: sy1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: d, DstY: d, SrcX: sx1, SrcY: sy1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+	}
+	dst.DrawTriangles(vs, []uint16{0, 1, 2, 1, 2, 3}, page, &ebiten.DrawTrianglesOptions{
+		Filter: ebiten.FilterLinear,
+	})
+
+	for j := 0; j < d; j++ {
+		for i := 0; i < d; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			if got.G != 0 {
+				t.Errorf("dst.At(%d, %d): got: %v, want: no green", i, j, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// BitmapFont is a font whose glyphs are laid out in a grid on an image, so-called a font sheet.
+//
+// All the glyphs have the same size. The glyph for a rune r is at the index r - firstRune
+// on the sheet, counted from left to right and then top to bottom.
+type BitmapFont struct {
+	image       *ebiten.Image
+	glyphWidth  int
+	glyphHeight int
+	firstRune   rune
+	columns     int
+	glyphNum    int
+
+	fallback    rune
+	hasFallback bool
+}
+
+// NewBitmapFont returns a new BitmapFont with the font sheet img.
+//
+// firstRune is the rune of the glyph at the top left corner of img.
+//
+// NewBitmapFont panics if glyphWidth or glyphHeight is not positive.
+func NewBitmapFont(img *ebiten.Image, glyphWidth, glyphHeight int, firstRune rune) *BitmapFont {
+	w, h := img.Size()
+	f := newBitmapFont(w, h, glyphWidth, glyphHeight, firstRune)
+	f.image = img
+	return f
+}
+
+func newBitmapFont(sheetWidth, sheetHeight int, glyphWidth, glyphHeight int, firstRune rune) *BitmapFont {
+	if glyphWidth <= 0 || glyphHeight <= 0 {
+		panic("ebitenutil: glyphWidth and glyphHeight must be positive")
+	}
+	columns := sheetWidth / glyphWidth
+	return &BitmapFont{
+		glyphWidth:  glyphWidth,
+		glyphHeight: glyphHeight,
+		firstRune:   firstRune,
+		columns:     columns,
+		glyphNum:    columns * (sheetHeight / glyphHeight),
+	}
+}
+
+// GlyphSize returns the size of a glyph.
+func (f *BitmapFont) GlyphSize() (width, height int) {
+	return f.glyphWidth, f.glyphHeight
+}
+
+// SetFallbackRune sets the rune whose glyph is drawn instead of runes that are not on the font sheet.
+//
+// By default, nothing is drawn for such runes, but the position still advances.
+func (f *BitmapFont) SetFallbackRune(r rune) {
+	f.fallback = r
+	f.hasFallback = true
+}
+
+// glyphBounds returns the bounds of the glyph for r on the font sheet.
+// glyphBounds returns false if neither r nor the fallback rune is on the font sheet.
+func (f *BitmapFont) glyphBounds(r rune) (image.Rectangle, bool) {
+	if b, ok := f.glyphBoundsWithoutFallback(r); ok {
+		return b, true
+	}
+	if f.hasFallback {
+		return f.glyphBoundsWithoutFallback(f.fallback)
+	}
+	return image.Rectangle{}, false
+}
+
+func (f *BitmapFont) glyphBoundsWithoutFallback(r rune) (image.Rectangle, bool) {
+	i := int(r - f.firstRune)
+	if r < f.firstRune || i >= f.glyphNum {
+		return image.Rectangle{}, false
+	}
+	x := (i % f.columns) * f.glyphWidth
+	y := (i / f.columns) * f.glyphHeight
+	return image.Rect(x, y, x+f.glyphWidth, y+f.glyphHeight), true
+}
+
+type bitmapGlyph struct {
+	// src is the bounds on the font sheet.
+	src image.Rectangle
+
+	// x and y are the position to draw the glyph at, relative to the origin of the text.
+	x int
+	y int
+}
+
+// glyphs returns the glyphs to draw str.
+//
+// '\n' moves the position to the beginning of the next line.
+func (f *BitmapFont) glyphs(str string) []bitmapGlyph {
+	var gs []bitmapGlyph
+	x, y := 0, 0
+	for _, r := range str {
+		if r == '\n' {
+			x = 0
+			y += f.glyphHeight
+			continue
+		}
+		if b, ok := f.glyphBounds(r); ok {
+			gs = append(gs, bitmapGlyph{
+				src: b,
+				x:   x,
+				y:   y,
+			})
+		}
+		x += f.glyphWidth
+	}
+	return gs
+}
+
+func drawBitmapText(dst *ebiten.Image, font *BitmapFont, str string, x, y float64, colorm *ebiten.ColorM) {
+	op := &ebiten.DrawImageOptions{}
+	if colorm != nil {
+		op.ColorM = *colorm
+	}
+	for _, g := range font.glyphs(str) {
+		op.GeoM.Reset()
+		op.GeoM.Translate(x+float64(g.x), y+float64(g.y))
+		_ = dst.DrawImage(font.image.SubImage(g.src).(*ebiten.Image), op)
+	}
+}
+
+// DrawText draws str with the bitmap font at (x, y) on the given destination dst.
+//
+// (x, y) is the top left corner of the first glyph.
+// Each glyph advances the position by the glyph width, and '\n' moves the position to the beginning of the next line.
+// The glyphs are scaled by clr: a white glyph is drawn in clr.
+//
+// DrawText is intended to be used mainly for debugging or prototyping purpose.
+func DrawText(dst *ebiten.Image, font *BitmapFont, str string, x, y float64, clr color.Color) {
+	var colorm ebiten.ColorM
+	colorm.Scale(colorScale(clr))
+	drawBitmapText(dst, font, str, x, y, &colorm)
+}
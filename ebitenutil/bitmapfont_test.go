@@ -0,0 +1,60 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestBitmapFontGlyphs(t *testing.T) {
+	// The sheet has 4x2 glyphs from 'A' to 'H'.
+	f := newBitmapFont(32, 20, 8, 10, 'A')
+
+	got := f.glyphs("AF?\nH")
+	want := []bitmapGlyph{
+		{src: image.Rect(0, 0, 8, 10), x: 0, y: 0},
+		{src: image.Rect(8, 10, 16, 20), x: 8, y: 0},
+		// '?' is not on the sheet, and the position just advances.
+		{src: image.Rect(24, 10, 32, 20), x: 0, y: 10},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("glyphs: got: %v, want: %v", got, want)
+	}
+
+	f.SetFallbackRune('D')
+	got = f.glyphs("AI\nB")
+	want = []bitmapGlyph{
+		{src: image.Rect(0, 0, 8, 10), x: 0, y: 0},
+		// 'I' is out of the sheet and the fallback glyph is used instead.
+		{src: image.Rect(24, 0, 32, 10), x: 8, y: 0},
+		{src: image.Rect(8, 0, 16, 10), x: 0, y: 10},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("glyphs with fallback: got: %v, want: %v", got, want)
+	}
+}
+
+func TestBitmapFontPartialSheet(t *testing.T) {
+	// The parts of the sheet that cannot contain a whole glyph are ignored.
+	f := newBitmapFont(20, 15, 8, 10, 0)
+	if _, ok := f.glyphBounds(1); !ok {
+		t.Errorf("glyphBounds(1): got: false, want: true")
+	}
+	if _, ok := f.glyphBounds(2); ok {
+		t.Errorf("glyphBounds(2): got: true, want: false")
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Capture reads back the pixels of img and returns them as a straight-alpha (non-premultiplied) image.
+//
+// img can be any image including the screen image passed to the update function, and a sub-image.
+// The returned image's bounds are same as img's bounds, and its origin is at the upper-left corner
+// regardless of the graphics driver.
+//
+// Capture reads pixels from GPU via (*ebiten.Image).At, which means that Capture can be slow.
+// Capture can't be called before the main loop (ebiten.Run) starts.
+func Capture(img *ebiten.Image) *image.NRGBA {
+	b := img.Bounds()
+	dst := image.NewNRGBA(b)
+	for j := b.Min.Y; j < b.Max.Y; j++ {
+		for i := b.Min.X; i < b.Max.X; i++ {
+			dst.SetNRGBA(i, j, color.NRGBAModel.Convert(img.At(i, j)).(color.NRGBA))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,48 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+	. "github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+func TestCapture(t *testing.T) {
+	const w, h = 16, 16
+	img, _ := ebiten.NewImage(w, h, ebiten.FilterDefault)
+	img.Fill(color.RGBA{0x40, 0x20, 0x10, 0x80})
+	DrawRect(img, 0, 0, w, 1, color.RGBA{0xff, 0, 0, 0xff})
+
+	got := Capture(img)
+	if got.Bounds() != img.Bounds() {
+		t.Errorf("bounds: got: %v, want: %v", got.Bounds(), img.Bounds())
+	}
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			// The top row is red, which shows that the captured image is not upside-down.
+			want := color.NRGBA{0x80, 0x40, 0x20, 0x80}
+			if j == 0 {
+				want = color.NRGBA{0xff, 0, 0, 0xff}
+			}
+			c := got.NRGBAAt(i, j)
+			if !sameColors(color.RGBA(c), color.RGBA(want), 1) {
+				t.Errorf("Capture(img).At(%d, %d): got: %v, want: %v", i, j, c, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"image"
+	"math"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+var (
+	// crossfadeImage is the intermediate image for the mix.
+	// crossfadeImage is extended when a larger image is given, and is never shrunk.
+	// A sub-image of it is used for a smaller image so that drawing images in various sizes doesn't
+	// reallocate the intermediate image every time.
+	crossfadeImage  *ebiten.Image
+	crossfadeImageM sync.Mutex
+)
+
+// DrawImageCrossfade draws the mix of the two images a and b on dst.
+// factor is the ratio of b: 0 means only a is drawn and 1 means only b is drawn.
+//
+// The result is the linear interpolation of the premultiplied colors of a and b,
+// so crossfading between translucent images works as expected.
+//
+// The mix has the same size as a, and b is stretched to a's size when the sizes differ.
+// In other words, the images are mapped by their relative coordinates: the upper-left and the lower-right
+// corners of b's bounds are mapped to a's. Sub-images are treated with their bounds.
+//
+// op is applied to the mix. GeoM, ColorM, CompositeMode and Filter of op are used. op can be nil.
+// Filter of op is also used to stretch b.
+//
+// The mix takes two passes through an intermediate image: a and b are mixed on the intermediate image first,
+// and then the intermediate image is drawn on dst. The intermediate image is shared among the calls.
+// The mix can't be done in one pass since a drawing call, including one with a user-defined shader,
+// samples only one source image. Drawing a and b directly on dst one by one would blend b with
+// the existing colors of dst, and op's ColorM and CompositeMode would not be applied to the mix as a whole.
+//
+// If factor is out of the range [0, 1] or is NaN, DrawImageCrossfade panics.
+//
+// DrawImageCrossfade is concurrent-safe.
+func DrawImageCrossfade(dst, a, b *ebiten.Image, factor float64, op *ebiten.DrawImageOptions) {
+	if math.IsNaN(factor) || factor < 0 || factor > 1 {
+		panic("ebitenutil: factor must be in [0, 1]")
+	}
+	if op == nil {
+		op = &ebiten.DrawImageOptions{}
+	}
+
+	crossfadeImageM.Lock()
+	defer crossfadeImageM.Unlock()
+
+	aw, ah := a.Size()
+	if crossfadeImage != nil {
+		if w, h := crossfadeImage.Size(); w < aw || h < ah {
+			// Keep the larger size so that the previous sizes can still use the new image.
+			if w < aw {
+				w = aw
+			}
+			if h < ah {
+				h = ah
+			}
+			_ = crossfadeImage.Dispose()
+			crossfadeImage, _ = ebiten.NewImage(w, h, ebiten.FilterDefault)
+		}
+	}
+	if crossfadeImage == nil {
+		crossfadeImage, _ = ebiten.NewImage(aw, ah, ebiten.FilterDefault)
+	}
+	mix := crossfadeImage.SubImage(image.Rect(0, 0, aw, ah)).(*ebiten.Image)
+
+	// Scaling alpha scales all the premultiplied color values.
+	opa := &ebiten.DrawImageOptions{}
+	opa.ColorM.Scale(1, 1, 1, 1-factor)
+	opa.CompositeMode = ebiten.CompositeModeCopy
+	_ = mix.DrawImage(a, opa)
+
+	bw, bh := b.Size()
+	opb := &ebiten.DrawImageOptions{}
+	opb.GeoM.Scale(float64(aw)/float64(bw), float64(ah)/float64(bh))
+	opb.ColorM.Scale(1, 1, 1, factor)
+	opb.CompositeMode = ebiten.CompositeModeLighter
+	opb.Filter = op.Filter
+	_ = mix.DrawImage(b, opb)
+
+	_ = dst.DrawImage(mix, op)
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil_test
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+	. "github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+func TestDrawImageCrossfade(t *testing.T) {
+	const w, h = 16, 16
+	red, _ := ebiten.NewImage(w, h, ebiten.FilterDefault)
+	red.Fill(color.RGBA{0xff, 0, 0, 0xff})
+	// blue has a different size and is translucent.
+	blue, _ := ebiten.NewImage(w/2, h/2, ebiten.FilterDefault)
+	blue.Fill(color.RGBA{0, 0, 0x80, 0x80})
+
+	for _, c := range []struct {
+		Factor float64
+		Want   color.RGBA
+	}{
+		{0, color.RGBA{0xff, 0, 0, 0xff}},
+		{0.5, color.RGBA{0x80, 0, 0x40, 0xc0}},
+		{1, color.RGBA{0, 0, 0x80, 0x80}},
+	} {
+		dst, _ := ebiten.NewImage(w, h, ebiten.FilterDefault)
+		DrawImageCrossfade(dst, red, blue, c.Factor, nil)
+		for _, p := range []image.Point{{0, 0}, {w - 1, h - 1}} {
+			got := dst.At(p.X, p.Y).(color.RGBA)
+			if !sameColors(got, c.Want, 2) {
+				t.Errorf("factor: %f, dst.At(%d, %d): got: %v, want: %v", c.Factor, p.X, p.Y, got, c.Want)
+			}
+		}
+	}
+}
+
+func TestDrawImageCrossfadeInvalidFactor(t *testing.T) {
+	a, _ := ebiten.NewImage(4, 4, ebiten.FilterDefault)
+	b, _ := ebiten.NewImage(4, 4, ebiten.FilterDefault)
+	dst, _ := ebiten.NewImage(4, 4, ebiten.FilterDefault)
+	for _, f := range []float64{-0.1, 1.1, math.NaN(), math.Inf(1)} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("DrawImageCrossfade with factor %v must panic", f)
+				}
+			}()
+			DrawImageCrossfade(dst, a, b, f, nil)
+		}()
+	}
+}
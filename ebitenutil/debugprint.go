@@ -15,19 +15,18 @@
 package ebitenutil
 
 import (
-	"image"
-
 	"github.com/hajimehoshi/ebiten"
 	"github.com/hajimehoshi/ebiten/ebitenutil/internal/assets"
 )
 
 var (
-	debugPrintTextImage *ebiten.Image
+	debugPrintFont *BitmapFont
 )
 
 func init() {
 	img := assets.CreateTextImage()
-	debugPrintTextImage, _ = ebiten.NewImageFromImage(img, ebiten.FilterDefault)
+	textImage, _ := ebiten.NewImageFromImage(img, ebiten.FilterDefault)
+	debugPrintFont = NewBitmapFont(textImage, assets.CharWidth, assets.CharHeight, 0)
 }
 
 // DebugPrint draws the string str on the image on left top corner.
@@ -49,30 +48,10 @@ func DebugPrintAt(image *ebiten.Image, str string, x, y int) {
 }
 
 func drawDebugText(rt *ebiten.Image, str string, ox, oy int, shadow bool) {
-	op := &ebiten.DrawImageOptions{}
+	var colorm *ebiten.ColorM
 	if shadow {
-		op.ColorM.Scale(0, 0, 0, 0.5)
-	}
-	x := 0
-	y := 0
-	w, _ := debugPrintTextImage.Size()
-	for _, c := range str {
-		const (
-			cw = assets.CharWidth
-			ch = assets.CharHeight
-		)
-		if c == '\n' {
-			x = 0
-			y += ch
-			continue
-		}
-		n := w / cw
-		sx := (int(c) % n) * cw
-		sy := (int(c) / n) * ch
-		op.GeoM.Reset()
-		op.GeoM.Translate(float64(x), float64(y))
-		op.GeoM.Translate(float64(ox+1), float64(oy))
-		_ = rt.DrawImage(debugPrintTextImage.SubImage(image.Rect(sx, sy, sx+cw, sy+ch)).(*ebiten.Image), op)
-		x += cw
+		colorm = &ebiten.ColorM{}
+		colorm.Scale(0, 0, 0, 0.5)
 	}
+	drawBitmapText(rt, debugPrintFont, str, float64(ox+1), float64(oy), colorm)
 }
@@ -0,0 +1,61 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil_test
+
+import (
+	"errors"
+	"image/color"
+	"os"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/internal/testflock"
+)
+
+func TestMain(m *testing.M) {
+	testflock.Lock()
+	defer testflock.Unlock()
+
+	code := 0
+	// Run an Ebiten process so that (*Image).At is available.
+	regularTermination := errors.New("regular termination")
+	f := func(screen *ebiten.Image) error {
+		code = m.Run()
+		return regularTermination
+	}
+	if err := ebiten.Run(f, 320, 240, 1, "Test"); err != nil && err != regularTermination {
+		panic(err)
+	}
+	os.Exit(code)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// sameColors compares c1 and c2 and returns a boolean value indicating
+// if the two colors are (almost) same.
+//
+// Pixels read from GPU might include errors (#492), and
+// sameColors considers such errors as delta.
+func sameColors(c1, c2 color.RGBA, delta int) bool {
+	return abs(int(c1.R)-int(c2.R)) <= delta &&
+		abs(int(c1.G)-int(c2.G)) <= delta &&
+		abs(int(c1.B)-int(c2.B)) <= delta &&
+		abs(int(c1.A)-int(c2.A)) <= delta
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// DrawImageWithVerticalGradient draws src on dst with the geometry matrix geoM.
+// src is tinted with the gradient from top to bottom.
+//
+// The colors are applied as color scales to each vertex, like ColorM.Scale.
+// This means that a white image is drawn as the gradient itself.
+func DrawImageWithVerticalGradient(dst, src *ebiten.Image, geoM ebiten.GeoM, top, bottom color.Color) {
+	drawImageWithGradient(dst, src, geoM, top, top, bottom, bottom)
+}
+
+// DrawImageWithHorizontalGradient draws src on dst with the geometry matrix geoM.
+// src is tinted with the gradient from left to right.
+//
+// The colors are applied as color scales to each vertex, like ColorM.Scale.
+// This means that a white image is drawn as the gradient itself.
+func DrawImageWithHorizontalGradient(dst, src *ebiten.Image, geoM ebiten.GeoM, left, right color.Color) {
+	drawImageWithGradient(dst, src, geoM, left, right, left, right)
+}
+
+func drawImageWithGradient(dst, src *ebiten.Image, geoM ebiten.GeoM, topLeft, topRight, bottomLeft, bottomRight color.Color) {
+	bounds := src.Bounds()
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
+
+	vs := make([]ebiten.Vertex, 4)
+	for i, c := range []struct {
+		x, y float64
+		clr  color.Color
+	}{
+		{0, 0, topLeft},
+		{w, 0, topRight},
+		{0, h, bottomLeft},
+		{w, h, bottomRight},
+	} {
+		x, y := geoM.Apply(c.x, c.y)
+		cr, cg, cb, ca := colorScale(c.clr)
+		vs[i] = ebiten.Vertex{
+			DstX:   float32(x),
+			DstY:   float32(y),
+			SrcX:   float32(bounds.Min.X) + float32(c.x),
+			SrcY:   float32(bounds.Min.Y) + float32(c.y),
+			ColorR: float32(cr),
+			ColorG: float32(cg),
+			ColorB: float32(cb),
+			ColorA: float32(ca),
+		}
+	}
+	dst.DrawTriangles(vs, []uint16{0, 1, 2, 1, 2, 3}, src, nil)
+}
@@ -0,0 +1,44 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+	. "github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+func TestDrawImageWithVerticalGradient(t *testing.T) {
+	const w, h = 16, 16
+	src, _ := ebiten.NewImage(w, h, ebiten.FilterDefault)
+	dst, _ := ebiten.NewImage(w, h, ebiten.FilterDefault)
+	src.Fill(color.White)
+
+	DrawImageWithVerticalGradient(dst, src, ebiten.GeoM{}, color.Black, color.White)
+
+	top := dst.At(w/2, 0).(color.RGBA)
+	if want := (color.RGBA{0, 0, 0, 0xff}); !sameColors(top, want, 0x10) {
+		t.Errorf("dst.At(%d, %d): got: %v, want: %v", w/2, 0, top, want)
+	}
+	bottom := dst.At(w/2, h-1).(color.RGBA)
+	if want := (color.RGBA{0xff, 0xff, 0xff, 0xff}); !sameColors(bottom, want, 0x10) {
+		t.Errorf("dst.At(%d, %d): got: %v, want: %v", w/2, h-1, bottom, want)
+	}
+	if top.R >= bottom.R {
+		t.Errorf("the top color %v must be darker than the bottom color %v", top, bottom)
+	}
+}
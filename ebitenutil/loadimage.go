@@ -20,6 +20,8 @@ package ebitenutil
 
 import (
 	"image"
+	"io/ioutil"
+	"path/filepath"
 
 	"github.com/hajimehoshi/ebiten"
 )
@@ -51,3 +53,45 @@ func NewImageFromFile(path string, filter ebiten.Filter) (*ebiten.Image, image.I
 	}
 	return img2, img, err
 }
+
+// NewImagesFromDir loads all the image files in the directory dir and returns ebiten.Images keyed by
+// their file names (e.g., "player.png").
+//
+// Image decoders must be imported when using NewImagesFromDir, as NewImageFromFile requires.
+//
+// Sub-directories are not loaded. The files are loaded in the lexical order of their names.
+// Files that are not images, or whose formats' decoders are not imported, are skipped silently.
+//
+// When some files fail to be loaded, NewImagesFromDir returns the successfully loaded images
+// with a *LoadImagesError that has all the errors.
+//
+// As NewImagesFromDir requires listing a directory, this doesn't work on browsers and mobiles.
+func NewImagesFromDir(dir string, filter ebiten.Filter) (map[string]*ebiten.Image, error) {
+	// ioutil.ReadDir returns the entries sorted by their names.
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	imgs := map[string]*ebiten.Image{}
+	errs := map[string]error{}
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, info.Name())
+		img, _, err := NewImageFromFile(path, filter)
+		if err == image.ErrFormat {
+			continue
+		}
+		if err != nil {
+			errs[path] = err
+			continue
+		}
+		imgs[info.Name()] = img
+	}
+	if len(errs) > 0 {
+		return imgs, &LoadImagesError{Errs: errs}
+	}
+	return imgs, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build go1.16
+
+package ebitenutil
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io/fs"
+	"path"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// NewImageFromFS loads the file with path from the file system fsys and returns ebiten.Image and image.Image.
+//
+// fsys can be any fs.FS, e.g., embed.FS with the go:embed directive. Unlike NewImageFromFile,
+// this works on any environment including browsers and mobiles, since the file system is given explicitly.
+//
+// Image decoders must be imported when using NewImageFromFS, as NewImageFromFile requires.
+//
+// The returned error includes path. When the file doesn't exist, errors.Is(err, fs.ErrNotExist) reports true.
+func NewImageFromFS(fsys fs.FS, path string, filter ebiten.Filter) (*ebiten.Image, image.Image, error) {
+	file, err := fsys.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ebitenutil: opening %s failed: %w", path, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ebitenutil: decoding %s failed: %w", path, err)
+	}
+	img2, err := ebiten.NewImageFromImage(img, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	return img2, img, nil
+}
+
+// NewImagesFromFS loads all the image files in the directory dir of the file system fsys and returns ebiten.Images
+// keyed by their file names (e.g., "player.png").
+//
+// Image decoders must be imported when using NewImagesFromFS, as NewImageFromFile requires.
+//
+// Sub-directories are not loaded. The files are loaded in the lexical order of their names.
+// Files that are not images, or whose formats' decoders are not imported, are skipped silently.
+//
+// When some files fail to be loaded, NewImagesFromFS returns the successfully loaded images
+// with a *LoadImagesError that has all the errors keyed by the paths in fsys.
+//
+// Unlike NewImagesFromDir, this works on any environment including browsers and mobiles.
+func NewImagesFromFS(fsys fs.FS, dir string, filter ebiten.Filter) (map[string]*ebiten.Image, error) {
+	// fs.ReadDir returns the entries sorted by their names.
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	imgs := map[string]*ebiten.Image{}
+	errs := map[string]error{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		p := path.Join(dir, entry.Name())
+		img, _, err := NewImageFromFS(fsys, p, filter)
+		if errors.Is(err, image.ErrFormat) {
+			continue
+		}
+		if err != nil {
+			errs[p] = err
+			continue
+		}
+		imgs[entry.Name()] = img
+	}
+	if len(errs) > 0 {
+		return imgs, &LoadImagesError{Errs: errs}
+	}
+	return imgs, nil
+}
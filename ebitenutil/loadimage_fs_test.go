@@ -0,0 +1,70 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build go1.16
+
+package ebitenutil_test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+	"testing/fstest"
+
+	"github.com/hajimehoshi/ebiten"
+	. "github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+func TestNewImagesFromFS(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 2))); err != nil {
+		t.Fatal(err)
+	}
+
+	fsys := fstest.MapFS{
+		"images/a.png":      {Data: buf.Bytes()},
+		"images/b.png":      {Data: buf.Bytes()},
+		"images/readme.txt": {Data: []byte("not an image")},
+		"images/broken.png": {Data: buf.Bytes()[:16]},
+		"images/sub/c.png":  {Data: buf.Bytes()},
+	}
+
+	imgs, err := NewImagesFromFS(fsys, "images", ebiten.FilterDefault)
+	var lerr *LoadImagesError
+	if !errors.As(err, &lerr) {
+		t.Fatalf("err: got: %v, want: *LoadImagesError", err)
+	}
+	if got, want := len(lerr.Errs), 1; got != want {
+		t.Errorf("len(Errs): got: %d, want: %d", got, want)
+	}
+	if _, ok := lerr.Errs["images/broken.png"]; !ok {
+		t.Errorf("Errs must have images/broken.png: %v", lerr)
+	}
+
+	if got, want := len(imgs), 2; got != want {
+		t.Errorf("len(imgs): got: %d, want: %d", got, want)
+	}
+	for _, name := range []string{"a.png", "b.png"} {
+		img, ok := imgs[name]
+		if !ok {
+			t.Errorf("imgs must have %s", name)
+			continue
+		}
+		if w, h := img.Size(); w != 4 || h != 2 {
+			t.Errorf("%s size: got: (%d, %d), want: (4, 2)", name, w, h)
+		}
+	}
+}
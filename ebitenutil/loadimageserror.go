@@ -0,0 +1,41 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"sort"
+	"strings"
+)
+
+// LoadImagesError represents errors that happen at NewImagesFromDir or NewImagesFromFS.
+type LoadImagesError struct {
+	// Errs is the errors keyed by the file paths.
+	Errs map[string]error
+}
+
+// Error implements the error interface.
+func (e *LoadImagesError) Error() string {
+	var paths []string
+	for p := range e.Errs {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var lines []string
+	for _, p := range paths {
+		lines = append(lines, p+": "+e.Errs[p].Error())
+	}
+	return "ebitenutil: loading images failed:\n" + strings.Join(lines, "\n")
+}
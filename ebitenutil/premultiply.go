@@ -0,0 +1,67 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"image"
+)
+
+// Premultiply multiplies the color values of img by the alpha values in place.
+//
+// Premultiply assumes that img's pixels have straight-alpha (non-premultiplied) color values,
+// though image.RGBA is premultiplied by convention. After Premultiply, img can be used as
+// the pixels for Ebiten, e.g., for (*ebiten.Image).ReplacePixels, whose shaders expect premultiplied colors.
+func Premultiply(img *image.RGBA) {
+	forEachPixel(img, func(p []byte) {
+		a := uint32(p[3])
+		for i := 0; i < 3; i++ {
+			// Round to the nearest.
+			p[i] = byte((uint32(p[i])*a + 127) / 255)
+		}
+	})
+}
+
+// Unpremultiply divides the color values of img by the alpha values in place.
+//
+// After Unpremultiply, img has straight-alpha (non-premultiplied) color values.
+// The color values of a pixel with zero alpha are 0. The color values more than alpha are clamped to 255.
+func Unpremultiply(img *image.RGBA) {
+	forEachPixel(img, func(p []byte) {
+		a := uint32(p[3])
+		if a == 0 {
+			p[0], p[1], p[2] = 0, 0, 0
+			return
+		}
+		for i := 0; i < 3; i++ {
+			c := uint32(p[i])
+			if c >= a {
+				p[i] = 0xff
+				continue
+			}
+			// Round to the nearest.
+			p[i] = byte((c*255 + a/2) / a)
+		}
+	})
+}
+
+func forEachPixel(img *image.RGBA, f func(p []byte)) {
+	b := img.Rect
+	for j := b.Min.Y; j < b.Max.Y; j++ {
+		for i := b.Min.X; i < b.Max.X; i++ {
+			idx := img.PixOffset(i, j)
+			f(img.Pix[idx : idx+4])
+		}
+	}
+}
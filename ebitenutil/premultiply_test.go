@@ -0,0 +1,103 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+func TestPremultiply(t *testing.T) {
+	cases := []struct {
+		In  color.RGBA
+		Out color.RGBA
+	}{
+		{color.RGBA{0xff, 0xff, 0xff, 0xff}, color.RGBA{0xff, 0xff, 0xff, 0xff}},
+		{color.RGBA{0xff, 0x80, 0x00, 0x80}, color.RGBA{0x80, 0x40, 0x00, 0x80}},
+		{color.RGBA{0x40, 0xc0, 0xff, 0x40}, color.RGBA{0x10, 0x30, 0x40, 0x40}},
+		{color.RGBA{0xff, 0xff, 0xff, 0x00}, color.RGBA{0x00, 0x00, 0x00, 0x00}},
+	}
+	for _, c := range cases {
+		img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+		img.SetRGBA(0, 0, c.In)
+		Premultiply(img)
+		if got := img.RGBAAt(0, 0); got != c.Out {
+			t.Errorf("Premultiply(%v): got: %v, want: %v", c.In, got, c.Out)
+		}
+	}
+}
+
+func TestUnpremultiply(t *testing.T) {
+	cases := []struct {
+		In  color.RGBA
+		Out color.RGBA
+	}{
+		{color.RGBA{0xff, 0xff, 0xff, 0xff}, color.RGBA{0xff, 0xff, 0xff, 0xff}},
+		{color.RGBA{0x80, 0x40, 0x00, 0x80}, color.RGBA{0xff, 0x80, 0x00, 0x80}},
+		// Zero alpha must not cause division by zero.
+		{color.RGBA{0x00, 0x00, 0x00, 0x00}, color.RGBA{0x00, 0x00, 0x00, 0x00}},
+		{color.RGBA{0x10, 0x20, 0x30, 0x00}, color.RGBA{0x00, 0x00, 0x00, 0x00}},
+		// Invalid premultiplied colors are clamped.
+		{color.RGBA{0xff, 0x81, 0x40, 0x80}, color.RGBA{0xff, 0xff, 0x80, 0x80}},
+	}
+	for _, c := range cases {
+		img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+		img.SetRGBA(0, 0, c.In)
+		Unpremultiply(img)
+		if got := img.RGBAAt(0, 0); got != c.Out {
+			t.Errorf("Unpremultiply(%v): got: %v, want: %v", c.In, got, c.Out)
+		}
+	}
+}
+
+func TestPremultiplyRoundTrip(t *testing.T) {
+	// Premultiplying and unpremultiplying loses precision with small alpha values.
+	// With an opaque or half-transparent alpha, the error is at most 1.
+	for _, a := range []uint8{0xff, 0xc0, 0x80} {
+		for _, v := range []uint8{0x00, 0x01, 0x33, 0x80, 0xcd, 0xfe, 0xff} {
+			img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+			in := color.RGBA{v, 0xff - v, v / 2, a}
+			img.SetRGBA(0, 0, in)
+			Premultiply(img)
+			Unpremultiply(img)
+			got := img.RGBAAt(0, 0)
+			for i, pair := range [][2]uint8{{got.R, in.R}, {got.G, in.G}, {got.B, in.B}, {got.A, in.A}} {
+				d := int(pair[0]) - int(pair[1])
+				if d < -1 || 1 < d {
+					t.Errorf("round trip of %v: got: %v (component %d)", in, got, i)
+				}
+			}
+		}
+	}
+}
+
+func TestPremultiplySubImage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	for j := 0; j < 2; j++ {
+		for i := 0; i < 2; i++ {
+			img.SetRGBA(i, j, color.RGBA{0xff, 0xff, 0xff, 0x80})
+		}
+	}
+	Premultiply(img.SubImage(image.Rect(1, 1, 2, 2)).(*image.RGBA))
+	if got, want := img.RGBAAt(0, 0), (color.RGBA{0xff, 0xff, 0xff, 0x80}); got != want {
+		t.Errorf("img.RGBAAt(0, 0): got: %v, want: %v", got, want)
+	}
+	if got, want := img.RGBAAt(1, 1), (color.RGBA{0x80, 0x80, 0x80, 0x80}); got != want {
+		t.Errorf("img.RGBAAt(1, 1): got: %v, want: %v", got, want)
+	}
+}
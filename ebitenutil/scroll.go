@@ -0,0 +1,63 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// DrawImageScrolled draws src on dst with the texture coordinates shifted by (offsetX, offsetY).
+//
+// The geometry is not changed by the offset. The sampled region is shifted and wraps around
+// the source image, so increasing the offset continuously scrolls the image seamlessly.
+// This is useful for effects like flowing water or conveyor belts.
+//
+// GeoM, ColorM, CompositeMode and Filter of op are used. op can be nil.
+func DrawImageScrolled(dst, src *ebiten.Image, offsetX, offsetY float64, op *ebiten.DrawImageOptions) {
+	if op == nil {
+		op = &ebiten.DrawImageOptions{}
+	}
+
+	b := src.Bounds()
+	w, h := float64(b.Dx()), float64(b.Dy())
+
+	// Wrap the offsets into the image size before converting them to float32. Otherwise, a large offset
+	// that grows every frame loses the fractional part and the scrolling stutters.
+	offsetX = math.Mod(offsetX, w)
+	offsetY = math.Mod(offsetY, h)
+
+	vs := make([]ebiten.Vertex, 4)
+	for i, p := range [][2]float64{{0, 0}, {w, 0}, {0, h}, {w, h}} {
+		x, y := op.GeoM.Apply(p[0], p[1])
+		vs[i] = ebiten.Vertex{
+			DstX:   float32(x),
+			DstY:   float32(y),
+			SrcX:   float32(float64(b.Min.X) + p[0] + offsetX),
+			SrcY:   float32(float64(b.Min.Y) + p[1] + offsetY),
+			ColorR: 1,
+			ColorG: 1,
+			ColorB: 1,
+			ColorA: 1,
+		}
+	}
+	dst.DrawTriangles(vs, []uint16{0, 1, 2, 1, 2, 3}, src, &ebiten.DrawTrianglesOptions{
+		ColorM:        op.ColorM,
+		CompositeMode: op.CompositeMode,
+		Filter:        op.Filter,
+		Address:       ebiten.AddressRepeat,
+	})
+}
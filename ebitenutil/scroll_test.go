@@ -0,0 +1,73 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+	. "github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+func TestDrawImageScrolled(t *testing.T) {
+	const w, h = 4, 1
+	src, _ := ebiten.NewImage(w, h, ebiten.FilterDefault)
+	pix := make([]byte, 4*w*h)
+	for i := 0; i < w; i++ {
+		pix[4*i] = byte(0x40 * i)
+		pix[4*i+3] = 0xff
+	}
+	src.ReplacePixels(pix)
+	dst, _ := ebiten.NewImage(w, h, ebiten.FilterDefault)
+
+	for offset := 0; offset < 3*w; offset++ {
+		dst.Clear()
+		DrawImageScrolled(dst, src, float64(offset), 0, nil)
+		for i := 0; i < w; i++ {
+			got := dst.At(i, 0)
+			want := color.RGBA{byte(0x40 * ((i + offset) % w)), 0, 0, 0xff}
+			if got != want {
+				t.Errorf("offset: %d, dst.At(%d, 0): got: %v, want: %v", offset, i, got, want)
+			}
+		}
+	}
+}
+
+func TestDrawImageScrolledLargeOffset(t *testing.T) {
+	const w, h = 4, 1
+	src, _ := ebiten.NewImage(w, h, ebiten.FilterDefault)
+	pix := make([]byte, 4*w*h)
+	for i := 0; i < w; i++ {
+		pix[4*i] = byte(0x40 * i)
+		pix[4*i+3] = 0xff
+	}
+	src.ReplacePixels(pix)
+	dst, _ := ebiten.NewImage(w, h, ebiten.FilterDefault)
+
+	// float32 cannot represent these offsets exactly. The offsets must be wrapped before the conversion.
+	const base = 1 << 30
+	for _, offset := range []int{base + 1, base + 2, base + 3, -base - 1} {
+		dst.Clear()
+		DrawImageScrolled(dst, src, float64(offset), 0, nil)
+		for i := 0; i < w; i++ {
+			got := dst.At(i, 0)
+			want := color.RGBA{byte(0x40 * (((i+offset)%w + w) % w)), 0, 0, 0xff}
+			if got != want {
+				t.Errorf("offset: %d, dst.At(%d, 0): got: %v, want: %v", offset, i, got, want)
+			}
+		}
+	}
+}
@@ -15,19 +15,36 @@
 package ebitenutil
 
 import (
+	"fmt"
+	"image"
 	"image/color"
 	"math"
+	"sync"
 
 	"github.com/hajimehoshi/ebiten"
 )
 
 var (
-	emptyImage *ebiten.Image
+	whiteImage     *ebiten.Image
+	whiteImageOnce sync.Once
 )
 
-func init() {
-	emptyImage, _ = ebiten.NewImage(16, 16, ebiten.FilterDefault)
-	_ = emptyImage.Fill(color.White)
+// WhiteImage returns a shared image filled with opaque white.
+//
+// WhiteImage is useful to draw solid shapes: scale the image with GeoM, or specify it as the source
+// of DrawTriangles, and tint it with ColorM or the vertices' colors.
+// The image is created at the first call and the same image is returned afterwards.
+//
+// The image is created with FilterNearest. The image is 16x16 rather than 1x1 so that
+// the pixels around the edges are solid even when the image is stretched or its edges are sampled.
+//
+// The returned image must not be modified or disposed.
+func WhiteImage() *ebiten.Image {
+	whiteImageOnce.Do(func() {
+		whiteImage, _ = ebiten.NewImage(16, 16, ebiten.FilterNearest)
+		_ = whiteImage.Fill(color.White)
+	})
+	return whiteImage
 }
 
 func colorScale(clr color.Color) (rf, gf, bf, af float64) {
@@ -47,7 +64,7 @@ func colorScale(clr color.Color) (rf, gf, bf, af float64) {
 //
 // DrawLine is intended to be used mainly for debugging or prototyping purpose.
 func DrawLine(dst *ebiten.Image, x1, y1, x2, y2 float64, clr color.Color) {
-	ew, eh := emptyImage.Size()
+	ew, eh := WhiteImage().Size()
 	length := math.Hypot(x2-x1, y2-y1)
 
 	op := &ebiten.DrawImageOptions{}
@@ -57,14 +74,43 @@ func DrawLine(dst *ebiten.Image, x1, y1, x2, y2 float64, clr color.Color) {
 	op.ColorM.Scale(colorScale(clr))
 	// Filter must be 'nearest' filter (default).
 	// Linear filtering would make edges blurred.
-	_ = dst.DrawImage(emptyImage, op)
+	_ = dst.DrawImage(WhiteImage(), op)
+}
+
+// DrawThickLine draws a line segment with the given width on the given destination dst.
+//
+// The line is centered on the segment from (x1, y1) to (x2, y2), and its ends are square-cut at the end points.
+// DrawThickLine draws nothing if the segment's length or width is 0.
+//
+// DrawThickLine is intended to be used mainly for debugging or prototyping purpose.
+func DrawThickLine(dst *ebiten.Image, x1, y1, x2, y2 float64, width float64, clr color.Color) {
+	length := math.Hypot(x2-x1, y2-y1)
+	if length == 0 || width <= 0 {
+		return
+	}
+
+	ew, eh := WhiteImage().Size()
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(length/float64(ew), width/float64(eh))
+	op.GeoM.Translate(0, -width/2)
+	op.GeoM.Rotate(math.Atan2(y2-y1, x2-x1))
+	op.GeoM.Translate(x1, y1)
+	op.ColorM.Scale(colorScale(clr))
+	_ = dst.DrawImage(WhiteImage(), op)
 }
 
 // DrawRect draws a rectangle on the given destination dst.
 //
+// DrawRect draws nothing if width or height is not positive.
+//
 // DrawRect is intended to be used mainly for debugging or prototyping purpose.
 func DrawRect(dst *ebiten.Image, x, y, width, height float64, clr color.Color) {
-	ew, eh := emptyImage.Size()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	ew, eh := WhiteImage().Size()
 
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Scale(width/float64(ew), height/float64(eh))
@@ -72,5 +118,150 @@ func DrawRect(dst *ebiten.Image, x, y, width, height float64, clr color.Color) {
 	op.ColorM.Scale(colorScale(clr))
 	// Filter must be 'nearest' filter (default).
 	// Linear filtering would make edges blurred.
-	_ = dst.DrawImage(emptyImage, op)
+	_ = dst.DrawImage(WhiteImage(), op)
+}
+
+// DrawRectOutline draws the outline of a rectangle with the given line width on the given destination dst.
+//
+// The outline is drawn inside the rectangle, and is composed of four edges that do not overlap each other,
+// so a translucent color is applied only once at the corners.
+// If the line width is large enough to cover the rectangle, the whole rectangle is filled.
+// DrawRectOutline draws nothing if width, height or lineWidth is not positive.
+//
+// DrawRectOutline is intended to be used mainly for debugging or prototyping purpose.
+func DrawRectOutline(dst *ebiten.Image, x, y, width, height, lineWidth float64, clr color.Color) {
+	if width <= 0 || height <= 0 || lineWidth <= 0 {
+		return
+	}
+	if 2*lineWidth >= width || 2*lineWidth >= height {
+		DrawRect(dst, x, y, width, height, clr)
+		return
+	}
+
+	// Top and bottom edges
+	DrawRect(dst, x, y, width, lineWidth, clr)
+	DrawRect(dst, x, y+height-lineWidth, width, lineWidth, clr)
+	// Left and right edges
+	DrawRect(dst, x, y+lineWidth, lineWidth, height-2*lineWidth, clr)
+	DrawRect(dst, x+width-lineWidth, y+lineWidth, lineWidth, height-2*lineWidth, clr)
+}
+
+const (
+	minCircleSegmentNum = 8
+	maxCircleSegmentNum = 256
+)
+
+// circleSegmentNum returns the default number of segments for a circle with the given radius.
+// Each segment is about 2 pixels long on the circumference.
+func circleSegmentNum(radius float64) int {
+	n := int(math.Ceil(math.Pi * radius))
+	if n < minCircleSegmentNum {
+		return minCircleSegmentNum
+	}
+	if n > maxCircleSegmentNum {
+		return maxCircleSegmentNum
+	}
+	return n
+}
+
+func shapeVertex(x, y float64, clr color.Color) ebiten.Vertex {
+	r, g, b, a := colorScale(clr)
+	return ebiten.Vertex{
+		DstX:   float32(x),
+		DstY:   float32(y),
+		SrcX:   1,
+		SrcY:   1,
+		ColorR: float32(r),
+		ColorG: float32(g),
+		ColorB: float32(b),
+		ColorA: float32(a),
+	}
+}
+
+const (
+	// MaxCircleSegmentNum is the maximum number of segments for DrawCircleWithSegments.
+	// A circle with n segments needs 3n indices, which must not exceed ebiten.MaxIndicesNum.
+	MaxCircleSegmentNum = ebiten.MaxIndicesNum / 3
+
+	// MaxPolygonPointNum is the maximum number of points for DrawPolygon.
+	// A polygon with n points needs 3(n-2) indices, which must not exceed ebiten.MaxIndicesNum.
+	MaxPolygonPointNum = ebiten.MaxIndicesNum/3 + 2
+)
+
+// circleTriangles returns the vertices and the indices of a circle triangulated as a fan around its center.
+func circleTriangles(cx, cy, radius float64, segments int, clr color.Color) ([]ebiten.Vertex, []uint16) {
+	vs := make([]ebiten.Vertex, 0, segments+1)
+	vs = append(vs, shapeVertex(cx, cy, clr))
+	for i := 0; i < segments; i++ {
+		theta := 2 * math.Pi * float64(i) / float64(segments)
+		vs = append(vs, shapeVertex(cx+radius*math.Cos(theta), cy+radius*math.Sin(theta), clr))
+	}
+
+	is := make([]uint16, 0, 3*segments)
+	for i := 0; i < segments; i++ {
+		is = append(is, 0, uint16(i+1), uint16((i+1)%segments+1))
+	}
+	return vs, is
+}
+
+// polygonTriangles returns the vertices and the indices of a polygon triangulated as a fan around its first point.
+func polygonTriangles(points []image.Point, clr color.Color) ([]ebiten.Vertex, []uint16) {
+	vs := make([]ebiten.Vertex, 0, len(points))
+	for _, p := range points {
+		vs = append(vs, shapeVertex(float64(p.X), float64(p.Y), clr))
+	}
+
+	is := make([]uint16, 0, 3*(len(points)-2))
+	for i := 1; i < len(points)-1; i++ {
+		is = append(is, 0, uint16(i), uint16(i+1))
+	}
+	return vs, is
+}
+
+// DrawCircle draws a filled circle on the given destination dst.
+//
+// The number of segments to approximate the circle is determined by the radius.
+// DrawCircle draws nothing if radius is not positive.
+//
+// DrawCircle is intended to be used mainly for debugging or prototyping purpose.
+func DrawCircle(dst *ebiten.Image, cx, cy, radius float64, clr color.Color) {
+	DrawCircleWithSegments(dst, cx, cy, radius, circleSegmentNum(radius), clr)
+}
+
+// DrawCircleWithSegments draws a filled circle approximated by a regular polygon
+// with the given number of segments on the given destination dst.
+//
+// segments must be at least 3 and at most MaxCircleSegmentNum.
+// DrawCircleWithSegments draws nothing if radius is not positive.
+//
+// DrawCircleWithSegments is intended to be used mainly for debugging or prototyping purpose.
+func DrawCircleWithSegments(dst *ebiten.Image, cx, cy, radius float64, segments int, clr color.Color) {
+	if segments < 3 || segments > MaxCircleSegmentNum {
+		panic(fmt.Sprintf("ebitenutil: segments must be >= 3 and <= %d", MaxCircleSegmentNum))
+	}
+	if radius <= 0 {
+		return
+	}
+	vs, is := circleTriangles(cx, cy, radius, segments, clr)
+	dst.DrawTriangles(vs, is, WhiteImage(), nil)
+}
+
+// DrawPolygon draws a filled polygon on the given destination dst.
+//
+// The polygon is triangulated as a fan around the first point, which assumes the polygon is convex.
+// A concave polygon might be drawn incorrectly: the parts outside of the polygon might be filled,
+// and the overlapping parts of a translucent polygon might be drawn more than once.
+// DrawPolygon draws nothing if the number of points is less than 3.
+// The number of points must be at most MaxPolygonPointNum.
+//
+// DrawPolygon is intended to be used mainly for debugging or prototyping purpose.
+func DrawPolygon(dst *ebiten.Image, points []image.Point, clr color.Color) {
+	if len(points) < 3 {
+		return
+	}
+	if len(points) > MaxPolygonPointNum {
+		panic(fmt.Sprintf("ebitenutil: the number of points must be <= %d", MaxPolygonPointNum))
+	}
+	vs, is := polygonTriangles(points, clr)
+	dst.DrawTriangles(vs, is, WhiteImage(), nil)
 }
@@ -0,0 +1,228 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+func TestCircleSegmentNum(t *testing.T) {
+	cases := []struct {
+		Radius float64
+		Want   int
+	}{
+		{0.5, minCircleSegmentNum},
+		{1, minCircleSegmentNum},
+		{10, 32},
+		{20, 63},
+		{1000, maxCircleSegmentNum},
+	}
+	for _, c := range cases {
+		if got := circleSegmentNum(c.Radius); got != c.Want {
+			t.Errorf("circleSegmentNum(%v): got: %d, want: %d", c.Radius, got, c.Want)
+		}
+	}
+
+	// The number of segments must not decrease as the radius increases.
+	prev := 0
+	for r := 0.0; r < 200; r += 0.5 {
+		n := circleSegmentNum(r)
+		if n < prev {
+			t.Errorf("circleSegmentNum(%v): got: %d, want: >= %d", r, n, prev)
+		}
+		prev = n
+	}
+}
+
+func TestCircleTriangles(t *testing.T) {
+	for _, segments := range []int{3, 8, 64} {
+		vs, is := circleTriangles(10, 20, 5, segments, color.White)
+		if got, want := len(vs), segments+1; got != want {
+			t.Errorf("len(vertices) with %d segments: got: %d, want: %d", segments, got, want)
+		}
+		if got, want := len(is), 3*segments; got != want {
+			t.Errorf("len(indices) with %d segments: got: %d, want: %d", segments, got, want)
+		}
+		for _, i := range is {
+			if int(i) >= len(vs) {
+				t.Errorf("index with %d segments: got: %d, want: < %d", segments, i, len(vs))
+			}
+		}
+		if vs[0].DstX != 10 || vs[0].DstY != 20 {
+			t.Errorf("center with %d segments: got: (%v, %v), want: (10, 20)", segments, vs[0].DstX, vs[0].DstY)
+		}
+	}
+}
+
+func TestPolygonTriangles(t *testing.T) {
+	points := []image.Point{
+		image.Pt(0, 0),
+		image.Pt(10, 0),
+		image.Pt(15, 5),
+		image.Pt(10, 10),
+		image.Pt(0, 10),
+	}
+	vs, is := polygonTriangles(points, color.White)
+	if got, want := len(vs), len(points); got != want {
+		t.Errorf("len(vertices): got: %d, want: %d", got, want)
+	}
+	want := []uint16{0, 1, 2, 0, 2, 3, 0, 3, 4}
+	if len(is) != len(want) {
+		t.Fatalf("len(indices): got: %d, want: %d", len(is), len(want))
+	}
+	for i := range is {
+		if is[i] != want[i] {
+			t.Errorf("indices[%d]: got: %d, want: %d", i, is[i], want[i])
+		}
+	}
+}
+
+func TestShapeIndicesLimit(t *testing.T) {
+	_, is := circleTriangles(0, 0, 1, MaxCircleSegmentNum, color.White)
+	if len(is) > ebiten.MaxIndicesNum {
+		t.Errorf("len(indices) with MaxCircleSegmentNum segments: got: %d, want: <= %d", len(is), ebiten.MaxIndicesNum)
+	}
+	_, is = circleTriangles(0, 0, 1, MaxCircleSegmentNum+1, color.White)
+	if len(is) <= ebiten.MaxIndicesNum {
+		t.Errorf("len(indices) with MaxCircleSegmentNum+1 segments: got: %d, want: > %d", len(is), ebiten.MaxIndicesNum)
+	}
+
+	points := make([]image.Point, MaxPolygonPointNum+1)
+	_, is = polygonTriangles(points[:MaxPolygonPointNum], color.White)
+	if len(is) > ebiten.MaxIndicesNum {
+		t.Errorf("len(indices) with MaxPolygonPointNum points: got: %d, want: <= %d", len(is), ebiten.MaxIndicesNum)
+	}
+	_, is = polygonTriangles(points, color.White)
+	if len(is) <= ebiten.MaxIndicesNum {
+		t.Errorf("len(indices) with MaxPolygonPointNum+1 points: got: %d, want: > %d", len(is), ebiten.MaxIndicesNum)
+	}
+
+	// Too many points or segments must be rejected before DrawTriangles is called.
+	for _, f := range []func(){
+		func() { DrawPolygon(nil, points, color.White) },
+		func() { DrawCircleWithSegments(nil, 0, 0, 1, MaxCircleSegmentNum+1, color.White) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("too many points or segments must panic")
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestDrawThickLine(t *testing.T) {
+	const w, h = 16, 16
+	dst, _ := ebiten.NewImage(w, h, ebiten.FilterDefault)
+
+	// A zero-length line draws nothing.
+	DrawThickLine(dst, 4, 4, 4, 4, 3, color.White)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+
+	// A horizontal line with width 3 covers 3 rows centered on the segment.
+	DrawThickLine(dst, 2, 8.5, 14, 8.5, 3, color.White)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{}
+			if 2 <= i && i < 14 && 7 <= j && j < 10 {
+				want = color.RGBA{0xff, 0xff, 0xff, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestDrawRectOutline(t *testing.T) {
+	const w, h = 16, 16
+	dst, _ := ebiten.NewImage(w, h, ebiten.FilterDefault)
+
+	// Empty and negative rectangles draw nothing.
+	DrawRect(dst, 4, 4, 0, 4, color.White)
+	DrawRect(dst, 4, 4, -4, 4, color.White)
+	DrawRectOutline(dst, 4, 4, 4, -4, 1, color.White)
+	DrawRectOutline(dst, 4, 4, 4, 4, 0, color.White)
+
+	// Use a translucent color to check the edges don't overlap each other.
+	clr := color.RGBA{0x40, 0x40, 0x40, 0x40}
+	const (
+		x         = 2
+		y         = 3
+		rw        = 12
+		rh        = 10
+		lineWidth = 2
+	)
+	DrawRectOutline(dst, x, y, rw, rh, lineWidth, clr)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{}
+			inRect := x <= i && i < x+rw && y <= j && j < y+rh
+			inInner := x+lineWidth <= i && i < x+rw-lineWidth && y+lineWidth <= j && j < y+rh-lineWidth
+			if inRect && !inInner {
+				want = clr
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestWhiteImage(t *testing.T) {
+	const w, h = 16, 16
+	dst, _ := ebiten.NewImage(w, h, ebiten.FilterDefault)
+
+	// Stretch the white image to a quad at (2, 3)-(12, 14) and tint it red.
+	white := WhiteImage()
+	if WhiteImage() != white {
+		t.Errorf("WhiteImage() must return the same image")
+	}
+	sw, sh := white.Size()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(10/float64(sw), 11/float64(sh))
+	op.GeoM.Translate(2, 3)
+	op.ColorM.Scale(1, 0, 0, 1)
+	dst.DrawImage(white, op)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{}
+			if 2 <= i && i < 12 && 3 <= j && j < 14 {
+				want = color.RGBA{0xff, 0, 0, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
@@ -107,13 +107,15 @@ func update(screen *ebiten.Image) error {
 		return nil
 	}
 
-	const ox, oy = 40, 60
-	drawRect(screen, ebitenImage, ox, oy, 200, 100, ebiten.AddressClampToZero, "Regular")
-	drawRect(screen, ebitenImage, 220+ox, oy, 200, 100, ebiten.AddressRepeat, "Regular, Repeat")
+	const ox, oy = 30, 60
+	drawRect(screen, ebitenImage, ox, oy, 180, 100, ebiten.AddressClampToZero, "Regular")
+	drawRect(screen, ebitenImage, 200+ox, oy, 180, 100, ebiten.AddressRepeat, "Regular, Repeat")
+	drawRect(screen, ebitenImage, 400+ox, oy, 180, 100, ebiten.AddressMirroredRepeat, "Regular, Mirrored Repeat")
 
 	subImage := ebitenImage.SubImage(image.Rect(10, 5, 20, 30)).(*ebiten.Image)
-	drawRect(screen, subImage, ox, 200+oy, 200, 100, ebiten.AddressClampToZero, "Subimage")
-	drawRect(screen, subImage, 220+ox, 200+oy, 200, 100, ebiten.AddressRepeat, "Subimage, Repeat")
+	drawRect(screen, subImage, ox, 200+oy, 180, 100, ebiten.AddressClampToZero, "Subimage")
+	drawRect(screen, subImage, 200+ox, 200+oy, 180, 100, ebiten.AddressRepeat, "Subimage, Repeat")
+	drawRect(screen, subImage, 400+ox, 200+oy, 180, 100, ebiten.AddressMirroredRepeat, "Subimage, Mirrored Repeat")
 	return nil
 }
 
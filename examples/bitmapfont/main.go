@@ -0,0 +1,91 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build example jsgo
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+
+	"github.com/hajimehoshi/bitmapfont"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+const (
+	screenWidth  = 320
+	screenHeight = 240
+
+	glyphWidth  = 6
+	glyphHeight = 16
+	sheetCols   = 16
+	firstRune   = ' '
+	lastRune    = '~'
+)
+
+var sheetFont *ebitenutil.BitmapFont
+
+// createSheet renders the ASCII printable runes into a font sheet.
+// Usually a font sheet is an image file made by hand or a tool.
+func createSheet() *ebiten.Image {
+	n := lastRune - firstRune + 1
+	rows := (int(n) + sheetCols - 1) / sheetCols
+	img := image.NewRGBA(image.Rect(0, 0, sheetCols*glyphWidth, rows*glyphHeight))
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.White,
+		Face: bitmapfont.Gothic12r,
+	}
+	ascent := bitmapfont.Gothic12r.Metrics().Ascent.Ceil()
+	for r := rune(firstRune); r <= lastRune; r++ {
+		i := int(r - firstRune)
+		x := (i % sheetCols) * glyphWidth
+		y := (i / sheetCols) * glyphHeight
+		d.Dot = fixed.P(x, y+ascent)
+		d.DrawString(string(r))
+	}
+	sheet, _ := ebiten.NewImageFromImage(img, ebiten.FilterDefault)
+	return sheet
+}
+
+func init() {
+	sheetFont = ebitenutil.NewBitmapFont(createSheet(), glyphWidth, glyphHeight, firstRune)
+	// Runes that are not on the sheet are drawn as '?'.
+	sheetFont.SetFallbackRune('?')
+}
+
+func update(screen *ebiten.Image) error {
+	if ebiten.IsDrawingSkipped() {
+		return nil
+	}
+
+	msg := fmt.Sprintf("FPS: %0.2f\nTPS: %0.2f", ebiten.CurrentFPS(), ebiten.CurrentTPS())
+	ebitenutil.DrawText(screen, sheetFont, msg, 8, 8, color.White)
+	ebitenutil.DrawText(screen, sheetFont, "Bitmap fonts can be tinted.", 8, 56, color.RGBA{0xff, 0xc0, 0x40, 0xff})
+	ebitenutil.DrawText(screen, sheetFont, "Missing glyphs: あいう", 8, 80, color.RGBA{0x80, 0xc0, 0xff, 0xff})
+	return nil
+}
+
+func main() {
+	if err := ebiten.Run(update, screenWidth, screenHeight, 2, "Bitmap Font (Ebiten Demo)"); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build example jsgo
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	"log"
+	"math"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"github.com/hajimehoshi/ebiten/examples/resources/images"
+)
+
+const (
+	screenWidth  = 640
+	screenHeight = 240
+)
+
+// gammaShader is a user-defined shader that applies gamma correction.
+// Gamma correction is not an affine transformation and can't be represented as a ColorM.
+const gammaShader = `
+uniform float gamma_value;
+
+vec4 shade(vec4 color) {
+  return vec4(pow(color.rgb, vec3(1.0 / gamma_value)), color.a);
+}
+`
+
+var (
+	gophersImage *ebiten.Image
+	shader       *ebiten.Shader
+
+	count int
+)
+
+func init() {
+	img, _, err := image.Decode(bytes.NewReader(images.Gophers_jpg))
+	if err != nil {
+		log.Fatal(err)
+	}
+	gophersImage, _ = ebiten.NewImageFromImage(img, ebiten.FilterDefault)
+
+	shader, err = ebiten.NewShader(gammaShader)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func update(screen *ebiten.Image) error {
+	count++
+
+	if ebiten.IsDrawingSkipped() {
+		return nil
+	}
+
+	t := math.Sin(float64(count) / 60)
+	brightness := t / 2
+	gamma := math.Pow(2, t)
+
+	w, h := gophersImage.Size()
+
+	// Change the brightness with a color matrix.
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(screenWidth/2-w)/2, float64(screenHeight-h)/2)
+	op.ColorM.ChangeBrightness(brightness)
+	screen.DrawImage(gophersImage, op)
+
+	// Apply gamma correction with a user-defined shader.
+	op = &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(screenWidth/2)+float64(screenWidth/2-w)/2, float64(screenHeight-h)/2)
+	op.Shader = shader
+	op.Uniforms = ebiten.Uniforms{
+		"gamma_value": float32(gamma),
+	}
+	screen.DrawImage(gophersImage, op)
+
+	msg := fmt.Sprintf("Brightness: %+0.2f (ColorM)\nGamma: %0.2f (Shader)", brightness, gamma)
+	ebitenutil.DebugPrint(screen, msg)
+	return nil
+}
+
+func main() {
+	if err := ebiten.Run(update, screenWidth, screenHeight, 1, "Brightness and Gamma (Ebiten Demo)"); err != nil {
+		log.Fatal(err)
+	}
+}
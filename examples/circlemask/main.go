@@ -0,0 +1,90 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build example jsgo
+
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	"log"
+	"math"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"github.com/hajimehoshi/ebiten/examples/resources/images"
+)
+
+const (
+	screenWidth  = 320
+	screenHeight = 240
+)
+
+var (
+	bgImage    *ebiten.Image
+	fgImage    *ebiten.Image
+	layerImage *ebiten.Image
+
+	count int
+)
+
+func init() {
+	img, _, err := image.Decode(bytes.NewReader(images.Gophers_jpg))
+	if err != nil {
+		log.Fatal(err)
+	}
+	bgImage, _ = ebiten.NewImageFromImage(img, ebiten.FilterDefault)
+
+	img, _, err = image.Decode(bytes.NewReader(images.FiveYears_jpg))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fgImage, _ = ebiten.NewImageFromImage(img, ebiten.FilterDefault)
+
+	layerImage, _ = ebiten.NewImage(screenWidth, screenHeight, ebiten.FilterDefault)
+}
+
+func update(screen *ebiten.Image) error {
+	count++
+
+	if ebiten.IsDrawingSkipped() {
+		return nil
+	}
+
+	// Draw the mask shape to the layer. Any shapes can be used as a mask: only the alpha values matter.
+	layerImage.Clear()
+	x := screenWidth/2 + 80*math.Cos(float64(count)/60)
+	y := screenHeight/2 + 40*math.Sin(float64(count)/30)
+	ebitenutil.DrawCircle(layerImage, x, y, 64, color.White)
+
+	// Draw the foreground image with 'source-in' so that the image is drawn only inside the mask shape.
+	op := &ebiten.DrawImageOptions{}
+	op.CompositeMode = ebiten.CompositeModeSourceIn
+	layerImage.DrawImage(fgImage, op)
+
+	// The pixels outside of the mask shape on the layer are transparent, and the screen's pixels
+	// there are not changed.
+	screen.DrawImage(bgImage, nil)
+	screen.DrawImage(layerImage, nil)
+	return nil
+}
+
+func main() {
+	if err := ebiten.Run(update, screenWidth, screenHeight, 2, "Circle Mask (Ebiten Demo)"); err != nil {
+		log.Fatal(err)
+	}
+}
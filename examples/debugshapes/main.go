@@ -0,0 +1,80 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build example jsgo
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"math"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+const (
+	screenWidth  = 640
+	screenHeight = 480
+)
+
+var count = 0
+
+func update(screen *ebiten.Image) error {
+	count++
+	count %= 360
+
+	if ebiten.IsDrawingSkipped() {
+		return nil
+	}
+
+	// The number of segments is determined by the radius.
+	ebitenutil.DrawCircle(screen, 80, 120, 8, color.RGBA{0xff, 0x80, 0x80, 0xff})
+	ebitenutil.DrawCircle(screen, 160, 120, 32, color.RGBA{0x80, 0xff, 0x80, 0xff})
+	ebitenutil.DrawCircle(screen, 320, 120, 96, color.RGBA{0x80, 0x80, 0xff, 0x80})
+
+	// A circle with few segments is a regular polygon.
+	ebitenutil.DrawCircleWithSegments(screen, 520, 120, 64, 6, color.RGBA{0xff, 0xff, 0x80, 0xff})
+
+	// Rotating pentagon
+	const n = 5
+	theta := float64(count) * math.Pi / 180
+	points := make([]image.Point, 0, n)
+	for i := 0; i < n; i++ {
+		t := theta + 2*math.Pi*float64(i)/n
+		points = append(points, image.Pt(160+int(80*math.Cos(t)), 340+int(80*math.Sin(t))))
+	}
+	ebitenutil.DrawPolygon(screen, points, color.RGBA{0x80, 0xff, 0xff, 0xff})
+
+	// A concave polygon is not drawn correctly since it is triangulated as a fan.
+	ebitenutil.DrawPolygon(screen, []image.Point{
+		image.Pt(400, 260),
+		image.Pt(560, 260),
+		image.Pt(480, 340),
+		image.Pt(560, 420),
+		image.Pt(400, 420),
+	}, color.RGBA{0xff, 0x80, 0xff, 0x80})
+
+	ebitenutil.DebugPrint(screen, fmt.Sprintf("TPS: %0.2f", ebiten.CurrentTPS()))
+	return nil
+}
+
+func main() {
+	if err := ebiten.Run(update, screenWidth, screenHeight, 1, "Debug Shapes (Ebiten Demo)"); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build example jsgo
+
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+const (
+	screenWidth  = 640
+	screenHeight = 480
+	cellSize     = 32
+)
+
+var count = 0
+
+func update(screen *ebiten.Image) error {
+	count++
+	count %= cellSize
+
+	if ebiten.IsDrawingSkipped() {
+		return nil
+	}
+
+	gridColor := color.RGBA{0x40, 0x80, 0x40, 0xff}
+	for x := count % cellSize; x < screenWidth; x += cellSize {
+		ebitenutil.DrawThickLine(screen, float64(x), 0, float64(x), screenHeight, 1, gridColor)
+	}
+	for y := 0; y < screenHeight; y += cellSize {
+		ebitenutil.DrawThickLine(screen, 0, float64(y), screenWidth, float64(y), 1, gridColor)
+	}
+
+	// Axes are drawn with thicker lines.
+	axisColor := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	ebitenutil.DrawThickLine(screen, screenWidth/2, 0, screenWidth/2, screenHeight, 3, axisColor)
+	ebitenutil.DrawThickLine(screen, 0, screenHeight/2, screenWidth, screenHeight/2, 3, axisColor)
+
+	ebitenutil.DebugPrint(screen, fmt.Sprintf("TPS: %0.2f", ebiten.CurrentTPS()))
+	return nil
+}
+
+func main() {
+	if err := ebiten.Run(update, screenWidth, screenHeight, 1, "Grid (Ebiten Demo)"); err != nil {
+		log.Fatal(err)
+	}
+}
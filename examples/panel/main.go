@@ -0,0 +1,82 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build example jsgo
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+const (
+	screenWidth  = 320
+	screenHeight = 240
+)
+
+var (
+	panelRect   = image.Rect(40, 40, 280, 200)
+	buttonRects = []image.Rectangle{
+		image.Rect(60, 140, 150, 180),
+		image.Rect(170, 140, 260, 180),
+	}
+)
+
+func drawRect(dst *ebiten.Image, r image.Rectangle, clr color.Color) {
+	ebitenutil.DrawRect(dst, float64(r.Min.X), float64(r.Min.Y), float64(r.Dx()), float64(r.Dy()), clr)
+}
+
+func drawRectOutline(dst *ebiten.Image, r image.Rectangle, lineWidth float64, clr color.Color) {
+	ebitenutil.DrawRectOutline(dst, float64(r.Min.X), float64(r.Min.Y), float64(r.Dx()), float64(r.Dy()), lineWidth, clr)
+}
+
+func update(screen *ebiten.Image) error {
+	if ebiten.IsDrawingSkipped() {
+		return nil
+	}
+
+	screen.Fill(color.RGBA{0x20, 0x40, 0x60, 0xff})
+
+	// The panel is translucent so that the background is visible through it.
+	drawRect(screen, panelRect, color.RGBA{0, 0, 0, 0xc0})
+	drawRectOutline(screen, panelRect, 2, color.White)
+	ebitenutil.DebugPrintAt(screen, "Panel", panelRect.Min.X+8, panelRect.Min.Y+8)
+
+	x, y := ebiten.CursorPosition()
+	for i, r := range buttonRects {
+		if image.Pt(x, y).In(r) {
+			drawRect(screen, r, color.RGBA{0x80, 0x80, 0x80, 0xff})
+			drawRectOutline(screen, r, 3, color.RGBA{0xff, 0xff, 0x00, 0xff})
+		} else {
+			drawRect(screen, r, color.RGBA{0x40, 0x40, 0x40, 0xff})
+			drawRectOutline(screen, r, 1, color.White)
+		}
+		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Button %d", i+1), r.Min.X+8, r.Min.Y+12)
+	}
+
+	ebitenutil.DebugPrint(screen, fmt.Sprintf("TPS: %0.2f", ebiten.CurrentTPS()))
+	return nil
+}
+
+func main() {
+	if err := ebiten.Run(update, screenWidth, screenHeight, 2, "Panel (Ebiten Demo)"); err != nil {
+		log.Fatal(err)
+	}
+}
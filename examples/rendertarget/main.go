@@ -0,0 +1,91 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build example jsgo
+
+package main
+
+import (
+	"bytes"
+	"image"
+	_ "image/png"
+	"log"
+	"math"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"github.com/hajimehoshi/ebiten/examples/resources/images"
+)
+
+const (
+	screenWidth  = 320
+	screenHeight = 240
+)
+
+var (
+	ebitenImage *ebiten.Image
+
+	// Any image can be a render target. Draw the scene to this offscreen image first.
+	offscreen *ebiten.Image
+
+	count int
+)
+
+func init() {
+	img, _, err := image.Decode(bytes.NewReader(images.Ebiten_png))
+	if err != nil {
+		log.Fatal(err)
+	}
+	ebitenImage, _ = ebiten.NewImageFromImage(img, ebiten.FilterDefault)
+	offscreen, _ = ebiten.NewImage(screenWidth, screenHeight, ebiten.FilterDefault)
+}
+
+// drawScene draws the scene to dst. dst can be either the screen or an offscreen image.
+func drawScene(dst *ebiten.Image) {
+	w, h := ebitenImage.Size()
+	for i := 0; i < 8; i++ {
+		theta := 2*math.Pi*float64(i)/8 + float64(count)/60
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(-float64(w)/2, -float64(h)/2)
+		op.GeoM.Rotate(theta)
+		op.GeoM.Translate(screenWidth/2+80*math.Cos(theta), screenHeight/2+80*math.Sin(theta))
+		dst.DrawImage(ebitenImage, op)
+	}
+}
+
+func update(screen *ebiten.Image) error {
+	count++
+
+	if ebiten.IsDrawingSkipped() {
+		return nil
+	}
+
+	// The first pass: render the scene to the offscreen image.
+	offscreen.Clear()
+	drawScene(offscreen)
+
+	// The second pass: draw the offscreen image to the screen with a color matrix.
+	op := &ebiten.DrawImageOptions{}
+	op.ColorM.RotateHue(float64(count) / 30)
+	screen.DrawImage(offscreen, op)
+
+	ebitenutil.DebugPrint(screen, "The scene is rendered to an offscreen image,\nand then drawn with a color matrix.")
+	return nil
+}
+
+func main() {
+	if err := ebiten.Run(update, screenWidth, screenHeight, 2, "Render Target (Ebiten Demo)"); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build example jsgo
+
+package main
+
+import (
+	"bytes"
+	"image"
+	_ "image/jpeg"
+	"log"
+	"math"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/examples/resources/images"
+)
+
+const (
+	screenWidth  = 320
+	screenHeight = 240
+)
+
+var (
+	count        int
+	gophersImage *ebiten.Image
+	sepiaM       ebiten.ColorM
+)
+
+func init() {
+	sepiaM.Sepia()
+}
+
+// lerpColorM returns the matrix interpolated linearly between a and b.
+func lerpColorM(a, b *ebiten.ColorM, t float64) ebiten.ColorM {
+	var m ebiten.ColorM
+	for i := 0; i < ebiten.ColorMDim-1; i++ {
+		for j := 0; j < ebiten.ColorMDim; j++ {
+			m.SetElement(i, j, a.Element(i, j)*(1-t)+b.Element(i, j)*t)
+		}
+	}
+	return m
+}
+
+func update(screen *ebiten.Image) error {
+	count++
+
+	if ebiten.IsDrawingSkipped() {
+		return nil
+	}
+
+	// Center the image on the screen.
+	w, h := gophersImage.Size()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(screenWidth-w)/2, float64(screenHeight-h)/2)
+
+	// Animate the color between the original and the sepia tone.
+	t := (1 - math.Cos(float64(count%240)*2*math.Pi/240)) / 2
+	op.ColorM = lerpColorM(&ebiten.ColorM{}, &sepiaM, t)
+
+	screen.DrawImage(gophersImage, op)
+	return nil
+}
+
+func main() {
+	// Decode image from a byte slice instead of a file so that
+	// this example works in any working directory.
+	img, _, err := image.Decode(bytes.NewReader(images.Gophers_jpg))
+	if err != nil {
+		log.Fatal(err)
+	}
+	gophersImage, _ = ebiten.NewImageFromImage(img, ebiten.FilterDefault)
+
+	if err := ebiten.Run(update, screenWidth, screenHeight, 2, "Sepia (Ebiten Demo)"); err != nil {
+		log.Fatal(err)
+	}
+}
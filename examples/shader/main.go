@@ -0,0 +1,93 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build example jsgo
+
+package main
+
+import (
+	"bytes"
+	"image"
+	_ "image/jpeg"
+	"log"
+	"math"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"github.com/hajimehoshi/ebiten/examples/resources/images"
+)
+
+const (
+	screenWidth  = 320
+	screenHeight = 240
+)
+
+// grayscaleShader is a user-defined shader that converts colors to grayscale.
+// The uniform variable rate is the ratio of grayscale: 0 keeps the original colors, and 1 is fully gray.
+const grayscaleShader = `
+uniform float grayscale_rate;
+
+vec4 shade(vec4 color) {
+  float y = dot(color.rgb, vec3(0.299, 0.587, 0.114));
+  return vec4(mix(color.rgb, vec3(y), grayscale_rate), color.a);
+}
+`
+
+var (
+	gophersImage *ebiten.Image
+	shader       *ebiten.Shader
+
+	count int
+)
+
+func init() {
+	img, _, err := image.Decode(bytes.NewReader(images.Gophers_jpg))
+	if err != nil {
+		log.Fatal(err)
+	}
+	gophersImage, _ = ebiten.NewImageFromImage(img, ebiten.FilterDefault)
+
+	shader, err = ebiten.NewShader(grayscaleShader)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func update(screen *ebiten.Image) error {
+	count++
+
+	if ebiten.IsDrawingSkipped() {
+		return nil
+	}
+
+	rate := (1 - math.Cos(float64(count)/60)) / 2
+
+	w, h := gophersImage.Size()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(screenWidth-w)/2, float64(screenHeight-h)/2)
+	op.Shader = shader
+	op.Uniforms = ebiten.Uniforms{
+		"grayscale_rate": float32(rate),
+	}
+	screen.DrawImage(gophersImage, op)
+
+	ebitenutil.DebugPrint(screen, "The image is drawn with a user-defined\ngrayscale shader.")
+	return nil
+}
+
+func main() {
+	if err := ebiten.Run(update, screenWidth, screenHeight, 2, "Shader (Ebiten Demo)"); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"image"
+	"image/color"
+	"time"
+)
+
+// Exported for testing.
+var (
+	CompositeModesForTesting = compositeModes
+	PrewarmShadersForTesting = prewarmShaders
+
+	NewScaledVolatileImageForTesting = newScaledVolatileImage
+)
+
+// ContainsShaderForTesting reports whether the set of the shaders to prewarm includes s.
+func ContainsShaderForTesting(s *Shader) bool {
+	for _, s2 := range shaders() {
+		if s2 == s.shader {
+			return true
+		}
+	}
+	return false
+}
+
+func ShaderCountForTesting() int {
+	return len(shaders())
+}
+
+func (i *Image) PhysicalRectForTesting(r image.Rectangle) image.Rectangle {
+	return i.physicalRect(r)
+}
+
+type fakeWindowImpl struct {
+	closed       bool
+	presentCount int
+}
+
+func (w *fakeWindowImpl) Close() {
+	w.closed = true
+}
+
+func (w *fakeWindowImpl) IsClosed() bool {
+	return w.closed
+}
+
+func (w *fakeWindowImpl) Present(f func(width, height int) error) error {
+	if w.closed {
+		return nil
+	}
+	w.presentCount++
+	return nil
+}
+
+// NewFakeWindowForTesting adds an additional window without a platform window.
+func NewFakeWindowForTesting(f func(*Image) error, width, height int) *Window {
+	w := &Window{
+		f:         f,
+		offscreen: newVolatileImage(width, height),
+		window:    &fakeWindowImpl{},
+	}
+	windowsM.Lock()
+	windows = append(windows, w)
+	windowsM.Unlock()
+	return w
+}
+
+// CloseByUserForTesting emulates closing the window by the user, which doesn't call Close.
+func (w *Window) CloseByUserForTesting() {
+	w.window.(*fakeWindowImpl).closed = true
+}
+
+func (w *Window) PresentCountForTesting() int {
+	return w.window.(*fakeWindowImpl).presentCount
+}
+
+func (w *Window) IsOffscreenDisposedForTesting() bool {
+	return w.offscreen.isDisposed()
+}
+
+func WindowsForTesting() []*Window {
+	windowsM.Lock()
+	defer windowsM.Unlock()
+	return append([]*Window{}, windows...)
+}
+
+func UpdateWindowsForTesting(update bool) error {
+	return updateWindows(update)
+}
+
+func VignettePixelsForTesting(innerRadius, outerRadius float64, clr color.RGBA64, width, height int) []byte {
+	v := &vignette{
+		innerRadius: innerRadius,
+		outerRadius: outerRadius,
+		color:       clr,
+	}
+	return v.pixels(width, height)
+}
+
+func ApplyVignetteForTesting(screen *Image) {
+	theVignette.apply(screen)
+}
+
+func RecordFrameGraphForTesting(now time.Time) {
+	theFrameGraph.recordAt(now)
+}
+
+// AddFrameGraphDrawTimeForTesting emulates drawing the frame graph that takes d.
+func AddFrameGraphDrawTimeForTesting(d time.Duration) {
+	theFrameGraph.m.Lock()
+	defer theFrameGraph.m.Unlock()
+	theFrameGraph.drawTime += d
+}
+
+// FrameGraphTimesForTesting returns the recorded frame times from the oldest and the index of the ring buffer.
+func FrameGraphTimesForTesting() ([]time.Duration, int) {
+	theFrameGraph.m.Lock()
+	defer theFrameGraph.m.Unlock()
+	n := len(theFrameGraph.times)
+	ts := make([]time.Duration, 0, n)
+	for i := 0; i < n; i++ {
+		ts = append(ts, theFrameGraph.times[(theFrameGraph.index+i)%n])
+	}
+	return ts, theFrameGraph.index
+}
+
+var MipmapBiasLevelForTesting = mipmapBiasLevel
@@ -0,0 +1,188 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"image/color"
+	"sync"
+	"time"
+)
+
+const (
+	frameGraphSamples = 120
+	frameGraphHeight  = 32
+)
+
+type frameGraph struct {
+	enabled bool
+
+	// times is the ring buffer of the recent frame times.
+	times [frameGraphSamples]time.Duration
+	index int
+
+	last time.Time
+
+	// drawTime is the time taken to draw the graph itself at the last frame.
+	// This is excluded from the frame time.
+	drawTime time.Duration
+
+	white    *Image
+	vertices []Vertex
+	indices  []uint16
+
+	m sync.Mutex
+}
+
+var theFrameGraph = &frameGraph{}
+
+// IsFrameGraphEnabled returns a boolean value indicating whether the frame-time graph is shown.
+//
+// IsFrameGraphEnabled is concurrent-safe.
+func IsFrameGraphEnabled() bool {
+	theFrameGraph.m.Lock()
+	defer theFrameGraph.m.Unlock()
+	return theFrameGraph.enabled
+}
+
+// SetFrameGraph sets a boolean value indicating whether the frame-time graph is shown.
+//
+// The frame-time graph plots the times of the last 120 frames at the bottom-left corner of the screen.
+// The horizontal line represents the budget of a frame, that is 1/60 seconds unless MaxTPS is changed.
+// The bars exceeding the budget are drawn in red.
+// The time to draw the graph itself is not counted as a frame time.
+//
+// The frame-time graph is intended to be used for debugging purpose.
+// The initial value is false.
+//
+// SetFrameGraph is concurrent-safe.
+func SetFrameGraph(enabled bool) {
+	theFrameGraph.m.Lock()
+	defer theFrameGraph.m.Unlock()
+	if theFrameGraph.enabled == enabled {
+		return
+	}
+	theFrameGraph.enabled = enabled
+	theFrameGraph.times = [frameGraphSamples]time.Duration{}
+	theFrameGraph.index = 0
+	theFrameGraph.last = time.Time{}
+	theFrameGraph.drawTime = 0
+}
+
+// record records the time from the last call of record as a frame time.
+func (g *frameGraph) record() {
+	g.recordAt(time.Now())
+}
+
+// recordAt records the time from the last call of record or recordAt to now as a frame time.
+func (g *frameGraph) recordAt(now time.Time) {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	if !g.enabled {
+		return
+	}
+
+	if !g.last.IsZero() {
+		d := now.Sub(g.last) - g.drawTime
+		if d < 0 {
+			d = 0
+		}
+		g.times[g.index] = d
+		g.index = (g.index + 1) % len(g.times)
+	}
+	g.last = now
+	g.drawTime = 0
+}
+
+func frameBudget() time.Duration {
+	tps := MaxTPS()
+	if tps <= 0 || tps == UncappedTPS {
+		return time.Second / 60
+	}
+	return time.Second / time.Duration(tps)
+}
+
+// draw draws the frame-time graph onto the given screen if the graph is enabled.
+func (g *frameGraph) draw(screen *Image) {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	if !g.enabled {
+		return
+	}
+
+	start := time.Now()
+	defer func() {
+		g.drawTime += time.Since(start)
+	}()
+
+	if g.white == nil {
+		g.white, _ = NewImage(16, 16, FilterDefault)
+		_ = g.white.Fill(color.White)
+	}
+
+	_, h := screen.Size()
+	x0, y0 := 0.0, float64(h-frameGraphHeight)
+
+	// The budget line is at the middle of the graph.
+	budget := frameBudget()
+	pixelsPerTime := frameGraphHeight / 2 / float64(budget)
+
+	g.vertices = g.vertices[:0]
+	g.indices = g.indices[:0]
+
+	// Background
+	g.appendRect(x0, y0, frameGraphSamples, frameGraphHeight, 0, 0, 0, 0.5)
+
+	for i := 0; i < len(g.times); i++ {
+		t := g.times[(g.index+i)%len(g.times)]
+		if t == 0 {
+			continue
+		}
+		bh := float64(t) * pixelsPerTime
+		if bh > frameGraphHeight {
+			bh = frameGraphHeight
+		}
+		if t > budget {
+			g.appendRect(x0+float64(i), y0+frameGraphHeight-bh, 1, bh, 1, 0.25, 0.25, 1)
+		} else {
+			g.appendRect(x0+float64(i), y0+frameGraphHeight-bh, 1, bh, 0.25, 1, 0.25, 1)
+		}
+	}
+
+	// Budget line
+	g.appendRect(x0, y0+frameGraphHeight/2, frameGraphSamples, 1, 1, 1, 0, 1)
+
+	// Filter must be 'nearest' filter (default).
+	// Linear filtering would make edges blurred.
+	screen.DrawTriangles(g.vertices, g.indices, g.white, nil)
+}
+
+func (g *frameGraph) appendRect(x, y, width, height float64, cr, cg, cb, ca float32) {
+	n := uint16(len(g.vertices))
+	for _, p := range [][2]float64{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+		g.vertices = append(g.vertices, Vertex{
+			DstX:   float32(x + p[0]*width),
+			DstY:   float32(y + p[1]*height),
+			SrcX:   float32(p[0]),
+			SrcY:   float32(p[1]),
+			ColorR: cr,
+			ColorG: cg,
+			ColorB: cb,
+			ColorA: ca,
+		})
+	}
+	g.indices = append(g.indices, n, n+1, n+2, n+1, n+2, n+3)
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/hajimehoshi/ebiten"
+)
+
+func TestFrameGraphRecordExcludesDrawTime(t *testing.T) {
+	SetFrameGraph(false)
+	SetFrameGraph(true)
+	defer SetFrameGraph(false)
+
+	t0 := time.Now()
+	RecordFrameGraphForTesting(t0)
+	AddFrameGraphDrawTimeForTesting(3 * time.Millisecond)
+	RecordFrameGraphForTesting(t0.Add(10 * time.Millisecond))
+	// The draw time is reset at every record.
+	RecordFrameGraphForTesting(t0.Add(30 * time.Millisecond))
+	// A frame time is never negative.
+	AddFrameGraphDrawTimeForTesting(50 * time.Millisecond)
+	RecordFrameGraphForTesting(t0.Add(40 * time.Millisecond))
+
+	ts, index := FrameGraphTimesForTesting()
+	if got, want := index, 3; got != want {
+		t.Errorf("index: got: %d, want: %d", got, want)
+	}
+	got := ts[len(ts)-3:]
+	want := []time.Duration{7 * time.Millisecond, 20 * time.Millisecond, 0}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("times[%d]: got: %v, want: %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestFrameGraphResetOnReenabled(t *testing.T) {
+	SetFrameGraph(false)
+	SetFrameGraph(true)
+	defer SetFrameGraph(false)
+
+	t0 := time.Now()
+	for i := 0; i < 5; i++ {
+		RecordFrameGraphForTesting(t0.Add(time.Duration(i) * 16 * time.Millisecond))
+	}
+	if _, index := FrameGraphTimesForTesting(); index != 4 {
+		t.Fatalf("index: got: %d, want: %d", index, 4)
+	}
+
+	SetFrameGraph(false)
+	SetFrameGraph(true)
+
+	check := func() {
+		ts, index := FrameGraphTimesForTesting()
+		if index != 0 {
+			t.Errorf("index: got: %d, want: %d", index, 0)
+		}
+		for i, d := range ts {
+			if d != 0 {
+				t.Errorf("times[%d]: got: %v, want: 0", i, d)
+			}
+		}
+	}
+	check()
+
+	// The first record after reenabling has no last time and doesn't record a frame time.
+	RecordFrameGraphForTesting(t0.Add(time.Second))
+	check()
+}
@@ -15,6 +15,7 @@
 package ebiten
 
 import (
+	"github.com/hajimehoshi/ebiten/internal/gamepaddb"
 	"github.com/hajimehoshi/ebiten/internal/input"
 )
 
@@ -57,3 +58,30 @@ const (
 	GamepadButton31  GamepadButton = GamepadButton(input.GamepadButton31)
 	GamepadButtonMax GamepadButton = GamepadButton31
 )
+
+// A StandardGamepadButton represents a gamepad button in the standard layout.
+//
+// The layout is based on the standard gamepad of the W3C Gamepad API and Xbox controllers.
+type StandardGamepadButton int
+
+// StandardGamepadButtons
+const (
+	StandardGamepadButtonA             StandardGamepadButton = StandardGamepadButton(gamepaddb.StandardButtonA)
+	StandardGamepadButtonB             StandardGamepadButton = StandardGamepadButton(gamepaddb.StandardButtonB)
+	StandardGamepadButtonX             StandardGamepadButton = StandardGamepadButton(gamepaddb.StandardButtonX)
+	StandardGamepadButtonY             StandardGamepadButton = StandardGamepadButton(gamepaddb.StandardButtonY)
+	StandardGamepadButtonBack          StandardGamepadButton = StandardGamepadButton(gamepaddb.StandardButtonBack)
+	StandardGamepadButtonGuide         StandardGamepadButton = StandardGamepadButton(gamepaddb.StandardButtonGuide)
+	StandardGamepadButtonStart         StandardGamepadButton = StandardGamepadButton(gamepaddb.StandardButtonStart)
+	StandardGamepadButtonLeftStick     StandardGamepadButton = StandardGamepadButton(gamepaddb.StandardButtonLeftStick)
+	StandardGamepadButtonRightStick    StandardGamepadButton = StandardGamepadButton(gamepaddb.StandardButtonRightStick)
+	StandardGamepadButtonLeftShoulder  StandardGamepadButton = StandardGamepadButton(gamepaddb.StandardButtonLeftShoulder)
+	StandardGamepadButtonRightShoulder StandardGamepadButton = StandardGamepadButton(gamepaddb.StandardButtonRightShoulder)
+	StandardGamepadButtonDPadUp        StandardGamepadButton = StandardGamepadButton(gamepaddb.StandardButtonDPadUp)
+	StandardGamepadButtonDPadDown      StandardGamepadButton = StandardGamepadButton(gamepaddb.StandardButtonDPadDown)
+	StandardGamepadButtonDPadLeft      StandardGamepadButton = StandardGamepadButton(gamepaddb.StandardButtonDPadLeft)
+	StandardGamepadButtonDPadRight     StandardGamepadButton = StandardGamepadButton(gamepaddb.StandardButtonDPadRight)
+	StandardGamepadButtonLeftTrigger   StandardGamepadButton = StandardGamepadButton(gamepaddb.StandardButtonLeftTrigger)
+	StandardGamepadButtonRightTrigger  StandardGamepadButton = StandardGamepadButton(gamepaddb.StandardButtonRightTrigger)
+	StandardGamepadButtonMax           StandardGamepadButton = StandardGamepadButtonRightTrigger
+)
@@ -85,6 +85,14 @@ func (g *GeoM) Element(i, j int) float64 {
 	}
 }
 
+// IsIdentity reports whether g is an identity matrix.
+//
+// The check is exact: IsIdentity returns false for a matrix that is almost identity
+// due to floating-point errors, e.g., a matrix rotated by 2π.
+func (g *GeoM) IsIdentity() bool {
+	return g.a_1 == 0 && g.b == 0 && g.c == 0 && g.d_1 == 0 && g.tx == 0 && g.ty == 0
+}
+
 // Concat multiplies a geometry matrix with the other geometry matrix.
 // This is same as muptiplying the matrix other and the matrix g in this order.
 func (g *GeoM) Concat(other GeoM) {
@@ -343,3 +343,120 @@ func BenchmarkGeoM(b *testing.B) {
 		m.Rotate(math.Pi / 2)
 	}
 }
+
+func TestGeoMIsIdentity(t *testing.T) {
+	var m GeoM
+	if !m.IsIdentity() {
+		t.Errorf("GeoM{}.IsIdentity(): got false; want true")
+	}
+
+	m.Translate(1, 0)
+	if m.IsIdentity() {
+		t.Errorf("m.IsIdentity(): got true; want false")
+	}
+	m.Translate(-1, 0)
+	if !m.IsIdentity() {
+		t.Errorf("m.IsIdentity(): got false; want true")
+	}
+
+	m.Reset()
+	m.Scale(2, 1)
+	if m.IsIdentity() {
+		t.Errorf("m.IsIdentity(): got true; want false")
+	}
+	m.Reset()
+	if !m.IsIdentity() {
+		t.Errorf("m.IsIdentity(): got false; want true")
+	}
+}
+
+func TestGeoMRotate(t *testing.T) {
+	cases := []struct {
+		Theta      float64
+		Tx, Ty     float64
+		X, Y       float64
+		WantX      float64
+		WantY      float64
+		IsIdentity bool
+	}{
+		{Theta: 0, X: 1, Y: 0, WantX: 1, WantY: 0, IsIdentity: true},
+		{Theta: math.Pi / 2, X: 1, Y: 0, WantX: 0, WantY: 1},
+		{Theta: math.Pi / 2, X: 0, Y: 1, WantX: -1, WantY: 0},
+		// Rotating is applied before translating.
+		{Theta: math.Pi / 2, Tx: 10, Ty: 20, X: 1, Y: 0, WantX: 10, WantY: 21},
+	}
+	for _, c := range cases {
+		g := GeoM{}
+		g.Rotate(c.Theta)
+		g.Translate(c.Tx, c.Ty)
+		x, y := g.Apply(c.X, c.Y)
+		if math.Abs(x-c.WantX) > 1e-6 || math.Abs(y-c.WantY) > 1e-6 {
+			t.Errorf("theta: %f, translate: (%f, %f), Apply(%f, %f): got: (%f, %f), want: (%f, %f)", c.Theta, c.Tx, c.Ty, c.X, c.Y, x, y, c.WantX, c.WantY)
+		}
+		if c.IsIdentity && !g.IsIdentity() {
+			t.Errorf("theta: %f: g must be identity", c.Theta)
+		}
+	}
+}
+
+func TestGeoMScale(t *testing.T) {
+	cases := []struct {
+		Name           string
+		F              func(g *GeoM)
+		X, Y           float64
+		WantX, WantY   float64
+		WantIsIdentity bool
+	}{
+		{
+			Name:           "scale (1, 1)",
+			F:              func(g *GeoM) { g.Scale(1, 1) },
+			X:              3,
+			Y:              4,
+			WantX:          3,
+			WantY:          4,
+			WantIsIdentity: true,
+		},
+		{
+			Name:  "flip on X",
+			F:     func(g *GeoM) { g.Scale(-1, 1) },
+			X:     3,
+			Y:     4,
+			WantX: -3,
+			WantY: 4,
+		},
+		{
+			// Translating is scaled by the following Scale.
+			Name: "translate then scale",
+			F: func(g *GeoM) {
+				g.Translate(10, 20)
+				g.Scale(-2, 3)
+			},
+			X:     1,
+			Y:     1,
+			WantX: -22,
+			WantY: 63,
+		},
+		{
+			Name: "scale then translate",
+			F: func(g *GeoM) {
+				g.Scale(-2, 3)
+				g.Translate(10, 20)
+			},
+			X:     1,
+			Y:     1,
+			WantX: 8,
+			WantY: 23,
+		},
+	}
+	for _, c := range cases {
+		g := GeoM{}
+		c.F(&g)
+		x, y := g.Apply(c.X, c.Y)
+		if x != c.WantX || y != c.WantY {
+			t.Errorf("%s: Apply(%f, %f): got: (%f, %f), want: (%f, %f)", c.Name, c.X, c.Y, x, y, c.WantX, c.WantY)
+		}
+		if got := g.IsIdentity(); got != c.WantIsIdentity {
+			t.Errorf("%s: IsIdentity: got: %v, want: %v", c.Name, got, c.WantIsIdentity)
+		}
+	}
+}
@@ -15,6 +15,9 @@
 package ebiten
 
 import (
+	"fmt"
+	"sync"
+
 	"github.com/hajimehoshi/ebiten/internal/graphics"
 )
 
@@ -33,8 +36,25 @@ const (
 
 	// filterScreen represents a special filter for screen. Inner usage only.
 	filterScreen Filter = Filter(graphics.FilterScreen)
+
+	// FilterBicubic represents bicubic (Catmull-Rom) filter.
+	// FilterBicubic samples 4x4 texels, and is sharper than FilterLinear for large upscales.
+	// Mipmaps are not used with FilterBicubic.
+	FilterBicubic Filter = Filter(graphics.FilterBicubic)
 )
 
+// SupportedFilters returns the filters that are usable in the current environment.
+//
+// Ebiten implements texture filtering in its own shader instead of relying on the
+// graphics driver's texture parameters, so the result doesn't depend on the GL capabilities,
+// e.g., the difference between OpenGL ES 2 and desktop OpenGL, or extension availability.
+// FilterDefault is not included since this is not an actual filter.
+//
+// SupportedFilters is concurrent-safe.
+func SupportedFilters() []Filter {
+	return []Filter{FilterNearest, FilterLinear, FilterBicubic}
+}
+
 // CompositeMode represents Porter-Duff composition mode.
 type CompositeMode int
 
@@ -84,3 +104,121 @@ const (
 	// c_out = c_src + c_dst
 	CompositeModeLighter CompositeMode = CompositeMode(graphics.CompositeModeLighter)
 )
+
+// BlendFactor represents a factor to multiply source or destination colors by in a custom composite mode.
+//
+// In the comments,
+// c_src and c_dst represent alpha-premultiplied RGBA values of source and destination respectively.
+// α_src and α_dst represent alpha values of source and destination respectively.
+type BlendFactor int
+
+const (
+	// 0
+	BlendFactorZero BlendFactor = BlendFactor(graphics.Zero)
+
+	// 1
+	BlendFactorOne BlendFactor = BlendFactor(graphics.One)
+
+	// c_src
+	BlendFactorSrcColor BlendFactor = BlendFactor(graphics.SrcColor)
+
+	// 1 - c_src
+	BlendFactorOneMinusSrcColor BlendFactor = BlendFactor(graphics.OneMinusSrcColor)
+
+	// α_src
+	BlendFactorSrcAlpha BlendFactor = BlendFactor(graphics.SrcAlpha)
+
+	// 1 - α_src
+	BlendFactorOneMinusSrcAlpha BlendFactor = BlendFactor(graphics.OneMinusSrcAlpha)
+
+	// c_dst
+	BlendFactorDstColor BlendFactor = BlendFactor(graphics.DstColor)
+
+	// 1 - c_dst
+	BlendFactorOneMinusDstColor BlendFactor = BlendFactor(graphics.OneMinusDstColor)
+
+	// α_dst
+	BlendFactorDstAlpha BlendFactor = BlendFactor(graphics.DstAlpha)
+
+	// 1 - α_dst
+	BlendFactorOneMinusDstAlpha BlendFactor = BlendFactor(graphics.OneMinusDstAlpha)
+)
+
+// CustomCompositeMode returns a composite mode that blends with the given factors,
+// i.e., c_out = c_src × src + c_dst × dst.
+//
+// For example, multiply blending is CustomCompositeMode(BlendFactorDstColor, BlendFactorOneMinusSrcAlpha),
+// and screen blending is CustomCompositeMode(BlendFactorOne, BlendFactorOneMinusSrcColor).
+//
+// All the factors are supported on both desktop OpenGL and OpenGL ES 2 (and WebGL), and on Metal.
+//
+// As with the other composite modes, drawing calls with the same composite mode are batched,
+// and the blend state is changed only when the composite mode changes.
+//
+// If src or dst is not a valid BlendFactor, CustomCompositeMode panics.
+//
+// CustomCompositeMode is concurrent-safe.
+func CustomCompositeMode(src, dst BlendFactor) CompositeMode {
+	if src < BlendFactorZero || BlendFactorOneMinusDstAlpha < src {
+		panic(fmt.Sprintf("ebiten: invalid src BlendFactor: %d", src))
+	}
+	if dst < BlendFactorZero || BlendFactorOneMinusDstAlpha < dst {
+		panic(fmt.Sprintf("ebiten: invalid dst BlendFactor: %d", dst))
+	}
+	m := CompositeMode(graphics.CustomCompositeMode(graphics.Operation(src), graphics.Operation(dst)))
+	theCustomCompositeModesM.Lock()
+	theCustomCompositeModes[m] = struct{}{}
+	theCustomCompositeModesM.Unlock()
+	return m
+}
+
+var (
+	// theCustomCompositeModes is the set of the composite modes created by CustomCompositeMode, for PrewarmShaders.
+	theCustomCompositeModes  = map[CompositeMode]struct{}{}
+	theCustomCompositeModesM sync.Mutex
+)
+
+// compositeModes returns all the built-in composite modes and the custom composite modes created so far.
+func compositeModes() []CompositeMode {
+	ms := []CompositeMode{
+		CompositeModeSourceOver,
+		CompositeModeClear,
+		CompositeModeCopy,
+		CompositeModeDestination,
+		CompositeModeDestinationOver,
+		CompositeModeSourceIn,
+		CompositeModeDestinationIn,
+		CompositeModeSourceOut,
+		CompositeModeDestinationOut,
+		CompositeModeSourceAtop,
+		CompositeModeDestinationAtop,
+		CompositeModeXor,
+		CompositeModeLighter,
+	}
+	theCustomCompositeModesM.Lock()
+	defer theCustomCompositeModesM.Unlock()
+	for m := range theCustomCompositeModes {
+		ms = append(ms, m)
+	}
+	return ms
+}
+
+// ColorMask represents a set of color channels that are not written by rendering.
+//
+// For example, ColorMaskAlpha preserves the alpha values of the destination image, and
+// ColorMaskRed|ColorMaskGreen|ColorMaskBlue writes only the alpha values.
+type ColorMask int
+
+const (
+	// ColorMaskRed masks the red channel.
+	ColorMaskRed ColorMask = ColorMask(graphics.ColorMaskRed)
+
+	// ColorMaskGreen masks the green channel.
+	ColorMaskGreen ColorMask = ColorMask(graphics.ColorMaskGreen)
+
+	// ColorMaskBlue masks the blue channel.
+	ColorMaskBlue ColorMask = ColorMask(graphics.ColorMaskBlue)
+
+	// ColorMaskAlpha masks the alpha channel.
+	ColorMaskAlpha ColorMask = ColorMask(graphics.ColorMaskAlpha)
+)
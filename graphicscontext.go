@@ -17,6 +17,7 @@ package ebiten
 import (
 	"image/color"
 	"math"
+	"sync/atomic"
 
 	"github.com/hajimehoshi/ebiten/internal/clock"
 	"github.com/hajimehoshi/ebiten/internal/graphicscommand"
@@ -34,16 +35,19 @@ func newGraphicsContext(f func(*Image) error) *graphicsContext {
 }
 
 type graphicsContext struct {
-	f            func(*Image) error
-	offscreen    *Image
-	screen       *Image
-	screenWidth  int
-	screenHeight int
-	screenScale  float64
-	initialized  bool
-	invalidated  bool // browser only
-	offsetX      float64
-	offsetY      float64
+	f               func(*Image) error
+	offscreen       *Image
+	offscreenWidth  int
+	offscreenHeight int
+	renderScale     float64
+	screen          *Image
+	screenWidth     int
+	screenHeight    int
+	screenScale     float64
+	initialized     bool
+	invalidated     bool // browser only
+	offsetX         float64
+	offsetY         float64
 }
 
 func (c *graphicsContext) Invalidate() {
@@ -60,10 +64,9 @@ func (c *graphicsContext) SetSize(screenWidth, screenHeight int, screenScale flo
 	if c.screen != nil {
 		_ = c.screen.Dispose()
 	}
-	if c.offscreen != nil {
-		_ = c.offscreen.Dispose()
-	}
-	c.offscreen = newVolatileImage(screenWidth, screenHeight)
+	c.offscreenWidth = screenWidth
+	c.offscreenHeight = screenHeight
+	c.resetOffscreen()
 
 	w := int(math.Ceil(float64(screenWidth) * screenScale))
 	h := int(math.Ceil(float64(screenHeight) * screenScale))
@@ -76,6 +79,29 @@ func (c *graphicsContext) SetSize(screenWidth, screenHeight int, screenScale flo
 	c.offsetY = py0
 }
 
+// isPixelPerfect reports whether the offscreen is rendered to the screen with nearest-neighbor
+// scaling. This is true only when pixel-perfect scaling is enabled and each pixel of the offscreen
+// is scaled by an integer.
+func (c *graphicsContext) isPixelPerfect() bool {
+	if !IsScreenPixelPerfect() {
+		return false
+	}
+	s := c.screenScale / c.renderScale
+	if s < 1 {
+		return false
+	}
+	// Allow a small error from the device scale factor calculation.
+	return math.Abs(s-math.Round(s)) < 1e-6
+}
+
+func (c *graphicsContext) resetOffscreen() {
+	if c.offscreen != nil {
+		_ = c.offscreen.Dispose()
+	}
+	c.renderScale = RenderScale()
+	c.offscreen = newScaledVolatileImage(c.offscreenWidth, c.offscreenHeight, c.renderScale)
+}
+
 func (c *graphicsContext) initializeIfNeeded() error {
 	if !c.initialized {
 		if err := shareable.InitializeGraphicsDriverState(); err != nil {
@@ -86,18 +112,70 @@ func (c *graphicsContext) initializeIfNeeded() error {
 	if err := c.restoreIfNeeded(); err != nil {
 		return err
 	}
+	if atomic.CompareAndSwapInt32(&shadersPrewarmRequested, 1, 0) {
+		prewarmShaders()
+	}
 	return nil
 }
 
+// prewarmShaders issues dummy draw calls with all the filters, address modes and composite modes,
+// and with the user-defined shaders, so that the driver compiles the shader variants and creates
+// the pipeline states in advance.
+func prewarmShaders() {
+	src, _ := NewImage(16, 16, FilterDefault)
+	dst := newVolatileImage(16, 16)
+	defer func() {
+		_ = src.Dispose()
+		_ = dst.Dispose()
+	}()
+
+	vs := []Vertex{
+		{DstX: 0, DstY: 0, SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 1, DstY: 0, SrcX: 1, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 0, DstY: 1, SrcX: 0, SrcY: 1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+	}
+	is := []uint16{0, 1, 2}
+	modes := compositeModes()
+	ss := shaders()
+	for _, f := range []Filter{FilterNearest, FilterLinear, filterScreen, FilterBicubic} {
+		for _, a := range SupportedAddresses() {
+			for _, m := range modes {
+				op := &DrawTrianglesOptions{
+					CompositeMode: m,
+					Filter:        f,
+					Address:       a,
+				}
+				// Use a color matrix that is not 'scale only' to use the color matrix path.
+				op.ColorM.RotateHue(1)
+				dst.DrawTriangles(vs, is, src, op)
+			}
+			for _, s := range ss {
+				// The uniform variables are 0 as their values are not specified.
+				// Wrap the internal shader temporarily. Note that the wrapper doesn't have a finalizer.
+				op := &DrawTrianglesOptions{
+					Filter:  f,
+					Address: a,
+					Shader:  &Shader{shader: s},
+				}
+				dst.DrawTriangles(vs, is, src, op)
+			}
+		}
+	}
+}
+
 func (c *graphicsContext) Update(afterFrameUpdate func()) error {
 	tps := int(MaxTPS())
 	updateCount := clock.Update(tps)
+	theFrameGraph.record()
 
 	// TODO: If updateCount is 0 and vsync is disabled, swapping buffers can be skipped.
 
 	if err := c.initializeIfNeeded(); err != nil {
 		return err
 	}
+	if updateCount > 0 && c.renderScale != RenderScale() {
+		c.resetOffscreen()
+	}
 	for i := 0; i < updateCount; i++ {
 		c.offscreen.Fill(color.Transparent)
 		// Mipmap images should be disposed by fill.
@@ -111,6 +189,12 @@ func (c *graphicsContext) Update(afterFrameUpdate func()) error {
 		}
 		afterFrameUpdate()
 	}
+	if updateCount > 0 {
+		// The post-processes are applied once to the frame to be presented. The vignette is applied before
+		// the frame graph so that the graph is not darkened.
+		theVignette.apply(c.offscreen)
+		theFrameGraph.draw(c.offscreen)
+	}
 
 	// TODO: This clear is needed only when the screen size is changed.
 	if c.offsetX > 0 || c.offsetY > 0 {
@@ -131,18 +215,28 @@ func (c *graphicsContext) Update(afterFrameUpdate func()) error {
 		panic("not reached")
 	}
 
-	op.GeoM.Translate(c.offsetX, c.offsetY)
 	op.CompositeMode = CompositeModeCopy
 
-	// filterScreen works with >=1 scale, but does not well with <1 scale.
-	// Use regular FilterLinear instead so far (#669).
-	if c.screenScale >= 1 {
-		op.Filter = filterScreen
+	if c.isPixelPerfect() {
+		// Snap the offsets to the pixels so that each block of pixels is aligned with the window's pixels.
+		op.GeoM.Translate(math.Floor(c.offsetX), math.Floor(c.offsetY))
+		op.Filter = FilterNearest
 	} else {
-		op.Filter = FilterLinear
+		op.GeoM.Translate(c.offsetX, c.offsetY)
+		// filterScreen works with >=1 scale, but does not well with <1 scale.
+		// Use regular FilterLinear instead so far (#669).
+		if c.screenScale >= 1 {
+			op.Filter = filterScreen
+		} else {
+			op.Filter = FilterLinear
+		}
 	}
 	_ = c.screen.DrawImage(c.offscreen, op)
 
+	if err := updateWindows(updateCount > 0); err != nil {
+		return err
+	}
+
 	shareable.ResolveStaleImages()
 
 	if err := shareable.Error(); err != nil {
@@ -15,13 +15,18 @@
 package ebiten
 
 import (
+	"errors"
 	"fmt"
 	"image"
 	"image/color"
+	"image/draw"
 	"math"
 	"runtime"
+	"sync"
 
+	"github.com/hajimehoshi/ebiten/internal/affine"
 	"github.com/hajimehoshi/ebiten/internal/graphics"
+	"github.com/hajimehoshi/ebiten/internal/graphicscommand"
 	"github.com/hajimehoshi/ebiten/internal/shareable"
 )
 
@@ -82,7 +87,7 @@ func (m *mipmap) level(r image.Rectangle, level int) *shareable.Image {
 			vs = src.QuadVertices(0, 0, w, h, 0.5, 0, 0, 0.5, 0, 0, 1, 1, 1, 1)
 		}
 		is := graphics.QuadIndices()
-		s.DrawImage(src, vs, is, nil, graphics.CompositeModeCopy, graphics.FilterLinear, graphics.AddressClampToZero)
+		s.DrawImage(src, vs, is, nil, graphics.CompositeModeCopy, graphics.FilterLinear, graphics.AddressClampToZero, graphics.ColorMaskNone, nil, nil)
 		imgs = append(imgs, s)
 		w = w2
 		h = h2
@@ -133,7 +138,18 @@ type Image struct {
 
 	pixelsToSet []byte
 
+	// alphaMask holds the alpha values of the source image for AlphaAt.
+	// alphaMask is nil unless the image is created with KeepAlphaMask.
+	alphaMask *image.Alpha
+
 	filter Filter
+
+	// renderScale is the scale of the underlying image relative to the image's size.
+	// renderScale is 0 unless the image is the screen rendered at a different resolution.
+	// See SetRenderScale.
+	renderScale  float64
+	renderWidth  int
+	renderHeight int
 }
 
 func (i *Image) copyCheck() {
@@ -143,6 +159,10 @@ func (i *Image) copyCheck() {
 }
 
 // Size returns the size of the image.
+//
+// Size returns the original size even if the image is allocated with a larger size internally,
+// e.g., as a power-of-two texture or as a part of a texture atlas.
+// For an image created by NewImageFromImage, Size returns the size of the source's bounds.
 func (i *Image) Size() (width, height int) {
 	s := i.Bounds().Size()
 	return s.X, s.Y
@@ -177,9 +197,21 @@ func (i *Image) Fill(clr color.Color) error {
 		return nil
 	}
 
-	// TODO: Implement this.
 	if i.isSubimage() {
-		panic("render to a subimage is not implemented")
+		// Fill the region by drawing a white image with the color.
+		r := i.Bounds()
+		if r.Empty() {
+			return nil
+		}
+		src := whiteImage()
+		w, h := src.Size()
+		op := &DrawImageOptions{}
+		op.GeoM.Scale(float64(r.Dx())/float64(w), float64(r.Dy())/float64(h))
+		op.GeoM.Translate(float64(r.Min.X), float64(r.Min.Y))
+		op.ColorM.ScaleWithColor(clr)
+		op.CompositeMode = CompositeModeCopy
+		i.drawImage(src, op)
+		return nil
 	}
 
 	i.resolvePixelsToSet(false)
@@ -191,6 +223,20 @@ func (i *Image) Fill(clr color.Color) error {
 	return nil
 }
 
+var (
+	theWhiteImage     *Image
+	theWhiteImageOnce sync.Once
+)
+
+// whiteImage returns a shared image filled with opaque white to fill sub-images.
+func whiteImage() *Image {
+	theWhiteImageOnce.Do(func() {
+		theWhiteImage, _ = NewImage(16, 16, FilterDefault)
+		_ = theWhiteImage.Fill(color.White)
+	})
+	return theWhiteImage
+}
+
 func (i *Image) disposeMipmaps() {
 	if i.isDisposed() {
 		panic("not reached")
@@ -242,9 +288,14 @@ func (i *Image) drawImage(img *Image, options *DrawImageOptions) {
 		return
 	}
 
-	// TODO: Implement this.
-	if i.isSubimage() {
-		panic("render to a subimage is not implemented")
+	if i.sharesUnderlyingImage(img) {
+		if img.Bounds().Empty() {
+			return
+		}
+		src, orig := img.copyAsSource()
+		i.drawImage(src, options)
+		_ = orig.Dispose()
+		return
 	}
 
 	img.resolvePixelsToSet(true)
@@ -271,6 +322,9 @@ func (i *Image) drawImage(img *Image, options *DrawImageOptions) {
 			op := &DrawImageOptions{
 				ColorM:        options.ColorM,
 				CompositeMode: options.CompositeMode,
+				ColorMask:     options.ColorMask,
+				Shader:        options.Shader,
+				Uniforms:      options.Uniforms,
 			}
 			op.GeoM.Scale(
 				float64(dx1-dx0)/float64(sx1-sx0),
@@ -282,6 +336,10 @@ func (i *Image) drawImage(img *Image, options *DrawImageOptions) {
 		return
 	}
 
+	if math.IsNaN(options.MipmapBias) {
+		panic("ebiten: MipmapBias must not be NaN")
+	}
+
 	bounds := img.Bounds()
 
 	// SourceRect is deprecated. This implementation is for backward compatibility.
@@ -293,7 +351,23 @@ func (i *Image) drawImage(img *Image, options *DrawImageOptions) {
 	}
 
 	geom := &options.GeoM
+	if img.renderScale != 0 || i.renderScale != 0 {
+		g := GeoM{}
+		if img.renderScale != 0 {
+			g.Scale(1/img.renderScale, 1/img.renderScale)
+			bounds = img.physicalRect(bounds)
+		}
+		g.Concat(options.GeoM)
+		if i.renderScale != 0 {
+			g.Scale(i.renderScale, i.renderScale)
+		}
+		geom = &g
+	}
 	mode := graphics.CompositeMode(options.CompositeMode)
+	mask := graphics.ColorMask(options.ColorMask)
+	shader := options.Shader.graphicsShader()
+	uniforms := options.Uniforms.graphicsUniforms()
+	options.Shader.checkUniforms(uniforms)
 
 	filter := graphics.FilterNearest
 	if options.Filter != FilterDefault {
@@ -317,15 +391,24 @@ func (i *Image) drawImage(img *Image, options *DrawImageOptions) {
 		if level < 0 {
 			panic("not reached")
 		}
+		level += mipmapBiasLevel(options.MipmapBias)
+		if level < 0 {
+			level = 0
+		}
 	}
-	if level > 6 {
-		level = 6
+	if level > maxMipmapLevel {
+		level = maxMipmapLevel
 	}
 
 	// TODO: Add (*mipmap).drawImage and move the below code.
-	colorm := options.ColorM.impl
+	colorm := alphaTestColorM(options.ColorM, options.AlphaTestThreshold).impl
+	if options.ColorMInLinearSpace {
+		colorm = colorm.InLinearSpace()
+	}
 	cr, cg, cb, ca := float32(1), float32(1), float32(1), float32(1)
-	if colorm.ScaleOnly() {
+	// A matrix in the linear space is not moved to the vertex color scales, that are applied
+	// in the same space as the matrix.
+	if !colorm.IsInLinearSpace() && colorm.ScaleOnly() {
 		body, _ := colorm.UnsafeElements()
 		cr = body[0]
 		cg = body[5]
@@ -338,7 +421,7 @@ func (i *Image) drawImage(img *Image, options *DrawImageOptions) {
 		src := img.mipmap.original()
 		vs := src.QuadVertices(bounds.Min.X, bounds.Min.Y, bounds.Max.X, bounds.Max.Y, a, b, c, d, tx, ty, cr, cg, cb, ca)
 		is := graphics.QuadIndices()
-		i.mipmap.original().DrawImage(src, vs, is, colorm, mode, filter, graphics.AddressClampToZero)
+		i.drawTriangles(src, vs, is, colorm, mode, filter, graphics.AddressClampToZero, mask, shader, uniforms)
 	} else if src := img.mipmap.level(bounds, level); src != nil {
 		w, h := src.Size()
 		s := 1 << uint(level)
@@ -348,11 +431,72 @@ func (i *Image) drawImage(img *Image, options *DrawImageOptions) {
 		d *= float32(s)
 		vs := src.QuadVertices(0, 0, w, h, a, b, c, d, tx, ty, cr, cg, cb, ca)
 		is := graphics.QuadIndices()
-		i.mipmap.original().DrawImage(src, vs, is, colorm, mode, filter, graphics.AddressClampToZero)
+		i.drawTriangles(src, vs, is, colorm, mode, filter, graphics.AddressClampToZero, mask, shader, uniforms)
 	}
 	i.disposeMipmaps()
 }
 
+// maxMipmapLevel is the maximum mipmap level used for drawing.
+const maxMipmapLevel = 6
+
+// mipmapBiasLevel returns the offset of the mipmap level for the given bias.
+//
+// The bias is rounded to the nearest integer and clamped so that an extreme bias, including infinities,
+// doesn't overflow the level.
+func mipmapBiasLevel(bias float64) int {
+	if bias > maxMipmapLevel {
+		return maxMipmapLevel
+	}
+	if bias < -maxMipmapLevel {
+		return -maxMipmapLevel
+	}
+	return int(math.Floor(bias + 0.5))
+}
+
+// sharesUnderlyingImage reports whether i and img are different images sharing the same underlying image,
+// e.g., sub-images of the same image.
+func (i *Image) sharesUnderlyingImage(img *Image) bool {
+	if i.mipmap != img.mipmap {
+		return false
+	}
+	return i.isSubimage() || img.isSubimage()
+}
+
+// copyAsSource copies img to a temporary image, and returns the copy with the same bounds as img and
+// the temporary image to dispose after use.
+//
+// This is used when img is drawn on an image that shares the same underlying image, since an image can't be
+// drawn on itself.
+func (img *Image) copyAsSource() (src *Image, orig *Image) {
+	b := img.Bounds()
+	orig, _ = NewImage(b.Max.X, b.Max.Y, img.filter)
+	op := &DrawImageOptions{}
+	op.GeoM.Translate(float64(b.Min.X), float64(b.Min.Y))
+	op.CompositeMode = CompositeModeCopy
+	orig.drawImage(img, op)
+	return orig.SubImage(b).(*Image), orig
+}
+
+// drawTriangles draws the triangles on the underlying image.
+//
+// If i is a sub-image, the triangles are clipped to i's bounds so that the rendering doesn't affect
+// the outside of the bounds. The vertices are drawn on the original image directly, so successive draw calls
+// can be batched.
+func (i *Image) drawTriangles(src *shareable.Image, vertices []float32, indices []uint16, colorm *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address, mask graphics.ColorMask, shader *graphicscommand.Shader, uniforms graphics.Uniforms) {
+	dst := i.mipmap.original()
+	if !i.isSubimage() {
+		dst.DrawImage(src, vertices, indices, colorm, mode, filter, address, mask, shader, uniforms)
+		return
+	}
+	r := i.physicalRect(i.Bounds())
+	if r.Empty() {
+		return
+	}
+	graphics.ClipTriangles(vertices, indices, float32(r.Min.X), float32(r.Min.Y), float32(r.Max.X), float32(r.Max.Y), func(vs []float32, is []uint16) {
+		dst.DrawImage(src, vs, is, colorm, mode, filter, address, mask, shader, uniforms)
+	})
+}
+
 // Vertex represents a vertex passed to DrawTriangles.
 //
 // Note that this API is experimental.
@@ -385,8 +529,24 @@ const (
 
 	// AddressRepeat means that texture coordinates wrap to the other side of the texture.
 	AddressRepeat Address = Address(graphics.AddressRepeat)
+
+	// AddressMirroredRepeat means that texture coordinates wrap to the other side of the texture,
+	// and the texture is mirrored at every other repetition.
+	// This hides seams between tiles whose edges don't match.
+	AddressMirroredRepeat Address = Address(graphics.AddressMirroredRepeat)
 )
 
+// SupportedAddresses returns the sampler address modes that are usable in the current environment.
+//
+// Ebiten implements address modes in its own shader, and textures are internally extended to
+// power-of-two sizes. Then, the result doesn't depend on the GL capabilities like
+// non-power-of-two texture support on OpenGL ES 2.
+//
+// SupportedAddresses is concurrent-safe.
+func SupportedAddresses() []Address {
+	return []Address{AddressClampToZero, AddressRepeat, AddressMirroredRepeat}
+}
+
 // DrawTrianglesOptions represents options to render triangles on an image.
 //
 // Note that this API is experimental.
@@ -407,6 +567,52 @@ type DrawTrianglesOptions struct {
 	// Address is a sampler address mode.
 	// The default (zero) value is AddressClampToZero.
 	Address Address
+
+	// ColorMask is a set of color channels that are not written.
+	// The default (zero) value writes all the channels.
+	ColorMask ColorMask
+
+	// Shader is a user-defined shader applied to the rendered colors.
+	// The default (zero) value is nil, which means that no user-defined shader is applied.
+	//
+	// See the document of DrawImageOptions.Shader for details.
+	Shader *Shader
+
+	// Uniforms is the values of the uniform variables of Shader.
+	//
+	// See the document of DrawImageOptions.Uniforms for details.
+	Uniforms Uniforms
+
+	// AlphaTestThreshold is a threshold of the alpha test.
+	// The default (zero) value disables the alpha test.
+	//
+	// The alpha test doesn't discard pixels but makes them transparent, which works as discarding only
+	// with some composite modes like CompositeModeSourceOver.
+	// See the document of DrawImageOptions.AlphaTestThreshold for details.
+	AlphaTestThreshold float64
+
+	// ColorMInLinearSpace represents whether ColorM and the vertices' color scales are applied in
+	// the linear color space.
+	// The default (zero) value is false.
+	//
+	// See the document of DrawImageOptions.ColorMInLinearSpace for details.
+	ColorMInLinearSpace bool
+}
+
+// alphaTestScale is the scale of the alpha values at the alpha test.
+// The alpha values are scaled by this value and clamped, which works as a step function.
+const alphaTestScale = 256
+
+// alphaTestColorM returns a color matrix that applies colorm and then the alpha test with the given threshold.
+func alphaTestColorM(colorm ColorM, threshold float64) ColorM {
+	if threshold <= 0 {
+		return colorm
+	}
+	// Alpha values that are equal to or more than the threshold become 1 after clamping.
+	colorm.Translate(0, 0, 0, -threshold)
+	colorm.Scale(1, 1, 1, alphaTestScale)
+	colorm.Translate(0, 0, 0, 1)
+	return colorm
 }
 
 // MaxIndicesNum is the maximum number of indices for DrawTriangles.
@@ -421,18 +627,28 @@ const MaxIndicesNum = graphics.IndicesNum
 // The rule in which DrawTriangles works effectively is same as DrawImage's.
 //
 // When the image i is disposed, DrawTriangles does nothing.
+// When the given image img is disposed, DrawTriangles panics.
 //
 // Internal mipmap is not used on DrawTriangles.
 //
 // Note that this API is experimental.
 func (i *Image) DrawTriangles(vertices []Vertex, indices []uint16, img *Image, options *DrawTrianglesOptions) {
 	i.copyCheck()
+	if img.isDisposed() {
+		panic("ebiten: the given image to DrawTriangles must not be disposed")
+	}
 	if i.isDisposed() {
 		return
 	}
 
-	if i.isSubimage() {
-		panic("render to a subimage is not implemented")
+	if i.sharesUnderlyingImage(img) {
+		if img.Bounds().Empty() {
+			return
+		}
+		src, orig := img.copyAsSource()
+		i.DrawTriangles(vertices, indices, src, options)
+		_ = orig.Dispose()
+		return
 	}
 
 	img.resolvePixelsToSet(true)
@@ -461,14 +677,29 @@ func (i *Image) DrawTriangles(vertices []Vertex, indices []uint16, img *Image, o
 
 	vs := make([]float32, len(vertices)*graphics.VertexFloatNum)
 	src := img.mipmap.original()
-	r := img.Bounds()
+	r := img.physicalRect(img.Bounds())
 	for idx, v := range vertices {
+		dx, dy, sx, sy := v.DstX, v.DstY, v.SrcX, v.SrcY
+		if i.renderScale != 0 {
+			dx *= float32(i.renderScale)
+			dy *= float32(i.renderScale)
+		}
+		if img.renderScale != 0 {
+			sx *= float32(img.renderScale)
+			sy *= float32(img.renderScale)
+		}
 		src.PutVertex(vs[idx*graphics.VertexFloatNum:(idx+1)*graphics.VertexFloatNum],
-			float32(v.DstX), float32(v.DstY), v.SrcX, v.SrcY,
+			dx, dy, sx, sy,
 			float32(r.Min.X), float32(r.Min.Y), float32(r.Max.X), float32(r.Max.Y),
 			v.ColorR, v.ColorG, v.ColorB, v.ColorA)
 	}
-	i.mipmap.original().DrawImage(img.mipmap.original(), vs, indices, options.ColorM.impl, mode, filter, graphics.Address(options.Address))
+	colorm := alphaTestColorM(options.ColorM, options.AlphaTestThreshold).impl
+	if options.ColorMInLinearSpace {
+		colorm = colorm.InLinearSpace()
+	}
+	uniforms := options.Uniforms.graphicsUniforms()
+	options.Shader.checkUniforms(uniforms)
+	i.drawTriangles(img.mipmap.original(), vs, indices, colorm, mode, filter, graphics.Address(options.Address), graphics.ColorMask(options.ColorMask), options.Shader.graphicsShader(), uniforms)
 	i.disposeMipmaps()
 }
 
@@ -478,7 +709,15 @@ func (i *Image) DrawTriangles(vertices []Vertex, indices []uint16, img *Image, o
 //
 // If the image is disposed, SubImage returns nil.
 //
-// In the current Ebiten implementation, SubImage is available only as a rendering source.
+// The returned image is available both as a rendering source and as a render target.
+// Rendering to a sub-image is clipped to the sub-image's bounds. The coordinates for rendering
+// are the same as the original image's: the upper-left position of the sub-image is r.Min,
+// not (0, 0).
+// This works as a clipping region, e.g., for a UI panel: use screen.SubImage(r) to draw only in r on the screen.
+// A sub-image of a sub-image is clipped to the intersection of both bounds. To use another region,
+// call SubImage of the original image instead.
+// Rendering to a sub-image is drawn on the original image directly with the triangles clipped to the bounds,
+// so successive draw calls to a sub-image can be batched like a regular image.
 func (i *Image) SubImage(r image.Rectangle) image.Image {
 	i.copyCheck()
 	if i.isDisposed() {
@@ -486,8 +725,12 @@ func (i *Image) SubImage(r image.Rectangle) image.Image {
 	}
 
 	img := &Image{
-		mipmap: i.mipmap,
-		filter: i.filter,
+		mipmap:       i.mipmap,
+		alphaMask:    i.alphaMask,
+		filter:       i.filter,
+		renderScale:  i.renderScale,
+		renderWidth:  i.renderWidth,
+		renderHeight: i.renderHeight,
 	}
 
 	// Keep the original image's reference not to dispose that by GC.
@@ -513,12 +756,37 @@ func (i *Image) SubImage(r image.Rectangle) image.Image {
 // Bounds returns the bounds of the image.
 func (i *Image) Bounds() image.Rectangle {
 	if i.bounds == nil {
+		if i.renderScale != 0 {
+			return image.Rect(0, 0, i.renderWidth, i.renderHeight)
+		}
 		w, h := i.mipmap.original().Size()
 		return image.Rect(0, 0, w, h)
 	}
 	return *i.bounds
 }
 
+// physicalRect returns the rectangle on the underlying image corresponding to r.
+func (i *Image) physicalRect(r image.Rectangle) image.Rectangle {
+	if i.renderScale == 0 {
+		return r
+	}
+	s := i.renderScale
+	return image.Rect(
+		int(math.Floor(float64(r.Min.X)*s)),
+		int(math.Floor(float64(r.Min.Y)*s)),
+		int(math.Ceil(float64(r.Max.X)*s)),
+		int(math.Ceil(float64(r.Max.Y)*s)))
+}
+
+// at returns the pixel at (x, y) on the underlying image.
+func (i *Image) at(x, y int) (r, g, b, a byte) {
+	if i.renderScale != 0 {
+		x = int(float64(x) * i.renderScale)
+		y = int(float64(y) * i.renderScale)
+	}
+	return i.mipmap.original().At(x, y)
+}
+
 // ColorModel returns the color model of the image.
 func (i *Image) ColorModel() color.Model {
 	return color.RGBAModel
@@ -530,6 +798,12 @@ func (i *Image) ColorModel() color.Model {
 //
 // At always returns a transparent color if the image is disposed.
 //
+// The returned color is alpha-premultiplied, as Ebiten composites images in alpha-premultiplied form.
+// Compositing premultiplied colors with the regular alpha blending never applies alpha twice,
+// so the straight-alpha (non-premultiplied) color can be obtained on read-back, e.g.,
+// by color.NRGBAModel.Convert(img.At(x, y)) or by encoding the image with image/png.
+// Note that the precision of the straight-alpha color is low when the alpha value is small.
+//
 // Note that important logic should not rely on At result since
 // At might include a very slight error on some machines.
 //
@@ -538,14 +812,40 @@ func (i *Image) At(x, y int) color.Color {
 	if i.isDisposed() {
 		return color.RGBA{}
 	}
-	if i.bounds != nil && !image.Pt(x, y).In(*i.bounds) {
+	// Check the logical bounds even for a non-sub-image, since the underlying image of a scaled image can
+	// include the mapped point of a point out of the bounds.
+	if !image.Pt(x, y).In(i.Bounds()) {
 		return color.RGBA{}
 	}
 	i.resolvePixelsToSet(true)
-	r, g, b, a := i.mipmap.original().At(x, y)
+	r, g, b, a := i.at(x, y)
 	return color.RGBA{r, g, b, a}
 }
 
+// AlphaAt returns the alpha value at (x, y) of the image created with KeepAlphaMask.
+//
+// Unlike At, AlphaAt doesn't read pixels from GPU, so AlphaAt is fast and can be called before the main loop starts.
+// This is useful for pixel-perfect hit tests, e.g., ignoring clicks on the transparent regions of a sprite.
+//
+// AlphaAt returns the alpha values of the source image at the creation.
+// Rendering to the image after the creation is not reflected.
+//
+// AlphaAt returns 0 if (x, y) is out of the image's bounds.
+// AlphaAt returns an error if the image is not created with KeepAlphaMask or the image is disposed.
+func (i *Image) AlphaAt(x, y int) (uint8, error) {
+	i.copyCheck()
+	if i.isDisposed() {
+		return 0, errors.New("ebiten: the image is already disposed")
+	}
+	if i.alphaMask == nil {
+		return 0, errors.New("ebiten: AlphaAt requires an image created with KeepAlphaMask")
+	}
+	if !image.Pt(x, y).In(i.Bounds()) {
+		return 0, nil
+	}
+	return i.alphaMask.AlphaAt(x, y).A, nil
+}
+
 // Set sets the color at (x, y).
 //
 // Set loads pixels from GPU to system memory if necessary, which means that Set can be slow.
@@ -571,7 +871,7 @@ func (img *Image) Set(x, y int, clr color.Color) {
 		idx := 0
 		for j := 0; j < h; j++ {
 			for i := 0; i < w; i++ {
-				r, g, b, a := img.mipmap.original().At(i, j)
+				r, g, b, a := img.at(i, j)
 				pix[4*idx] = r
 				pix[4*idx+1] = g
 				pix[4*idx+2] = b
@@ -608,7 +908,11 @@ func (i *Image) resolvePixelsToSet(draw bool) {
 
 // Dispose disposes the image data. After disposing, most of image functions do nothing and returns meaningless values.
 //
-// Dispose is useful to save memory.
+// Dispose is useful to save memory. For example, images that are used only in a level can be disposed
+// when the level is unloaded. The image's region of the GPU memory is released.
+//
+// Drawing a disposed image onto another image by DrawImage or DrawTriangles panics.
+// Disposing a sub-image doesn't dispose the parent image, but disposing an image disposes its sub-images.
 //
 // When the image is disposed, Dipose does nothing.
 //
@@ -632,6 +936,14 @@ func (i *Image) Dispose() error {
 //
 // ReplacePixels may be slow (as for implementation, this calls glTexSubImage2D).
 //
+// To replace only a region of an image, e.g., for a procedural texture or a video frame,
+// call ReplacePixels of its sub-image:
+//
+//     img.SubImage(image.Rect(x, y, x+w, y+h)).(*ebiten.Image).ReplacePixels(p)
+//
+// This updates only the region of the texture without reallocating it, and keeps the other pixels.
+// Note that a sub-image is clipped by the image's bounds, and then len(p) must match the clipped size.
+//
 // When len(p) is not appropriate, ReplacePixels panics.
 //
 // When the image is disposed, ReplacePixels does nothing.
@@ -642,15 +954,35 @@ func (i *Image) ReplacePixels(p []byte) error {
 	if i.isDisposed() {
 		return nil
 	}
-	// TODO: Implement this.
-	if i.isSubimage() {
-		panic("render to a subimage is not implemented")
-	}
-	i.resolvePixelsToSet(false)
+	// A sub-image keeps the pixels outside of the region.
+	i.resolvePixelsToSet(i.isSubimage())
 	s := i.Bounds().Size()
 	if l := 4 * s.X * s.Y; len(p) != l {
 		panic(fmt.Sprintf("ebiten: len(p) was %d but must be %d", len(p), l))
 	}
+	if i.isSubimage() {
+		if s.X == 0 || s.Y == 0 {
+			return nil
+		}
+		if i.renderScale == 0 {
+			r := i.Bounds()
+			i.mipmap.original().ReplacePixelsRegion(p, r.Min.X, r.Min.Y, r.Dx(), r.Dy())
+			i.disposeMipmaps()
+			return nil
+		}
+	}
+	if i.renderScale != 0 {
+		// The underlying image has a different size. Replace the pixels via a temporary image.
+		img, _ := NewImage(s.X, s.Y, FilterDefault)
+		img.mipmap.original().ReplacePixels(p)
+		op := &DrawImageOptions{}
+		op.GeoM.Translate(float64(i.Bounds().Min.X), float64(i.Bounds().Min.Y))
+		op.CompositeMode = CompositeModeCopy
+		op.Filter = FilterLinear
+		i.drawImage(img, op)
+		_ = img.Dispose()
+		return nil
+	}
 	i.mipmap.original().ReplacePixels(p)
 	i.disposeMipmaps()
 	return nil
@@ -682,6 +1014,75 @@ type DrawImageOptions struct {
 	// Otherwise, Filter specified at DrawImageOptions is used.
 	Filter Filter
 
+	// MipmapBias is a bias added to the mipmap level selected for the draw.
+	// The default (zero) value is no bias.
+	//
+	// A positive value selects a smaller mipmap and softens the result.
+	// A negative value selects a larger mipmap and sharpens the result.
+	// A bias of 1 selects the mipmap as if the image were drawn at the half scale.
+	//
+	// MipmapBias is rounded to the nearest integer and added to the selected level.
+	// The result is clamped to the range of the available levels, so an extreme bias is safe.
+	// If MipmapBias is NaN, DrawImage panics.
+	//
+	// MipmapBias is effective only when the filter is FilterLinear, since mipmaps are used
+	// only with FilterLinear.
+	//
+	// Ebiten selects a mipmap level for each draw call rather than in the shader, so
+	// MipmapBias doesn't depend on GL_EXT_shader_texture_lod and works even on OpenGL ES 2.
+	// The level can't be less than the original image, so a negative bias has no effect on
+	// an image that is not scaled down.
+	MipmapBias float64
+
+	// AlphaTestThreshold is a threshold of the alpha test.
+	// The default (zero) value disables the alpha test.
+	//
+	// If AlphaTestThreshold is positive, the alpha values after applying ColorM that are less than
+	// AlphaTestThreshold become 0, and the other alpha values become 1.
+	// This is useful for sprites with 1-bit transparency (cutout sprites), especially with FilterLinear.
+	//
+	// The alpha test is a step of the color matrix, and doesn't discard pixels: the pixels failing the test are
+	// rendered as transparent. This works as discarding only with composite modes where a transparent source
+	// doesn't change the destination, i.e., CompositeModeSourceOver (the default), CompositeModeDestinationOver,
+	// CompositeModeSourceAtop, CompositeModeDestinationOut, CompositeModeXor and CompositeModeLighter.
+	// For example, with CompositeModeCopy, the pixels failing the test are overwritten with transparent.
+	AlphaTestThreshold float64
+
+	// ColorMInLinearSpace represents whether ColorM is applied in the linear color space.
+	// The default (zero) value is false, which means that ColorM is applied to sRGB colors as they are.
+	//
+	// Multiplying sRGB colors, e.g., tinting with ColorM.Scale, makes colors darker than expected.
+	// If ColorMInLinearSpace is true, colors are converted to the linear space before applying ColorM,
+	// and converted back after that. This makes tints perceptually correct.
+	ColorMInLinearSpace bool
+
+	// ColorMask is a set of color channels that are not written.
+	// The default (zero) value writes all the channels.
+	//
+	// Draw calls with different color masks are not batched.
+	ColorMask ColorMask
+
+	// Shader is a user-defined shader applied to the rendered colors.
+	// The default (zero) value is nil, which means that no user-defined shader is applied.
+	//
+	// Draw calls with different shaders are not batched.
+	//
+	// Note that this API is experimental.
+	Shader *Shader
+
+	// Uniforms is the values of the uniform variables of Shader, keyed by the variable names.
+	// The uniform variables not in Uniforms are 0.
+	//
+	// If Uniforms has a name that is not declared in Shader, or a value whose type doesn't match
+	// with the declaration, DrawImage or DrawTriangles panics.
+	// Uniforms is copied when drawing, so Uniforms can be modified after drawing.
+	// Uniforms is ignored when Shader is nil.
+	//
+	// Draw calls with different uniform values are not batched.
+	//
+	// Note that this API is experimental.
+	Uniforms Uniforms
+
 	// Deprecated (as of 1.5.0-alpha): Use SubImage instead.
 	ImageParts ImageParts
 
@@ -733,6 +1134,25 @@ func newVolatileImage(width, height int) *Image {
 	return i
 }
 
+// newScaledVolatileImage returns a volatile image whose underlying image is scaled by scale.
+// This is used for the screen rendered at a different resolution.
+func newScaledVolatileImage(width, height int, scale float64) *Image {
+	if scale == 1 {
+		return newVolatileImage(width, height)
+	}
+	w := int(math.Ceil(float64(width) * scale))
+	h := int(math.Ceil(float64(height) * scale))
+	i := &Image{
+		mipmap:       newMipmap(shareable.NewVolatileImage(w, h)),
+		renderScale:  scale,
+		renderWidth:  width,
+		renderHeight: height,
+	}
+	i.addr = i
+	runtime.SetFinalizer(i, (*Image).Dispose)
+	return i
+}
+
 // NewImageFromImage creates a new image with the given image (source).
 //
 // If source's width or height is less than 1 or more than device-dependent maximum size, NewImageFromImage panics.
@@ -742,11 +1162,12 @@ func newVolatileImage(width, height int) *Image {
 //
 // Error returned by NewImageFromImage is always nil as of 1.5.0-alpha.
 func NewImageFromImage(source image.Image, filter Filter) (*Image, error) {
-	size := source.Bounds().Size()
-
-	width, height := size.X, size.Y
+	return newImageFromPixels(source.Bounds().Size(), graphics.CopyImage(source), filter), nil
+}
 
-	s := shareable.NewImage(width, height)
+// newImageFromPixels creates a new image with the given size and the given premultiplied pixels.
+func newImageFromPixels(size image.Point, pixels []byte, filter Filter) *Image {
+	s := shareable.NewImage(size.X, size.Y)
 	i := &Image{
 		mipmap: newMipmap(s),
 		filter: filter,
@@ -754,7 +1175,79 @@ func NewImageFromImage(source image.Image, filter Filter) (*Image, error) {
 	i.addr = i
 	runtime.SetFinalizer(i, (*Image).Dispose)
 
-	_ = i.ReplacePixels(graphics.CopyImage(source))
+	_ = i.ReplacePixels(pixels)
+	return i
+}
+
+// NewImageFromImageOptions represents options for NewImageFromImageWithOptions.
+type NewImageFromImageOptions struct {
+	// Filter is a filter for backward compatibility. See NewImageFromImage.
+	//
+	// The default (zero) value is FilterDefault.
+	Filter Filter
+
+	// PremultipliedAlpha indicates that the color values of the source image are
+	// already premultiplied by alpha.
+	//
+	// Some image files like exported sprites store premultiplied color values even though
+	// they are decoded as straight-alpha images like image.NRGBA. Multiplying such color values
+	// by alpha again makes semi-transparent pixels, e.g., anti-aliased edges, darker.
+	// When PremultipliedAlpha is true, the color values of straight-alpha colors like color.NRGBA
+	// are used as they are without multiplying by alpha. The color values more than alpha are clamped.
+	// Colors of the other color models, e.g., color.RGBA, are already premultiplied and are not affected.
+	//
+	// The default (zero) value is false.
+	PremultipliedAlpha bool
+
+	// Region is the region of the source image to create the new image from.
+	//
+	// Only the pixels in Region are copied, so this is useful to create an image from a part of a big
+	// sprite sheet without copying the whole sheet. If the source image is *image.RGBA, the rows are
+	// copied directly regarding the source's stride.
+	//
+	// The default (zero) value is an empty rectangle, which means the whole source image.
+	Region image.Rectangle
+
+	// KeepAlphaMask indicates that the alpha values of the source image are kept in system memory
+	// for AlphaAt. This costs one byte per pixel.
+	//
+	// The default (zero) value is false.
+	KeepAlphaMask bool
+}
+
+// NewImageFromImageWithOptions creates a new image with the given image (source) and the given options.
+//
+// If options is nil, NewImageFromImageWithOptions is same as NewImageFromImage with FilterDefault.
+//
+// If source's width or height is less than 1 or more than device-dependent maximum size,
+// NewImageFromImageWithOptions panics.
+//
+// If options.Region is not empty and is not in the source's bounds, NewImageFromImageWithOptions returns an error.
+func NewImageFromImageWithOptions(source image.Image, options *NewImageFromImageOptions) (*Image, error) {
+	if options == nil {
+		options = &NewImageFromImageOptions{}
+	}
+	if r := options.Region; !r.Empty() {
+		if !r.In(source.Bounds()) {
+			return nil, fmt.Errorf("ebiten: the region %v must be in the source bounds %v", r, source.Bounds())
+		}
+		source = graphics.SubImage(source, r)
+	}
+
+	var pixels []byte
+	if options.PremultipliedAlpha {
+		pixels = graphics.CopyPremultipliedImage(source)
+	} else {
+		pixels = graphics.CopyImage(source)
+	}
+	i := newImageFromPixels(source.Bounds().Size(), pixels, options.Filter)
+
+	if options.KeepAlphaMask {
+		b := source.Bounds()
+		m := image.NewAlpha(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(m, m.Bounds(), source, b.Min, draw.Src)
+		i.alphaMask = m
+	}
 	return i, nil
 }
 
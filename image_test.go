@@ -17,13 +17,17 @@ package ebiten_test
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"image"
 	"image/color"
 	"image/draw"
 	_ "image/png"
 	"math"
 	"os"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	. "github.com/hajimehoshi/ebiten"
 	"github.com/hajimehoshi/ebiten/ebitenutil"
@@ -368,6 +372,38 @@ func TestImageReplacePixels(t *testing.T) {
 	}
 }
 
+func TestImageReplacePixelsRegion(t *testing.T) {
+	img, _ := NewImage(4, 4, FilterDefault)
+	img.Fill(color.RGBA{0xff, 0, 0, 0xff})
+	// Draw the image once so that the region is replaced after a drawing command.
+	dst, _ := NewImage(4, 4, FilterDefault)
+	dst.DrawImage(img, nil)
+	img.Set(0, 3, color.RGBA{0, 0xff, 0, 0xff})
+
+	r := image.Rect(1, 1, 3, 3)
+	pix := make([]byte, 4*r.Dx()*r.Dy())
+	for i := range pix {
+		pix[i] = 0xff
+	}
+	img.SubImage(r).(*Image).ReplacePixels(pix)
+
+	for j := 0; j < 4; j++ {
+		for i := 0; i < 4; i++ {
+			got := img.At(i, j)
+			want := color.RGBA{0xff, 0, 0, 0xff}
+			switch {
+			case image.Pt(i, j).In(r):
+				want = color.RGBA{0xff, 0xff, 0xff, 0xff}
+			case i == 0 && j == 3:
+				want = color.RGBA{0, 0xff, 0, 0xff}
+			}
+			if got != want {
+				t.Errorf("img.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
 func TestImageReplacePixelsNil(t *testing.T) {
 	defer func() {
 		if r := recover(); r == nil {
@@ -402,6 +438,58 @@ func TestImageDispose(t *testing.T) {
 	if got != want {
 		t.Errorf("img.At(0, 0) got: %v, want: %v", got, want)
 	}
+
+	// Disposing twice is safe.
+	if err := img.Dispose(); err != nil {
+		t.Errorf("img.Dipose() returns error: %v", err)
+	}
+}
+
+func TestImageDrawDisposed(t *testing.T) {
+	vs := []Vertex{
+		{DstX: 0, DstY: 0, SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 1, DstY: 0, SrcX: 1, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 0, DstY: 1, SrcX: 0, SrcY: 1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+	}
+	is := []uint16{0, 1, 2}
+
+	cases := []struct {
+		Name string
+		Draw func(dst, src *Image)
+	}{
+		{
+			Name: "DrawImage",
+			Draw: func(dst, src *Image) {
+				dst.DrawImage(src, nil)
+			},
+		},
+		{
+			Name: "DrawTriangles",
+			Draw: func(dst, src *Image) {
+				dst.DrawTriangles(vs, is, src, nil)
+			},
+		},
+	}
+	for _, c := range cases {
+		src, _ := NewImage(16, 16, FilterDefault)
+		dst, _ := NewImage(16, 16, FilterDefault)
+		src.Fill(color.White)
+		src.Dispose()
+
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("%s: drawing a disposed image must panic but not", c.Name)
+				}
+			}()
+			c.Draw(dst, src)
+		}()
+
+		// Drawing onto a disposed image does nothing.
+		dst.Dispose()
+		src, _ = NewImage(16, 16, FilterDefault)
+		c.Draw(dst, src)
+	}
 }
 
 func min(a, b int) int {
@@ -524,6 +612,28 @@ func TestImageFill(t *testing.T) {
 	}
 }
 
+func TestImageFillTranslucent(t *testing.T) {
+	const w, h = 16, 16
+	img, _ := NewImage(w, h, FilterNearest)
+
+	// A straight-alpha color is stored as premultiplied alpha.
+	img.Fill(color.NRGBA{0x80, 0x80, 0xff, 0x80})
+	got := img.At(w-1, h-1).(color.RGBA)
+	want := color.RGBA{0x40, 0x40, 0x80, 0x80}
+	if !sameColors(got, want, 1) {
+		t.Errorf("img At(%d, %d): got %v; want %v", w-1, h-1, got, want)
+	}
+
+	// Filling with the fully transparent color must not leave opaque black.
+	img.Fill(color.White)
+	img.Fill(color.RGBA{})
+	got = img.At(w-1, h-1).(color.RGBA)
+	want = color.RGBA{}
+	if got != want {
+		t.Errorf("img At(%d, %d): got %v; want %v", w-1, h-1, got, want)
+	}
+}
+
 // Issue #740
 func TestImageClear(t *testing.T) {
 	const w, h = 128, 256
@@ -739,6 +849,162 @@ func BenchmarkDrawImage(b *testing.B) {
 	}
 }
 
+// BenchmarkReplacePixelsRegion replaces a region of an image every frame.
+func BenchmarkReplacePixelsRegion(b *testing.B) {
+	img, _ := NewImage(256, 256, FilterNearest)
+	sub := img.SubImage(image.Rect(64, 64, 128, 128)).(*Image)
+	pix := make([]byte, 4*64*64)
+	for i := 0; i < b.N; i++ {
+		sub.ReplacePixels(pix)
+	}
+}
+
+// BenchmarkRecreateImage recreates a whole image every frame, which BenchmarkReplacePixelsRegion is
+// compared with.
+func BenchmarkRecreateImage(b *testing.B) {
+	src := image.NewRGBA(image.Rect(0, 0, 256, 256))
+	for i := 0; i < b.N; i++ {
+		img, _ := NewImageFromImage(src, FilterNearest)
+		img.Dispose()
+	}
+}
+
+const benchmarkSpriteNum = 1000
+
+// BenchmarkDrawImageSprites draws many sprites with individual DrawImage calls.
+// The successive calls are merged into one draw call internally.
+func BenchmarkDrawImageSprites(b *testing.B) {
+	dst, _ := NewImage(256, 256, FilterNearest)
+	src, _ := NewImage(16, 16, FilterNearest)
+	op := &DrawImageOptions{}
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < benchmarkSpriteNum; j++ {
+			op.GeoM.Reset()
+			op.GeoM.Translate(float64(j%16*16), float64(j/16%16*16))
+			dst.DrawImage(src, op)
+		}
+	}
+}
+
+// BenchmarkDrawTrianglesSprites draws many sprites with one DrawTriangles call.
+func BenchmarkDrawTrianglesSprites(b *testing.B) {
+	dst, _ := NewImage(256, 256, FilterNearest)
+	src, _ := NewImage(16, 16, FilterNearest)
+	vs := make([]Vertex, 0, 4*benchmarkSpriteNum)
+	is := make([]uint16, 0, 6*benchmarkSpriteNum)
+	for j := 0; j < benchmarkSpriteNum; j++ {
+		x, y := float32(j%16*16), float32(j/16%16*16)
+		n := uint16(len(vs))
+		vs = append(vs,
+			Vertex{DstX: x, DstY: y, SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+			Vertex{DstX: x + 16, DstY: y, SrcX: 16, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+			Vertex{DstX: x, DstY: y + 16, SrcX: 0, SrcY: 16, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+			Vertex{DstX: x + 16, DstY: y + 16, SrcX: 16, SrcY: 16, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		)
+		is = append(is, n, n+1, n+2, n+1, n+2, n+3)
+	}
+	for i := 0; i < b.N; i++ {
+		dst.DrawTriangles(vs, is, src, nil)
+	}
+}
+
+// BenchmarkDrawImageTilemap draws a static tilemap in the same way at every frame.
+// The vertices are put into the reused buffers, and are uploaded to the GPU with one call per frame.
+func BenchmarkDrawImageTilemap(b *testing.B) {
+	const (
+		tileSize = 16
+		tileXNum = 16
+		tileYNum = 16
+	)
+	dst, _ := NewImage(tileSize*tileXNum, tileSize*tileYNum, FilterNearest)
+	tiles, _ := NewImage(tileSize*4, tileSize*4, FilterNearest)
+	subs := make([]*Image, 16)
+	for i := range subs {
+		x, y := i%4*tileSize, i/4*tileSize
+		subs[i] = tiles.SubImage(image.Rect(x, y, x+tileSize, y+tileSize)).(*Image)
+	}
+
+	op := &DrawImageOptions{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < tileYNum; j++ {
+			for k := 0; k < tileXNum; k++ {
+				op.GeoM.Reset()
+				op.GeoM.Translate(float64(k*tileSize), float64(j*tileSize))
+				dst.DrawImage(subs[(j+k)%len(subs)], op)
+			}
+		}
+		// Flush the commands as the end of a frame does.
+		_ = dst.At(0, 0)
+	}
+}
+
+// BenchmarkDrawImageColorScale draws sprites with different alpha values.
+// ColorM with only scaling is applied as vertex colors, and the draw calls are merged.
+func BenchmarkDrawImageColorScale(b *testing.B) {
+	dst, _ := NewImage(256, 256, FilterNearest)
+	src, _ := NewImage(16, 16, FilterNearest)
+	op := &DrawImageOptions{}
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < benchmarkSpriteNum; j++ {
+			op.GeoM.Reset()
+			op.GeoM.Translate(float64(j%16*16), float64(j/16%16*16))
+			op.ColorM.Reset()
+			op.ColorM.Scale(1, 1, 1, float64(j)/benchmarkSpriteNum)
+			dst.DrawImage(src, op)
+		}
+	}
+}
+
+// BenchmarkDrawImageColorMatrix draws sprites with different color matrices.
+// Each draw call requires updating the color matrix uniforms, and the draw calls are not merged.
+func BenchmarkDrawImageColorMatrix(b *testing.B) {
+	dst, _ := NewImage(256, 256, FilterNearest)
+	src, _ := NewImage(16, 16, FilterNearest)
+	op := &DrawImageOptions{}
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < benchmarkSpriteNum; j++ {
+			op.GeoM.Reset()
+			op.GeoM.Translate(float64(j%16*16), float64(j/16%16*16))
+			op.ColorM.Reset()
+			op.ColorM.Scale(1, 1, 1, float64(j)/benchmarkSpriteNum)
+			op.ColorM.Translate(0, 0, 0, 1.0/benchmarkSpriteNum)
+			dst.DrawImage(src, op)
+		}
+	}
+}
+
+func BenchmarkDrawImageWithUniforms(b *testing.B) {
+	const src = `
+uniform float bench_time;
+uniform vec2 bench_offset;
+
+vec4 shade(vec4 color) {
+  return color * (sin(bench_time) + bench_offset.x + bench_offset.y);
+}
+`
+	s, err := NewShader(src)
+	if err != nil {
+		b.Skipf("user-defined shaders are not available: %v", err)
+	}
+	defer s.Dispose()
+
+	img0, _ := NewImage(16, 16, FilterNearest)
+	img1, _ := NewImage(16, 16, FilterNearest)
+	op := &DrawImageOptions{}
+	op.Shader = s
+	op.Uniforms = Uniforms{
+		"bench_time":   float32(0),
+		"bench_offset": []float32{0, 0},
+	}
+	for i := 0; i < b.N; i++ {
+		// Change the uniforms at every frame.
+		op.Uniforms["bench_time"] = float32(i / 60)
+		img0.DrawImage(img1, op)
+	}
+}
+
 func TestImageLinearGradiation(t *testing.T) {
 	img0, _ := NewImage(2, 2, FilterNearest)
 	img0.ReplacePixels([]byte{
@@ -790,6 +1056,37 @@ func TestImageLinearEdges(t *testing.T) {
 	}
 }
 
+func TestImageSubImageSamplesOnlyInside(t *testing.T) {
+	src, _ := NewImage(32, 32, FilterDefault)
+	src.Fill(color.RGBA{0, 0xff, 0, 0xff})
+	ebitenutil.DrawRect(src, 8, 8, 16, 16, color.RGBA{0xff, 0, 0, 0xff})
+	sub := src.SubImage(image.Rect(8, 8, 24, 24)).(*Image)
+
+	for _, f := range SupportedFilters() {
+		for _, a := range SupportedAddresses() {
+			dst, _ := NewImage(64, 64, FilterDefault)
+			op := &DrawTrianglesOptions{}
+			op.Filter = f
+			op.Address = a
+			vs := []Vertex{
+				{DstX: 0, DstY: 0, SrcX: 4, SrcY: 4, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+				{DstX: 64, DstY: 0, SrcX: 28, SrcY: 4, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+				{DstX: 0, DstY: 64, SrcX: 4, SrcY: 28, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+				{DstX: 64, DstY: 64, SrcX: 28, SrcY: 28, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+			}
+			dst.DrawTriangles(vs, []uint16{0, 1, 2, 1, 2, 3}, sub, op)
+
+			for j := 0; j < 64; j++ {
+				for i := 0; i < 64; i++ {
+					if got := dst.At(i, j).(color.RGBA).G; got > 1 {
+						t.Errorf("filter: %d, address: %d, dst At(%d, %d).G: got %#v, want: 0", f, a, i, j, got)
+					}
+				}
+			}
+		}
+	}
+}
+
 func TestImageOutside(t *testing.T) {
 	src, _ := NewImage(5, 10, FilterNearest) // internal texture size is 8x16.
 	dst, _ := NewImage(4, 4, FilterNearest)
@@ -875,6 +1172,71 @@ func TestImageSize(t *testing.T) {
 	}
 }
 
+func TestImageSizeFromImage(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Source image.Image
+	}{
+		{"NPOT", image.NewRGBA(image.Rect(0, 0, 57, 26))},
+		{"POT", image.NewRGBA(image.Rect(0, 0, 64, 32))},
+		{"non-zero origin", image.NewRGBA(image.Rect(3, 5, 60, 31))},
+		{"sub-image", image.NewRGBA(image.Rect(0, 0, 128, 128)).SubImage(image.Rect(10, 20, 67, 46))},
+	}
+	for _, c := range cases {
+		img, _ := NewImageFromImage(c.Source, FilterDefault)
+		want := c.Source.Bounds().Size()
+		gotW, gotH := img.Size()
+		if gotW != want.X || gotH != want.Y {
+			t.Errorf("%s: got: (%d, %d), want: (%d, %d)", c.Name, gotW, gotH, want.X, want.Y)
+		}
+		if got := img.Bounds(); got != image.Rect(0, 0, want.X, want.Y) {
+			t.Errorf("%s: Bounds(): got: %v, want: %v", c.Name, got, image.Rect(0, 0, want.X, want.Y))
+		}
+	}
+}
+
+func TestImageFromImageRegion(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	for j := 0; j < 16; j++ {
+		for i := 0; i < 16; i++ {
+			src.Set(i, j, color.RGBA{uint8(i * 16), uint8(j * 16), 0, 0xff})
+		}
+	}
+
+	r := image.Rect(3, 5, 12, 9)
+	img, err := NewImageFromImageWithOptions(src, &NewImageFromImageOptions{
+		Region: r,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Compare with a manual crop.
+	crop := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(crop, crop.Bounds(), src, r.Min, draw.Src)
+	want, _ := NewImageFromImage(crop, FilterDefault)
+
+	if got, want := img.Bounds(), want.Bounds(); got != want {
+		t.Errorf("Bounds(): got: %v, want: %v", got, want)
+	}
+	for j := 0; j < r.Dy(); j++ {
+		for i := 0; i < r.Dx(); i++ {
+			got := img.At(i, j)
+			want := want.At(i, j)
+			if got != want {
+				t.Errorf("img.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+
+	// A region out of the bounds is an error.
+	if _, err := NewImageFromImageWithOptions(src, &NewImageFromImageOptions{
+		Region: image.Rect(8, 8, 17, 12),
+	}); err == nil {
+		t.Errorf("NewImageFromImageWithOptions with a region out of the bounds must return an error")
+	}
+}
+
 func TestImageSize1(t *testing.T) {
 	src, _ := NewImage(1, 1, FilterNearest)
 	dst, _ := NewImage(1, 1, FilterNearest)
@@ -1340,6 +1702,86 @@ func TestImageAddressRepeat(t *testing.T) {
 	}
 }
 
+func TestImageAddressMirroredRepeat(t *testing.T) {
+	const w, h = 16, 16
+	src, _ := NewImage(w, h, FilterDefault)
+	dst, _ := NewImage(w, h, FilterDefault)
+	pix := make([]byte, 4*w*h)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			idx := 4 * (i + j*w)
+			if 4 <= i && i < 8 && 4 <= j && j < 8 {
+				pix[idx] = byte(i-4) * 0x10
+				pix[idx+1] = byte(j-4) * 0x10
+				pix[idx+2] = 0
+				pix[idx+3] = 0xff
+			} else {
+				pix[idx] = 0
+				pix[idx+1] = 0
+				pix[idx+2] = 0xff
+				pix[idx+3] = 0xff
+			}
+		}
+	}
+	src.ReplacePixels(pix)
+
+	vs := []Vertex{
+		{DstX: 0, DstY: 0, SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: w, DstY: 0, SrcX: w, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 0, DstY: h, SrcX: 0, SrcY: h, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: w, DstY: h, SrcX: w, SrcY: h, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+	}
+	is := []uint16{0, 1, 2, 1, 2, 3}
+	op := &DrawTrianglesOptions{}
+	op.Address = AddressMirroredRepeat
+	dst.DrawTriangles(vs, is, src.SubImage(image.Rect(4, 4, 8, 8)).(*Image), op)
+
+	mirror := func(x int) byte {
+		x = (x + 4) % 8
+		if x >= 4 {
+			x = 7 - x
+		}
+		return byte(x)
+	}
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{mirror(i) * 0x10, mirror(j) * 0x10, 0, 0xff}
+			if !sameColors(got, want, 1) {
+				t.Errorf("dst.At(%d, %d): got %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageAddressMirroredRepeatBoundary(t *testing.T) {
+	src, _ := NewImage(2, 1, FilterDefault)
+	src.ReplacePixels([]byte{
+		0xff, 0, 0, 0xff, 0, 0xff, 0, 0xff,
+	})
+	dst, _ := NewImage(4, 1, FilterDefault)
+
+	// The pixel centers of dst sample the source exactly at 0, 1, 2 and 3. 2 is the mirror boundary.
+	vs := []Vertex{
+		{DstX: 0, DstY: 0, SrcX: -0.5, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 4, DstY: 0, SrcX: 3.5, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 0, DstY: 1, SrcX: -0.5, SrcY: 1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 4, DstY: 1, SrcX: 3.5, SrcY: 1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+	}
+	is := []uint16{0, 1, 2, 1, 2, 3}
+	op := &DrawTrianglesOptions{}
+	op.Address = AddressMirroredRepeat
+	dst.DrawTriangles(vs, is, src, op)
+
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	green := color.RGBA{0, 0xff, 0, 0xff}
+	for i, want := range []color.RGBA{red, green, green, green} {
+		if got := dst.At(i, 0); got != want {
+			t.Errorf("dst.At(%d, 0): got %v, want: %v", i, got, want)
+		}
+	}
+}
+
 func TestImageReplacePixelsAfterClear(t *testing.T) {
 	const w, h = 256, 256
 	img, _ := NewImage(w, h, FilterDefault)
@@ -1447,3 +1889,1340 @@ func TestImageSetAndDraw(t *testing.T) {
 		}
 	}
 }
+
+func TestImageStraightAlphaReadBack(t *testing.T) {
+	const w, h = 16, 16
+	red, _ := NewImage(w, h, FilterDefault)
+	red.Fill(color.RGBA{0xff, 0, 0, 0xff})
+	blue, _ := NewImage(w, h, FilterDefault)
+	blue.Fill(color.RGBA{0, 0, 0xff, 0xff})
+
+	dst, _ := NewImage(w, h, FilterDefault)
+	op := &DrawImageOptions{}
+	op.ColorM.Scale(1, 1, 1, 0.5)
+	dst.DrawImage(red, op)
+	dst.DrawImage(blue, op)
+
+	// The premultiplied color is (0.25, 0, 0.5, 0.75).
+	got := color.NRGBAModel.Convert(dst.At(0, 0)).(color.NRGBA)
+	want := color.NRGBA{0x55, 0, 0xaa, 0xbf}
+	if abs(int(got.R)-int(want.R)) > 2 ||
+		abs(int(got.G)-int(want.G)) > 2 ||
+		abs(int(got.B)-int(want.B)) > 2 ||
+		abs(int(got.A)-int(want.A)) > 2 {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
+
+func TestImageAlphaTest(t *testing.T) {
+	const w, h = 2, 1
+	src, _ := NewImage(w, h, FilterDefault)
+	src.ReplacePixels([]byte{
+		0x40, 0x40, 0x40, 0x40,
+		0xc0, 0xc0, 0xc0, 0xc0,
+	})
+	dst, _ := NewImage(w, h, FilterDefault)
+
+	cases := []struct {
+		Threshold float64
+		Want0     color.RGBA
+		Want1     color.RGBA
+	}{
+		{0, color.RGBA{0x40, 0x40, 0x40, 0x40}, color.RGBA{0xc0, 0xc0, 0xc0, 0xc0}},
+		{0.5, color.RGBA{}, color.RGBA{0xff, 0xff, 0xff, 0xff}},
+		{0.1, color.RGBA{0xff, 0xff, 0xff, 0xff}, color.RGBA{0xff, 0xff, 0xff, 0xff}},
+	}
+	for _, c := range cases {
+		dst.Clear()
+		op := &DrawImageOptions{}
+		op.AlphaTestThreshold = c.Threshold
+		dst.DrawImage(src, op)
+		if got := dst.At(0, 0).(color.RGBA); !sameColors(got, c.Want0, 1) {
+			t.Errorf("threshold: %f, dst.At(0, 0): got: %v, want: %v", c.Threshold, got, c.Want0)
+		}
+		if got := dst.At(1, 0).(color.RGBA); !sameColors(got, c.Want1, 1) {
+			t.Errorf("threshold: %f, dst.At(1, 0): got: %v, want: %v", c.Threshold, got, c.Want1)
+		}
+	}
+}
+
+func TestImageAlphaTestWithCompositeModes(t *testing.T) {
+	const w, h = 2, 1
+	src, _ := NewImage(w, h, FilterDefault)
+	src.ReplacePixels([]byte{
+		0x40, 0, 0, 0x40,
+		0xc0, 0, 0, 0xc0,
+	})
+	dst, _ := NewImage(w, h, FilterDefault)
+
+	blue := color.RGBA{0, 0, 0xff, 0xff}
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	cases := []struct {
+		Mode  CompositeMode
+		Want0 color.RGBA
+	}{
+		// The pixel failing the test doesn't change the destination with source-over.
+		{CompositeModeSourceOver, blue},
+		// The pixel failing the test is not discarded but overwritten with transparent with copy.
+		{CompositeModeCopy, color.RGBA{}},
+	}
+	for _, c := range cases {
+		dst.Fill(blue)
+		op := &DrawImageOptions{}
+		op.AlphaTestThreshold = 0.5
+		op.CompositeMode = c.Mode
+		dst.DrawImage(src, op)
+		if got := dst.At(0, 0).(color.RGBA); !sameColors(got, c.Want0, 1) {
+			t.Errorf("mode: %d, dst.At(0, 0): got: %v, want: %v", c.Mode, got, c.Want0)
+		}
+		if got := dst.At(1, 0).(color.RGBA); !sameColors(got, red, 1) {
+			t.Errorf("mode: %d, dst.At(1, 0): got: %v, want: %v", c.Mode, got, red)
+		}
+	}
+}
+
+func TestImageDrawToSubImage(t *testing.T) {
+	const w, h = 16, 16
+	src, _ := NewImage(w, h, FilterDefault)
+	src.Fill(color.RGBA{0xff, 0, 0, 0xff})
+	dst, _ := NewImage(w, h, FilterDefault)
+
+	r := image.Rect(4, 4, 8, 8)
+	sub := dst.SubImage(r).(*Image)
+	op := &DrawImageOptions{}
+	op.GeoM.Translate(2, 2)
+	sub.DrawImage(src, op)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{}
+			if image.Pt(i, j).In(r) {
+				want = color.RGBA{0xff, 0, 0, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+
+	sub.Fill(color.RGBA{0, 0xff, 0, 0xff})
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{}
+			if image.Pt(i, j).In(r) {
+				want = color.RGBA{0, 0xff, 0, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageDrawToNestedSubImage(t *testing.T) {
+	const w, h = 16, 16
+	src, _ := NewImage(w, h, FilterDefault)
+	src.Fill(color.RGBA{0xff, 0, 0, 0xff})
+	dst, _ := NewImage(w, h, FilterDefault)
+
+	r0 := image.Rect(2, 2, 10, 10)
+	r1 := image.Rect(6, 6, 14, 14)
+	sub := dst.SubImage(r0).(*Image).SubImage(r1).(*Image)
+	if got, want := sub.Bounds(), r0.Intersect(r1); got != want {
+		t.Errorf("sub.Bounds(): got: %v, want: %v", got, want)
+	}
+
+	// Draw a full-size image. Only the intersection is rendered.
+	sub.DrawImage(src, nil)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{}
+			if image.Pt(i, j).In(r0.Intersect(r1)) {
+				want = color.RGBA{0xff, 0, 0, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageDrawWithShapeMask(t *testing.T) {
+	const w, h = 16, 16
+	src, _ := NewImage(w, h, FilterDefault)
+	src.Fill(color.RGBA{0xff, 0, 0, 0xff})
+	dst, _ := NewImage(w, h, FilterDefault)
+	dst.Fill(color.RGBA{0, 0, 0xff, 0xff})
+
+	// Draw the source only inside a circle via a layer image.
+	layer, _ := NewImage(w, h, FilterDefault)
+	ebitenutil.DrawCircle(layer, w/2, h/2, 4, color.White)
+	op := &DrawImageOptions{}
+	op.CompositeMode = CompositeModeSourceIn
+	layer.DrawImage(src, op)
+	dst.DrawImage(layer, nil)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			dx, dy := float64(i)+0.5-w/2, float64(j)+0.5-h/2
+			d := math.Sqrt(dx*dx + dy*dy)
+			var want color.RGBA
+			switch {
+			case d < 3:
+				want = color.RGBA{0xff, 0, 0, 0xff}
+			case d > 5:
+				// The pixels outside of the mask are not changed.
+				want = color.RGBA{0, 0, 0xff, 0xff}
+			default:
+				continue
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageColorMInLinearSpace(t *testing.T) {
+	const w, h = 16, 16
+	src, _ := NewImage(w, h, FilterDefault)
+	src.Fill(color.White)
+	dst, _ := NewImage(w, h, FilterDefault)
+
+	cases := []struct {
+		InLinearSpace bool
+		Want          color.RGBA
+	}{
+		// 0.5 in the sRGB space.
+		{false, color.RGBA{0x80, 0x80, 0x80, 0xff}},
+		// 0.5 in the linear space is 0.5^(1/2.2) ≈ 0.73 in the sRGB space.
+		{true, color.RGBA{0xba, 0xba, 0xba, 0xff}},
+	}
+	for _, c := range cases {
+		dst.Clear()
+		op := &DrawImageOptions{}
+		op.ColorM.Scale(0.5, 0.5, 0.5, 1)
+		op.ColorMInLinearSpace = c.InLinearSpace
+		dst.DrawImage(src, op)
+		if got := dst.At(0, 0).(color.RGBA); !sameColors(got, c.Want, 2) {
+			t.Errorf("in linear space: %v, got: %v, want: %v", c.InLinearSpace, got, c.Want)
+		}
+	}
+}
+
+func TestImageVertexColorsInLinearSpace(t *testing.T) {
+	const w, h = 16, 16
+	src, _ := NewImage(w, h, FilterDefault)
+	src.Fill(color.White)
+	dst, _ := NewImage(w, h, FilterDefault)
+
+	vs := []Vertex{
+		{DstX: 0, DstY: 0, SrcX: 0, SrcY: 0, ColorR: 0.5, ColorG: 0.5, ColorB: 0.5, ColorA: 1},
+		{DstX: w, DstY: 0, SrcX: w, SrcY: 0, ColorR: 0.5, ColorG: 0.5, ColorB: 0.5, ColorA: 1},
+		{DstX: 0, DstY: h, SrcX: 0, SrcY: h, ColorR: 0.5, ColorG: 0.5, ColorB: 0.5, ColorA: 1},
+		{DstX: w, DstY: h, SrcX: w, SrcY: h, ColorR: 0.5, ColorG: 0.5, ColorB: 0.5, ColorA: 1},
+	}
+	is := []uint16{0, 1, 2, 1, 2, 3}
+
+	cases := []struct {
+		InLinearSpace bool
+		Want          color.RGBA
+	}{
+		{false, color.RGBA{0x80, 0x80, 0x80, 0xff}},
+		// Even with the identity ColorM, the vertex colors are applied in the linear space.
+		{true, color.RGBA{0xba, 0xba, 0xba, 0xff}},
+	}
+	for _, c := range cases {
+		dst.Clear()
+		op := &DrawTrianglesOptions{}
+		op.ColorMInLinearSpace = c.InLinearSpace
+		dst.DrawTriangles(vs, is, src, op)
+		if got := dst.At(0, 0).(color.RGBA); !sameColors(got, c.Want, 2) {
+			t.Errorf("in linear space: %v, got: %v, want: %v", c.InLinearSpace, got, c.Want)
+		}
+	}
+}
+
+func TestImageColorMask(t *testing.T) {
+	const w, h = 16, 16
+	src, _ := NewImage(w, h, FilterDefault)
+	src.Fill(color.RGBA{0xff, 0xff, 0xff, 0xff})
+
+	cases := []struct {
+		Mask ColorMask
+		Want color.RGBA
+	}{
+		{0, color.RGBA{0xff, 0xff, 0xff, 0xff}},
+		{ColorMaskAlpha, color.RGBA{0xff, 0xff, 0xff, 0x40}},
+		{ColorMaskRed | ColorMaskBlue, color.RGBA{0x20, 0xff, 0x20, 0xff}},
+	}
+	for _, c := range cases {
+		dst, _ := NewImage(w, h, FilterDefault)
+		dst.Fill(color.RGBA{0x20, 0x20, 0x20, 0x40})
+		op := &DrawImageOptions{}
+		op.ColorMask = c.Mask
+		op.CompositeMode = CompositeModeCopy
+		dst.DrawImage(src, op)
+		if got := dst.At(0, 0).(color.RGBA); got != c.Want {
+			t.Errorf("mask: %d, got: %v, want: %v", c.Mask, got, c.Want)
+		}
+	}
+}
+
+func TestImageLuminanceMask(t *testing.T) {
+	const w, h = 3, 1
+	mask, _ := NewImage(w, h, FilterDefault)
+	mask.ReplacePixels([]byte{
+		0xff, 0xff, 0xff, 0xff,
+		0x80, 0x80, 0x80, 0xff,
+		0, 0, 0, 0xff,
+	})
+
+	dst, _ := NewImage(w, h, FilterDefault)
+	dst.Fill(color.RGBA{0xff, 0, 0, 0xff})
+
+	op := &DrawImageOptions{}
+	op.ColorM.LuminanceToAlpha()
+	op.CompositeMode = CompositeModeDestinationIn
+	dst.DrawImage(mask, op)
+
+	for i, a := range []byte{0xff, 0x80, 0} {
+		got := dst.At(i, 0).(color.RGBA)
+		want := color.RGBA{a, 0, 0, a}
+		if !sameColors(got, want, 1) {
+			t.Errorf("dst.At(%d, 0): got: %v, want: %v", i, got, want)
+		}
+	}
+}
+
+func TestImageDrawOptionsNotLeaked(t *testing.T) {
+	const w, h = 16, 16
+	src, _ := NewImage(w, h, FilterDefault)
+	src.Fill(color.RGBA{0x80, 0x80, 0x80, 0x80})
+
+	dst, _ := NewImage(w, h, FilterDefault)
+	dst.Fill(color.RGBA{0x20, 0x20, 0x20, 0x40})
+
+	// A subroutine drawing with non-default state on a sub-image.
+	sub := dst.SubImage(image.Rect(0, 0, w/2, h/2)).(*Image)
+	op := &DrawImageOptions{}
+	op.GeoM.Scale(2, 2)
+	op.CompositeMode = CompositeModeCopy
+	op.ColorMask = ColorMaskAlpha
+	op.Filter = FilterLinear
+	sub.DrawImage(src, op)
+
+	// A drawing call with the default options must not be affected by the previous call.
+	dst.DrawImage(src, nil)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			var want color.RGBA
+			if i < w/2 && j < h/2 {
+				// The destination is (0x80, 0x80, 0x80, 0x40) after the subroutine.
+				want = color.RGBA{0xc0, 0xc0, 0xc0, 0xa0}
+			} else {
+				want = color.RGBA{0x90, 0x90, 0x90, 0xa0}
+			}
+			if !sameColors(got, want, 1) {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageBicubic(t *testing.T) {
+	const (
+		w, h  = 4, 4
+		scale = 4
+	)
+	pix := make([]byte, 4*w*h)
+	for i := 0; i < w*h; i++ {
+		pix[4*i] = byte(i * 0x10)
+		pix[4*i+1] = byte(0xff - i*0x10)
+		pix[4*i+2] = byte((i % 3) * 0x70)
+		pix[4*i+3] = 0xff
+	}
+	src, _ := NewImage(w, h, FilterDefault)
+	src.ReplacePixels(pix)
+
+	dst, _ := NewImage(w*scale, h*scale, FilterDefault)
+	op := &DrawImageOptions{}
+	op.GeoM.Scale(scale, scale)
+	op.Filter = FilterBicubic
+	dst.DrawImage(src, op)
+
+	weights := func(f float64) [4]float64 {
+		return [4]float64{
+			f * (-0.5 + f*(1.0-0.5*f)),
+			1.0 + f*f*(-2.5+1.5*f),
+			f * (0.5 + f*(2.0-1.5*f)),
+			f * f * (-0.5 + 0.5*f),
+		}
+	}
+	texel := func(x, y, c int) float64 {
+		if x < 0 || y < 0 || x >= w || y >= h {
+			return 0
+		}
+		return float64(pix[4*(x+y*w)+c]) / 0xff
+	}
+	clamp := func(x float64) float64 {
+		return math.Max(0, math.Min(1, x))
+	}
+
+	for j := 0; j < h*scale; j++ {
+		for i := 0; i < w*scale; i++ {
+			qx := (float64(i)+0.5)/scale - 0.5
+			qy := (float64(j)+0.5)/scale - 0.5
+			x0, y0 := int(math.Floor(qx)), int(math.Floor(qy))
+			wx := weights(qx - math.Floor(qx))
+			wy := weights(qy - math.Floor(qy))
+
+			var c [4]float64
+			for k := 0; k < 4; k++ {
+				for y := 0; y < 4; y++ {
+					for x := 0; x < 4; x++ {
+						c[k] += wx[x] * wy[y] * texel(x0+x-1, y0+y-1, k)
+					}
+				}
+				c[k] = clamp(c[k])
+			}
+			for k := 0; k < 3; k++ {
+				c[k] = math.Min(c[k], c[3])
+			}
+
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{byte(math.Round(c[0] * 0xff)), byte(math.Round(c[1] * 0xff)), byte(math.Round(c[2] * 0xff)), byte(math.Round(c[3] * 0xff))}
+			if !sameColors(got, want, 2) {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageCustomCompositeMode(t *testing.T) {
+	const w, h = 16, 16
+	src, _ := NewImage(w, h, FilterDefault)
+	src.Fill(color.RGBA{0x80, 0x80, 0x80, 0xff})
+
+	cases := []struct {
+		Name string
+		Mode CompositeMode
+		Want color.RGBA
+	}{
+		{"multiply", CustomCompositeMode(BlendFactorDstColor, BlendFactorOneMinusSrcAlpha), color.RGBA{0x40, 0x20, 0x80, 0xff}},
+		{"screen", CustomCompositeMode(BlendFactorOne, BlendFactorOneMinusSrcColor), color.RGBA{0xc0, 0xa0, 0xff, 0xff}},
+		{"source-over", CompositeModeSourceOver, color.RGBA{0x80, 0x80, 0x80, 0xff}},
+		{"lighter", CustomCompositeMode(BlendFactorOne, BlendFactorOne), color.RGBA{0xff, 0xc0, 0xff, 0xff}},
+	}
+
+	// Draw all the cases before reading pixels so that the blend state changes between the drawing calls.
+	dsts := make([]*Image, len(cases))
+	for i, c := range cases {
+		dst, _ := NewImage(w, h, FilterDefault)
+		dst.Fill(color.RGBA{0x80, 0x40, 0xff, 0xff})
+		op := &DrawImageOptions{}
+		op.CompositeMode = c.Mode
+		dst.DrawImage(src, op)
+		dsts[i] = dst
+	}
+	for i, c := range cases {
+		if got := dsts[i].At(0, 0).(color.RGBA); !sameColors(got, c.Want, 2) {
+			t.Errorf("%s: got: %v, want: %v", c.Name, got, c.Want)
+		}
+	}
+}
+
+func TestImageNearestIntegerScale(t *testing.T) {
+	const (
+		w     = 8
+		h     = 8
+		scale = 3
+	)
+	pix := make([]byte, 4*w*h)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			idx := 4 * (i + j*w)
+			pix[idx] = byte(i * 0x20)
+			pix[idx+1] = byte(j * 0x20)
+			pix[idx+2] = byte((i + j) * 0x10)
+			pix[idx+3] = 0xff
+		}
+	}
+	src, _ := NewImage(w, h, FilterDefault)
+	src.ReplacePixels(pix)
+
+	dst, _ := NewImage(w*scale, h*scale, FilterDefault)
+	op := &DrawImageOptions{}
+	op.GeoM.Scale(scale, scale)
+	op.Filter = FilterNearest
+	dst.DrawImage(src, op)
+
+	for j := 0; j < h*scale; j++ {
+		for i := 0; i < w*scale; i++ {
+			got := dst.At(i, j)
+			want := src.At(i/scale, j/scale)
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageFromImagePremultipliedAlpha(t *testing.T) {
+	// An anti-aliased edge of a white sprite, whose color values are premultiplied
+	// but stored in a straight-alpha image.
+	src := &image.NRGBA{
+		Pix:    []uint8{0xff, 0xff, 0xff, 0xff, 0x80, 0x80, 0x80, 0x80, 0x40, 0x40, 0x40, 0x40, 0, 0, 0, 0},
+		Stride: 16,
+		Rect:   image.Rect(0, 0, 4, 1),
+	}
+
+	straight, _ := NewImageFromImage(src, FilterDefault)
+	premultiplied, _ := NewImageFromImageWithOptions(src, &NewImageFromImageOptions{
+		PremultipliedAlpha: true,
+	})
+
+	for i := 0; i < 4; i++ {
+		c := src.Pix[4*i]
+		a := src.Pix[4*i+3]
+
+		// Without the option, the color values are multiplied by alpha again and the edge gets darker.
+		want := color.RGBA{uint8(int(c) * int(a) / 0xff), uint8(int(c) * int(a) / 0xff), uint8(int(c) * int(a) / 0xff), a}
+		got := straight.At(i, 0).(color.RGBA)
+		if !sameColors(got, want, 1) {
+			t.Errorf("straight: At(%d, 0): got: %v, want: %v", i, got, want)
+		}
+
+		want = color.RGBA{c, c, c, a}
+		got = premultiplied.At(i, 0).(color.RGBA)
+		if got != want {
+			t.Errorf("premultiplied: At(%d, 0): got: %v, want: %v", i, got, want)
+		}
+	}
+}
+
+func TestImageShader(t *testing.T) {
+	const src = `
+uniform float grayscale_rate;
+
+vec4 shade(vec4 color) {
+  float y = dot(color.rgb, vec3(0.299, 0.587, 0.114));
+  return vec4(mix(color.rgb, vec3(y), grayscale_rate), color.a);
+}
+`
+	s, err := NewShader(src)
+	if err != nil {
+		t.Skipf("user-defined shaders are not available: %v", err)
+	}
+	defer s.Dispose()
+
+	const w, h = 16, 16
+	srcImg, _ := NewImage(w, h, FilterDefault)
+	srcImg.Fill(color.RGBA{0xff, 0x80, 0, 0xff})
+
+	cases := []struct {
+		Rate float32
+		Want color.RGBA
+	}{
+		{
+			Rate: 0,
+			Want: color.RGBA{0xff, 0x80, 0, 0xff},
+		},
+		{
+			Rate: 1,
+			Want: color.RGBA{0x97, 0x97, 0x97, 0xff},
+		},
+	}
+	for _, c := range cases {
+		dst, _ := NewImage(w, h, FilterDefault)
+		op := &DrawImageOptions{}
+		op.Shader = s
+		op.Uniforms = Uniforms{
+			"grayscale_rate": c.Rate,
+		}
+		dst.DrawImage(srcImg, op)
+
+		got := dst.At(0, 0).(color.RGBA)
+		if !sameColors(got, c.Want, 2) {
+			t.Errorf("rate: %f: got: %v, want: %v", c.Rate, got, c.Want)
+		}
+	}
+}
+
+func TestImageShaderUniformTypes(t *testing.T) {
+	const src = `
+uniform float shader_scale;
+uniform vec2 shader_rg;
+uniform vec3 shader_rgb;
+uniform vec4 shader_rgba;
+uniform mat4 shader_matrix;
+
+vec4 shade(vec4 color) {
+  vec4 c = vec4(shader_rg, 0, 0) + vec4(shader_rgb, 0) + shader_rgba;
+  return shader_matrix * c * shader_scale;
+}
+`
+	s, err := NewShader(src)
+	if err != nil {
+		t.Skipf("user-defined shaders are not available: %v", err)
+	}
+	defer s.Dispose()
+
+	const w, h = 16, 16
+	srcImg, _ := NewImage(w, h, FilterDefault)
+	srcImg.Fill(color.White)
+
+	dst, _ := NewImage(w, h, FilterDefault)
+	op := &DrawImageOptions{}
+	op.Shader = s
+	op.Uniforms = Uniforms{
+		"shader_scale": 0.5,
+		"shader_rg":    []float32{0.5, 0},
+		"shader_rgb":   []float32{0, 0.5, 0},
+		"shader_rgba":  []float32{0, 0, 1, 2},
+		// Swap the red and the blue.
+		"shader_matrix": []float32{
+			0, 0, 1, 0,
+			0, 1, 0, 0,
+			1, 0, 0, 0,
+			0, 0, 0, 1,
+		},
+	}
+	dst.DrawImage(srcImg, op)
+
+	got := dst.At(0, 0).(color.RGBA)
+	want := color.RGBA{0x80, 0x40, 0x40, 0xff}
+	if !sameColors(got, want, 2) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
+
+func TestImageShaderUnusedUniform(t *testing.T) {
+	// shader_unused is declared but not used, and the driver can remove it.
+	const src = `
+uniform float shader_unused;
+uniform float shader_alpha;
+
+vec4 shade(vec4 color) {
+  return vec4(color.rgb, color.a * shader_alpha);
+}
+`
+	s, err := NewShader(src)
+	if err != nil {
+		t.Skipf("user-defined shaders are not available: %v", err)
+	}
+	defer s.Dispose()
+
+	const w, h = 16, 16
+	srcImg, _ := NewImage(w, h, FilterDefault)
+	srcImg.Fill(color.White)
+
+	for _, u := range []Uniforms{
+		nil,
+		{"shader_alpha": 1},
+		{"shader_alpha": 1, "shader_unused": 1},
+	} {
+		dst, _ := NewImage(w, h, FilterDefault)
+		op := &DrawImageOptions{}
+		op.Shader = s
+		op.Uniforms = u
+		dst.DrawImage(srcImg, op)
+
+		want := color.RGBA{0xff, 0xff, 0xff, 0xff}
+		if u == nil {
+			want = color.RGBA{}
+		}
+		if got := dst.At(0, 0).(color.RGBA); !sameColors(got, want, 1) {
+			t.Errorf("uniforms: %v: got: %v, want: %v", u, got, want)
+		}
+	}
+}
+
+func TestShaderReload(t *testing.T) {
+	const red = `
+vec4 shade(vec4 color) {
+  return vec4(1, 0, 0, color.a);
+}
+`
+	const green = `
+vec4 shade(vec4 color) {
+  return vec4(0, 1, 0, color.a);
+}
+`
+	s, err := NewShader(red)
+	if err != nil {
+		t.Skipf("user-defined shaders are not available: %v", err)
+	}
+	defer s.Dispose()
+
+	const w, h = 16, 16
+	srcImg, _ := NewImage(w, h, FilterDefault)
+	srcImg.Fill(color.White)
+
+	draw := func() color.RGBA {
+		dst, _ := NewImage(w, h, FilterDefault)
+		op := &DrawImageOptions{}
+		op.Shader = s
+		dst.DrawImage(srcImg, op)
+		return dst.At(0, 0).(color.RGBA)
+	}
+
+	if got, want := draw(), (color.RGBA{0xff, 0, 0, 0xff}); got != want {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+
+	// Reload a valid source.
+	if err := s.Reload(green); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := draw(), (color.RGBA{0, 0xff, 0, 0xff}); got != want {
+		t.Errorf("after reloading: got: %v, want: %v", got, want)
+	}
+
+	// Reloading an invalid source fails and the previous source survives.
+	if err := s.Reload(`vec4 foo(vec4 color) { return color; }`); err == nil {
+		t.Errorf("Reload with an invalid source must return an error")
+	}
+	if got, want := draw(), (color.RGBA{0, 0xff, 0, 0xff}); got != want {
+		t.Errorf("after failing to reload: got: %v, want: %v", got, want)
+	}
+
+	// Reloading a source that passes the validation but fails to compile also fails,
+	// and the previous program survives.
+	const broken = `
+vec4 shade(vec4 color) {
+  return vec4(0, 0, 1, color.a) * shader_undefined;
+}
+`
+	if err := s.Reload(broken); err == nil {
+		t.Errorf("Reload with a source that fails to compile must return an error")
+	}
+	if got, want := draw(), (color.RGBA{0, 0xff, 0, 0xff}); got != want {
+		t.Errorf("after failing to compile: got: %v, want: %v", got, want)
+	}
+}
+
+func TestNewShaderInvalidSource(t *testing.T) {
+	cases := []string{
+		// No shade function
+		`vec4 foo(vec4 color) { return color; }`,
+		// Reserved keyword
+		`vec4 shade(vec4 filter) { return filter; }`,
+		// Unsupported uniform type
+		`uniform ivec2 offset; vec4 shade(vec4 color) { return color + vec4(offset, 0, 0); }`,
+	}
+	for _, src := range cases {
+		if _, err := NewShader(src); err == nil {
+			t.Errorf("NewShader(%q) must return an error", src)
+		}
+	}
+}
+
+func TestNewShaderCompileError(t *testing.T) {
+	s, err := NewShader(`vec4 shade(vec4 color) { return color; }`)
+	if err != nil {
+		t.Skipf("user-defined shaders are not available: %v", err)
+	}
+	s.Dispose()
+
+	// This source passes the validation but fails to compile, since shader_undefined is not declared.
+	const src = `
+vec4 shade(vec4 color) {
+  return color * shader_undefined;
+}
+`
+	if _, err := NewShader(src); err == nil {
+		t.Errorf("NewShader with a source that fails to compile must return an error")
+	}
+
+	// The game must keep running after the failure.
+	dst, _ := NewImage(16, 16, FilterDefault)
+	dst.Fill(color.White)
+	if got, want := dst.At(0, 0), (color.RGBA{0xff, 0xff, 0xff, 0xff}); got != want {
+		t.Errorf("dst.At(0, 0): got: %v, want: %v", got, want)
+	}
+}
+
+func TestImageShaderInvalidUniforms(t *testing.T) {
+	const src = `
+uniform vec2 shader_offset;
+
+vec4 shade(vec4 color) {
+  return color + vec4(shader_offset, 0, 0);
+}
+`
+	s, err := NewShader(src)
+	if err != nil {
+		t.Skipf("user-defined shaders are not available: %v", err)
+	}
+	defer s.Dispose()
+
+	const w, h = 16, 16
+	srcImg, _ := NewImage(w, h, FilterDefault)
+	dst, _ := NewImage(w, h, FilterDefault)
+
+	vs := []Vertex{
+		{DstX: 0, DstY: 0, SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: w, DstY: 0, SrcX: w, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 0, DstY: h, SrcX: 0, SrcY: h, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+	}
+	is := []uint16{0, 1, 2}
+
+	for _, u := range []Uniforms{
+		// Undeclared name
+		{"shader_offset2": []float32{0, 0}},
+		// Wrong type
+		{"shader_offset": float32(0)},
+		{"shader_offset": []float32{0, 0, 0}},
+	} {
+		mustPanic := func(name string, f func()) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					t.Errorf("%s with %v must panic", name, u)
+					return
+				}
+				msg := fmt.Sprint(r)
+				for n := range u {
+					if !strings.Contains(msg, n) {
+						t.Errorf("the panic message %q must include the uniform name %q", msg, n)
+					}
+				}
+			}()
+			f()
+		}
+		mustPanic("DrawImage", func() {
+			op := &DrawImageOptions{}
+			op.Shader = s
+			op.Uniforms = u
+			dst.DrawImage(srcImg, op)
+		})
+		mustPanic("DrawTriangles", func() {
+			op := &DrawTrianglesOptions{}
+			op.Shader = s
+			op.Uniforms = u
+			dst.DrawTriangles(vs, is, srcImg, op)
+		})
+	}
+}
+
+func TestImageNonPowerOfTwoEdges(t *testing.T) {
+	// The size is same as the Ebiten logo. The internal texture is larger than this size.
+	const w, h = 57, 26
+	src, _ := NewImage(w, h, FilterDefault)
+	pix := make([]byte, 4*w*h)
+	for i := 0; i < w*h; i++ {
+		pix[4*i] = 0xff
+		pix[4*i+3] = 0xff
+	}
+	src.ReplacePixels(pix)
+
+	for _, f := range []Filter{FilterNearest, FilterLinear} {
+		for _, scale := range []int{1, 3} {
+			dst, _ := NewImage(w*scale+8, h*scale+8, FilterDefault)
+			op := &DrawImageOptions{}
+			op.GeoM.Scale(float64(scale), float64(scale))
+			op.Filter = f
+			dst.DrawImage(src, op)
+
+			for j := 0; j < h*scale+8; j++ {
+				for i := 0; i < w*scale+8; i++ {
+					// With the linear filter, the pixels around the edges are blended with
+					// the transparent color outside of the image.
+					if f == FilterLinear && scale > 1 && (i == 0 || j == 0 || i == w*scale-1 || j == h*scale-1 || i == w*scale || j == h*scale) {
+						continue
+					}
+					got := dst.At(i, j).(color.RGBA)
+					want := color.RGBA{}
+					if i < w*scale && j < h*scale {
+						want = color.RGBA{0xff, 0, 0, 0xff}
+					}
+					if got != want {
+						t.Errorf("filter: %d, scale: %d, dst.At(%d, %d): got: %v, want: %v", f, scale, i, j, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestImageNearestAndLinearFiltersInOneFrame(t *testing.T) {
+	src, _ := NewImage(2, 1, FilterDefault)
+	src.ReplacePixels([]byte{
+		0xff, 0, 0, 0xff,
+		0, 0, 0xff, 0xff,
+	})
+
+	const scale = 8
+	dst, _ := NewImage(2*scale, 2, FilterDefault)
+
+	// Draw the same image with the different filters without reading the pixels in between,
+	// so that both draw calls are in the same command queue.
+	op := &DrawImageOptions{}
+	op.GeoM.Scale(scale, 1)
+	op.Filter = FilterNearest
+	dst.DrawImage(src, op)
+
+	op = &DrawImageOptions{}
+	op.GeoM.Scale(scale, 1)
+	op.GeoM.Translate(0, 1)
+	op.Filter = FilterLinear
+	dst.DrawImage(src, op)
+
+	for i := 0; i < 2*scale; i++ {
+		// Nearest: the left half is red and the right half is blue.
+		got := dst.At(i, 0).(color.RGBA)
+		want := color.RGBA{0xff, 0, 0, 0xff}
+		if i >= scale {
+			want = color.RGBA{0, 0, 0xff, 0xff}
+		}
+		if got != want {
+			t.Errorf("nearest: dst.At(%d, 0): got: %v, want: %v", i, got, want)
+		}
+	}
+
+	// Linear: the pixels around the center are blended.
+	got := dst.At(scale, 1).(color.RGBA)
+	if got.R == 0 || got.B == 0 {
+		t.Errorf("linear: dst.At(%d, 1): got: %v, want: a blended color of red and blue", scale, got)
+	}
+	got = dst.At(scale-1, 1).(color.RGBA)
+	if got.R == 0 || got.B == 0 {
+		t.Errorf("linear: dst.At(%d, 1): got: %v, want: a blended color of red and blue", scale-1, got)
+	}
+}
+
+func TestSetGraphicsDriverAfterRun(t *testing.T) {
+	// The game is already running in TestMain.
+	if err := SetGraphicsDriver("software"); err == nil {
+		t.Errorf("SetGraphicsDriver after Run must return an error")
+	}
+}
+
+func TestImageAlphaAt(t *testing.T) {
+	// A 16x16 image with an opaque 8x8 'logo' at the center, and a translucent pixel.
+	src := image.NewNRGBA(image.Rect(0, 0, 16, 16))
+	for j := 4; j < 12; j++ {
+		for i := 4; i < 12; i++ {
+			src.Set(i, j, color.NRGBA{0x12, 0x34, 0x56, 0xff})
+		}
+	}
+	src.Set(1, 1, color.NRGBA{0xff, 0xff, 0xff, 0x80})
+
+	img, err := NewImageFromImageWithOptions(src, &NewImageFromImageOptions{
+		KeepAlphaMask: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		X, Y int
+		Want uint8
+	}{
+		{8, 8, 0xff},
+		{4, 4, 0xff},
+		{11, 11, 0xff},
+		{3, 4, 0},
+		{12, 11, 0},
+		{0, 0, 0},
+		{1, 1, 0x80},
+		// Out of the bounds.
+		{-1, 8, 0},
+		{16, 8, 0},
+	}
+	for _, c := range cases {
+		got, err := img.AlphaAt(c.X, c.Y)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.Want {
+			t.Errorf("AlphaAt(%d, %d): got: %d, want: %d", c.X, c.Y, got, c.Want)
+		}
+	}
+
+	// A sub-image shares the mask with the same coordinates.
+	sub := img.SubImage(image.Rect(8, 8, 16, 16)).(*Image)
+	if got, err := sub.AlphaAt(8, 8); err != nil || got != 0xff {
+		t.Errorf("sub.AlphaAt(8, 8): got: %d, %v, want: %d, nil", got, err, 0xff)
+	}
+	if got, err := sub.AlphaAt(4, 4); err != nil || got != 0 {
+		t.Errorf("sub.AlphaAt(4, 4) out of the sub-image: got: %d, %v, want: 0, nil", got, err)
+	}
+
+	// The mask follows the region.
+	img2, err := NewImageFromImageWithOptions(src, &NewImageFromImageOptions{
+		Region:        image.Rect(4, 4, 12, 12),
+		KeepAlphaMask: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := img2.AlphaAt(0, 0); err != nil || got != 0xff {
+		t.Errorf("img2.AlphaAt(0, 0): got: %d, %v, want: %d, nil", got, err, 0xff)
+	}
+
+	img3, _ := NewImageFromImage(src, FilterDefault)
+	if _, err := img3.AlphaAt(8, 8); err == nil {
+		t.Errorf("AlphaAt without KeepAlphaMask must return an error")
+	}
+
+	img.Dispose()
+	if _, err := img.AlphaAt(8, 8); err == nil {
+		t.Errorf("AlphaAt after Dispose must return an error")
+	}
+}
+
+func TestImageManyDrawsToSubImage(t *testing.T) {
+	const w, h = 32, 32
+	glyph, _ := NewImage(4, 4, FilterDefault)
+	glyph.Fill(color.RGBA{0xff, 0, 0, 0xff})
+	dst, _ := NewImage(w, h, FilterDefault)
+	dst.Fill(color.RGBA{0, 0, 0xff, 0xff})
+
+	// Draw many glyphs into a slot like a text atlas. Some glyphs overflow the slot.
+	r := image.Rect(8, 8, 24, 20)
+	slot := dst.SubImage(r).(*Image)
+	for j := 0; j < 8; j++ {
+		for i := 0; i < 8; i++ {
+			op := &DrawImageOptions{}
+			op.GeoM.Translate(float64(i*4), float64(j*4))
+			slot.DrawImage(glyph, op)
+		}
+	}
+	// Triangles partially out of the slot with the linear filter.
+	vs := []Vertex{
+		{DstX: 0, DstY: 0, SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: w, DstY: 0, SrcX: 4, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 0, DstY: h, SrcX: 0, SrcY: 4, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+	}
+	slot.DrawTriangles(vs, []uint16{0, 1, 2}, glyph, &DrawTrianglesOptions{Filter: FilterLinear})
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{0, 0, 0xff, 0xff}
+			if image.Pt(i, j).In(r) {
+				want = color.RGBA{0xff, 0, 0, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageSubImageFillAndCopyInSameImage(t *testing.T) {
+	const w, h = 16, 16
+	img, _ := NewImage(w, h, FilterDefault)
+	img.Fill(color.RGBA{0, 0, 0xff, 0xff})
+
+	r0 := image.Rect(0, 0, 4, 4)
+	r1 := image.Rect(8, 8, 12, 12)
+	img.SubImage(r0).(*Image).Fill(color.RGBA{0x80, 0x40, 0x20, 0x80})
+
+	// Copy a sub-image to another sub-image of the same image.
+	op := &DrawImageOptions{}
+	op.GeoM.Translate(float64(r1.Min.X), float64(r1.Min.Y))
+	op.CompositeMode = CompositeModeCopy
+	img.SubImage(r1).(*Image).DrawImage(img.SubImage(r0).(*Image), op)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := img.At(i, j).(color.RGBA)
+			want := color.RGBA{0, 0, 0xff, 0xff}
+			if p := image.Pt(i, j); p.In(r0) || p.In(r1) {
+				want = color.RGBA{0x80, 0x40, 0x20, 0x80}
+			}
+			if !sameColors(got, want, 1) {
+				t.Errorf("img.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestCustomCompositeModeInvalidFactors(t *testing.T) {
+	n := len(CompositeModesForTesting())
+	for _, f := range [][2]BlendFactor{
+		{BlendFactorZero - 1, BlendFactorOne},
+		{BlendFactorOne, BlendFactorZero - 1},
+		{BlendFactorOneMinusDstAlpha + 1, BlendFactorOne},
+		{BlendFactorOne, BlendFactorOneMinusDstAlpha + 1},
+		{1 << 10, BlendFactorOne},
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("CustomCompositeMode(%d, %d) must panic", f[0], f[1])
+				}
+			}()
+			CustomCompositeMode(f[0], f[1])
+		}()
+	}
+	// The invalid modes must not be registered for prewarming.
+	if got, want := len(CompositeModesForTesting()), n; got != want {
+		t.Errorf("len(CompositeModesForTesting()): got: %d, want: %d", got, want)
+	}
+}
+
+func TestPrewarmShadersTargets(t *testing.T) {
+	m := CustomCompositeMode(BlendFactorDstColor, BlendFactorOneMinusSrcAlpha)
+	found := false
+	ms := CompositeModesForTesting()
+	for _, mode := range ms {
+		if mode == m {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("CompositeModesForTesting() must include the custom composite mode %v", m)
+	}
+	if got, want := len(ms), 13+1; got < want {
+		t.Errorf("len(CompositeModesForTesting()): got: %d, want: >= %d", got, want)
+	}
+
+	s, err := NewShader(`vec4 shade(vec4 color) {
+	return color;
+}`)
+	if err != nil {
+		t.Skip("user-defined shaders are not available on this graphics driver")
+	}
+	if !ContainsShaderForTesting(s) {
+		t.Errorf("the shaders to prewarm must include a new shader")
+	}
+	if err := s.Reload(`vec4 shade(vec4 color) {
+	return color.bgra;
+}`); err != nil {
+		t.Fatal(err)
+	}
+	if !ContainsShaderForTesting(s) {
+		t.Errorf("the shaders to prewarm must include a reloaded shader")
+	}
+
+	// Prewarming with the custom mode and the shader must not break the following rendering.
+	dst, _ := NewImage(16, 16, FilterDefault)
+	src, _ := NewImage(16, 16, FilterDefault)
+	src.Fill(color.RGBA{0x80, 0x40, 0x20, 0xff})
+	PrewarmShadersForTesting()
+	dst.DrawImage(src, nil)
+	if got, want := dst.At(0, 0), (color.RGBA{0x80, 0x40, 0x20, 0xff}); got != want {
+		t.Errorf("dst.At(0, 0): got: %v, want: %v", got, want)
+	}
+
+	s.Dispose()
+	if ContainsShaderForTesting(s) {
+		t.Errorf("the shaders to prewarm must not include a disposed shader")
+	}
+}
+
+func TestShaderFinalizer(t *testing.T) {
+	n := ShaderCountForTesting()
+	func() {
+		s, err := NewShader(`vec4 shade(vec4 color) {
+	return color;
+}`)
+		if err != nil {
+			t.Skip("user-defined shaders are not available on this graphics driver")
+		}
+		if got, want := ShaderCountForTesting(), n+1; got != want {
+			t.Errorf("ShaderCountForTesting(): got: %d, want: %d", got, want)
+		}
+		_ = s
+	}()
+
+	// An unreachable shader is disposed by its finalizer and is removed from the shaders to prewarm.
+	for i := 0; i < 100; i++ {
+		runtime.GC()
+		if ShaderCountForTesting() == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("ShaderCountForTesting(): got: %d, want: %d", ShaderCountForTesting(), n)
+}
+
+func TestImagePhysicalRectWithRenderScale(t *testing.T) {
+	cases := []struct {
+		Scale float64
+		Rect  image.Rectangle
+		Want  image.Rectangle
+	}{
+		{
+			Scale: 0.5,
+			Rect:  image.Rect(0, 0, 16, 16),
+			Want:  image.Rect(0, 0, 8, 8),
+		},
+		{
+			Scale: 0.5,
+			Rect:  image.Rect(3, 3, 7, 7),
+			Want:  image.Rect(1, 1, 4, 4),
+		},
+		{
+			Scale: 0.75,
+			Rect:  image.Rect(1, 2, 5, 7),
+			Want:  image.Rect(0, 1, 4, 6),
+		},
+		{
+			Scale: 0.3,
+			Rect:  image.Rect(1, 1, 5, 5),
+			Want:  image.Rect(0, 0, 2, 2),
+		},
+		{
+			Scale: 0.25,
+			Rect:  image.Rect(5, 6, 7, 8),
+			Want:  image.Rect(1, 1, 2, 2),
+		},
+	}
+	for _, c := range cases {
+		img := NewScaledVolatileImageForTesting(16, 16, c.Scale)
+		// The physical rectangle must cover the pixels that the logical rectangle covers.
+		if got := img.PhysicalRectForTesting(c.Rect); got != c.Want {
+			t.Errorf("scale: %v, physicalRect(%v): got: %v, want: %v", c.Scale, c.Rect, got, c.Want)
+		}
+		if got := img.SubImage(c.Rect).(*Image).PhysicalRectForTesting(c.Rect); got != c.Want {
+			t.Errorf("scale: %v, sub-image's physicalRect(%v): got: %v, want: %v", c.Scale, c.Rect, got, c.Want)
+		}
+		_ = img.Dispose()
+	}
+}
+
+func TestRenderScaleClamp(t *testing.T) {
+	defer SetRenderScale(1)
+
+	cases := []struct {
+		In   float64
+		Want float64
+	}{
+		{In: 0.5, Want: 0.5},
+		{In: 0.25, Want: 0.25},
+		{In: 0.1, Want: 0.25},
+		{In: 0, Want: 0.25},
+		{In: -1, Want: 0.25},
+		{In: 1, Want: 1},
+		{In: 2, Want: 1},
+		{In: math.Inf(1), Want: 1},
+	}
+	for _, c := range cases {
+		SetRenderScale(c.In)
+		if got := RenderScale(); got != c.Want {
+			t.Errorf("SetRenderScale(%v): RenderScale(): got: %v, want: %v", c.In, got, c.Want)
+		}
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("SetRenderScale(NaN) must panic")
+		}
+	}()
+	SetRenderScale(math.NaN())
+}
+
+func TestImageBoundsAndAtWithRenderScale(t *testing.T) {
+	const w, h = 16, 16
+	for _, scale := range []float64{0.3, 0.5} {
+		img := NewScaledVolatileImageForTesting(w, h, scale)
+		if got, want := img.Bounds(), image.Rect(0, 0, w, h); got != want {
+			t.Errorf("scale: %v, Bounds(): got: %v, want: %v", scale, got, want)
+		}
+		if gotW, gotH := img.Size(); gotW != w || gotH != h {
+			t.Errorf("scale: %v, Size(): got: (%d, %d), want: (%d, %d)", scale, gotW, gotH, w, h)
+		}
+
+		img.Fill(color.RGBA{0, 0, 0xff, 0xff})
+		for j := 0; j < h; j++ {
+			for i := 0; i < w; i++ {
+				if got, want := img.At(i, j), (color.RGBA{0, 0, 0xff, 0xff}); got != want {
+					t.Errorf("scale: %v, At(%d, %d): got: %v, want: %v", scale, i, j, got, want)
+				}
+			}
+		}
+		// The points out of the logical bounds must be transparent even if they are mapped into the underlying image.
+		for _, p := range []image.Point{{-1, 0}, {0, -1}, {w, 0}, {0, h}} {
+			if got, want := img.At(p.X, p.Y), (color.RGBA{}); got != want {
+				t.Errorf("scale: %v, At(%d, %d): got: %v, want: %v", scale, p.X, p.Y, got, want)
+			}
+		}
+
+		r := image.Rect(4, 4, 12, 12)
+		sub := img.SubImage(r).(*Image)
+		if got := sub.Bounds(); got != r {
+			t.Errorf("scale: %v, sub-image's Bounds(): got: %v, want: %v", scale, got, r)
+		}
+		_ = img.Dispose()
+	}
+
+	// Fill a sub-image and check the result in the logical coordinates.
+	img := NewScaledVolatileImageForTesting(w, h, 0.5)
+	img.Fill(color.RGBA{0, 0, 0xff, 0xff})
+	r := image.Rect(4, 4, 12, 12)
+	img.SubImage(r).(*Image).Fill(color.RGBA{0xff, 0, 0, 0xff})
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := img.At(i, j)
+			want := color.RGBA{0, 0, 0xff, 0xff}
+			if image.Pt(i, j).In(r) {
+				want = color.RGBA{0xff, 0, 0, 0xff}
+			}
+			if got != want {
+				t.Errorf("At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+	_ = img.Dispose()
+}
+
+func TestImageMipmapBiasLevel(t *testing.T) {
+	cases := []struct {
+		Bias float64
+		Want int
+	}{
+		{0, 0},
+		{0.4, 0},
+		{0.5, 1},
+		{1, 1},
+		{-1, -1},
+		{-1.6, -2},
+		{100, 6},
+		{-100, -6},
+		{math.Inf(1), 6},
+		{math.Inf(-1), -6},
+	}
+	for _, c := range cases {
+		if got := MipmapBiasLevelForTesting(c.Bias); got != c.Want {
+			t.Errorf("mipmapBiasLevel(%v): got: %d, want: %d", c.Bias, got, c.Want)
+		}
+	}
+}
+
+func TestImageMipmapBiasExtreme(t *testing.T) {
+	src, _ := NewImage(64, 64, FilterDefault)
+	src.Fill(color.RGBA{0xff, 0, 0, 0xff})
+	dst, _ := NewImage(64, 64, FilterDefault)
+
+	// An extreme bias must not panic.
+	for _, bias := range []float64{math.Inf(1), math.Inf(-1), 1e9, -1e9, math.MaxFloat64} {
+		op := &DrawImageOptions{}
+		op.GeoM.Scale(0.25, 0.25)
+		op.Filter = FilterLinear
+		op.MipmapBias = bias
+		dst.DrawImage(src, op)
+	}
+	if got, want := dst.At(0, 0), (color.RGBA{0xff, 0, 0, 0xff}); got != want {
+		t.Errorf("dst.At(0, 0): got: %v, want: %v", got, want)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("DrawImage with a NaN MipmapBias must panic")
+		}
+	}()
+	op := &DrawImageOptions{}
+	op.MipmapBias = math.NaN()
+	dst.DrawImage(src, op)
+}
@@ -15,6 +15,9 @@
 package ebiten
 
 import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/internal/gamepaddb"
 	"github.com/hajimehoshi/ebiten/internal/input"
 	"github.com/hajimehoshi/ebiten/internal/ui"
 )
@@ -47,6 +50,10 @@ func IsKeyPressed(key Key) bool {
 
 // CursorPosition returns a position of a mouse cursor.
 //
+// The position is in the game screen's coordinates, i.e., the screen scale and the device scale factor
+// are already applied: the returned position is on the pixel of the screen image under the cursor.
+// When the cursor is outside of the screen, the returned position can be negative or beyond the screen size.
+//
 // CursorPosition is concurrent-safe.
 func CursorPosition() (x, y int) {
 	return ui.AdjustedCursorPosition()
@@ -55,6 +62,9 @@ func CursorPosition() (x, y int) {
 // Wheel returns the x and y offset of the mouse wheel or touchpad scroll.
 // It returns 0 if the wheel isn't being rolled.
 //
+// The offsets are accumulated since the previous call of the updating function, and are reset
+// after each call. The offsets are not rounded to integers: a touchpad can report fractional values.
+//
 // Wheel is concurrent-safe.
 func Wheel() (xoff, yoff float64) {
 	return input.Get().Wheel()
@@ -90,6 +100,9 @@ func GamepadAxisNum(id int) int {
 
 // GamepadAxis returns the float value [-1.0 - 1.0] of the given gamepad (id)'s axis (axis).
 //
+// The dead zone set by SetGamepadAxisDeadZone is applied to the value.
+// GamepadAxis returns 0 if the gamepad or the axis is not found.
+//
 // GamepadAxis is concurrent-safe.
 //
 // GamepadAxis always returns 0 on mobiles.
@@ -97,6 +110,30 @@ func GamepadAxis(id int, axis int) float64 {
 	return input.Get().GamepadAxis(id, axis)
 }
 
+// GamepadAxisDeadZone returns the current dead zone of gamepad axes.
+//
+// GamepadAxisDeadZone is concurrent-safe.
+func GamepadAxisDeadZone() float64 {
+	return input.GamepadAxisDeadZone()
+}
+
+// SetGamepadAxisDeadZone sets the dead zone of gamepad axes in the range of [0, 1).
+//
+// Analog sticks often don't report exactly 0 even when they are not touched.
+// GamepadAxis treats a value whose absolute value is less than or equal to the dead zone as 0,
+// and rescales the rest so that the value still reaches 1 (or -1) continuously.
+//
+// The initial value is 0, which means no dead zone.
+// If deadZone is out of the range, SetGamepadAxisDeadZone panics.
+//
+// SetGamepadAxisDeadZone is concurrent-safe.
+func SetGamepadAxisDeadZone(deadZone float64) {
+	if deadZone < 0 || deadZone >= 1 {
+		panic("ebiten: deadZone must be in [0, 1)")
+	}
+	input.SetGamepadAxisDeadZone(deadZone)
+}
+
 // GamepadButtonNum returns the number of the buttons of the given gamepad (id).
 //
 // GamepadButtonNum is concurrent-safe.
@@ -118,6 +155,88 @@ func IsGamepadButtonPressed(id int, button GamepadButton) bool {
 	return input.Get().IsGamepadButtonPressed(id, input.GamepadButton(button))
 }
 
+// VibrateGamepad vibrates the given gamepad (id) for the given duration.
+//
+// strongMagnitude and weakMagnitude are the magnitudes of the strong (low-frequency) and the weak (high-frequency)
+// motors in the range of [0, 1].
+//
+// VibrateGamepad works only on browsers that support the vibration of gamepads (e.g., Chrome).
+// On the other environments, or when the gamepad doesn't support vibration, VibrateGamepad does nothing.
+// VibrateGamepad never panics even when the gamepad is not found.
+//
+// VibrateGamepad is concurrent-safe.
+func VibrateGamepad(id int, strongMagnitude, weakMagnitude float64, duration time.Duration) {
+	if strongMagnitude < 0 {
+		strongMagnitude = 0
+	}
+	if strongMagnitude > 1 {
+		strongMagnitude = 1
+	}
+	if weakMagnitude < 0 {
+		weakMagnitude = 0
+	}
+	if weakMagnitude > 1 {
+		weakMagnitude = 1
+	}
+	if duration <= 0 {
+		return
+	}
+	input.Get().VibrateGamepad(id, strongMagnitude, weakMagnitude, duration)
+}
+
+// GamepadName returns the name of the given gamepad (id).
+//
+// GamepadName returns an empty string when the gamepad is not found.
+//
+// GamepadName is concurrent-safe.
+//
+// GamepadName always returns an empty string on mobiles.
+func GamepadName(id int) string {
+	return input.Get().GamepadName(id)
+}
+
+// IsStandardGamepadLayoutAvailable reports whether the gamepad (id) can be used with the standard layout,
+// i.e., IsStandardGamepadButtonPressed works for the gamepad.
+//
+// On browsers, the standard layout is available when the browser recognizes the gamepad as a standard one.
+// On the other environments, the standard layout is available when a mapping for the gamepad's name is
+// registered by UpdateStandardGamepadLayoutMappings.
+//
+// IsStandardGamepadLayoutAvailable is concurrent-safe.
+func IsStandardGamepadLayoutAvailable(id int) bool {
+	return input.Get().IsStandardGamepadLayoutAvailable(id)
+}
+
+// IsStandardGamepadButtonPressed reports whether the given standard button of the gamepad (id) is pressed.
+//
+// Unlike IsGamepadButtonPressed, the relationships between physical buttons and standard buttons are
+// consistent across gamepads.
+// If the standard layout is not available for the gamepad, IsStandardGamepadButtonPressed always returns false.
+// In this case, use IsGamepadButtonPressed with the raw button indices as a fallback.
+//
+// IsStandardGamepadButtonPressed is concurrent-safe.
+func IsStandardGamepadButtonPressed(id int, button StandardGamepadButton) bool {
+	return input.Get().IsStandardGamepadButtonPressed(id, gamepaddb.StandardButton(button))
+}
+
+// UpdateStandardGamepadLayoutMappings adds the given gamepad mappings to the mapping database.
+//
+// The format is same as SDL_GameControllerDB (https://github.com/gabomdq/SDL_GameControllerDB).
+// Each line represents one gamepad:
+//
+//     GUID,name,a:b0,b:b1,x:b2,y:b3,dpup:h0.1,lefttrigger:a2,platform:Windows,
+//
+// As GUIDs are not available in the current GLFW, gamepads are identified by their names (GamepadName).
+// Lines for other platforms are ignored. Hats are not supported so far, and buttons mapped to hats are
+// never pressed.
+//
+// UpdateStandardGamepadLayoutMappings returns an error when the given mappings are invalid.
+//
+// UpdateStandardGamepadLayoutMappings is concurrent-safe.
+func UpdateStandardGamepadLayoutMappings(mappings string) error {
+	return gamepaddb.Update([]byte(mappings))
+}
+
 // TouchIDs returns the current touch states.
 //
 // TouchIDs returns nil when there are no touches.
@@ -39,9 +39,17 @@ type inputState struct {
 	touchDurations     map[int]int
 	prevTouchDurations map[int]int
 
+	touchPositions     map[int]touchPosition
+	prevTouchPositions map[int]touchPosition
+
 	m sync.RWMutex
 }
 
+type touchPosition struct {
+	x int
+	y int
+}
+
 var theInputState = &inputState{
 	keyDurations:     map[ebiten.Key]int{},
 	prevKeyDurations: map[ebiten.Key]int{},
@@ -57,6 +65,9 @@ var theInputState = &inputState{
 
 	touchDurations:     map[int]int{},
 	prevTouchDurations: map[int]int{},
+
+	touchPositions:     map[int]touchPosition{},
+	prevTouchPositions: map[int]touchPosition{},
 }
 
 func init() {
@@ -71,14 +82,7 @@ func (i *inputState) update() {
 	defer i.m.Unlock()
 
 	// Keyboard
-	for k := ebiten.Key(0); k <= ebiten.KeyMax; k++ {
-		i.prevKeyDurations[k] = i.keyDurations[k]
-		if ebiten.IsKeyPressed(k) {
-			i.keyDurations[k]++
-		} else {
-			i.keyDurations[k] = 0
-		}
-	}
+	i.updateKeys(ebiten.IsKeyPressed)
 
 	// Mouse
 	for _, b := range []ebiten.MouseButton{
@@ -137,19 +141,32 @@ func (i *inputState) update() {
 	}
 
 	// Touches
+	i.updateTouches(ebiten.TouchIDs(), ebiten.TouchPosition)
+}
+
+// updateTouches updates the touch durations and positions with the current touch IDs and position.
+//
+// updateTouches must be called with i.m locked.
+func (i *inputState) updateTouches(touchIDs []int, position func(id int) (int, int)) {
 	ids := map[int]struct{}{}
 
-	// Copy the touch durations.
+	// Copy the touch durations and positions.
 	i.prevTouchDurations = map[int]int{}
 	for id := range i.touchDurations {
 		i.prevTouchDurations[id] = i.touchDurations[id]
 	}
+	i.prevTouchPositions = map[int]touchPosition{}
+	for id := range i.touchPositions {
+		i.prevTouchPositions[id] = i.touchPositions[id]
+	}
 
-	for _, id := range ebiten.TouchIDs() {
+	for _, id := range touchIDs {
 		ids[id] = struct{}{}
 		i.touchDurations[id]++
+		x, y := position(id)
+		i.touchPositions[id] = touchPosition{x: x, y: y}
 	}
-	idsToDelete = []int{}
+	idsToDelete := []int{}
 	for id := range i.touchDurations {
 		if _, ok := ids[id]; !ok {
 			idsToDelete = append(idsToDelete, id)
@@ -157,6 +174,21 @@ func (i *inputState) update() {
 	}
 	for _, id := range idsToDelete {
 		delete(i.touchDurations, id)
+		delete(i.touchPositions, id)
+	}
+}
+
+// updateKeys updates the key durations with the current key states reported by isKeyPressed.
+//
+// updateKeys must be called with i.m locked.
+func (i *inputState) updateKeys(isKeyPressed func(ebiten.Key) bool) {
+	for k := ebiten.Key(0); k <= ebiten.KeyMax; k++ {
+		i.prevKeyDurations[k] = i.keyDurations[k]
+		if isKeyPressed(k) {
+			i.keyDurations[k]++
+		} else {
+			i.keyDurations[k] = 0
+		}
 	}
 }
 
@@ -317,6 +349,21 @@ func IsTouchJustReleased(id int) bool {
 	return r
 }
 
+// IsTouchJustMoved returns a boolean value indicating
+// whether the given touch moved just in the current frame.
+//
+// IsTouchJustMoved returns false for a touch that is created or released in the current frame.
+//
+// IsTouchJustMoved is concurrent safe.
+func IsTouchJustMoved(id int) bool {
+	theInputState.m.RLock()
+	defer theInputState.m.RUnlock()
+	if theInputState.touchDurations[id] < 2 {
+		return false
+	}
+	return theInputState.touchPositions[id] != theInputState.prevTouchPositions[id]
+}
+
 // TouchPressDuration returns how long the touch remains in frames.
 //
 // TouchPressDuration is concurrent safe.
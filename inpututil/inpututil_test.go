@@ -0,0 +1,173 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inpututil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+func TestKeyDurations(t *testing.T) {
+	orig := theInputState
+	defer func() {
+		theInputState = orig
+	}()
+	theInputState = &inputState{
+		keyDurations:     map[ebiten.Key]int{},
+		prevKeyDurations: map[ebiten.Key]int{},
+	}
+
+	// Each frame lists the pressed keys.
+	frames := []struct {
+		Pressed      []ebiten.Key
+		JustPressed  []ebiten.Key
+		JustReleased []ebiten.Key
+	}{
+		{nil, nil, nil},
+		{[]ebiten.Key{ebiten.KeyA}, []ebiten.Key{ebiten.KeyA}, nil},
+		{[]ebiten.Key{ebiten.KeyA}, nil, nil},
+		{[]ebiten.Key{ebiten.KeyA, ebiten.KeyB}, []ebiten.Key{ebiten.KeyB}, nil},
+		{[]ebiten.Key{ebiten.KeyB}, nil, []ebiten.Key{ebiten.KeyA}},
+		{nil, nil, []ebiten.Key{ebiten.KeyB}},
+		{nil, nil, nil},
+		// Pressed again after a release.
+		{[]ebiten.Key{ebiten.KeyA}, []ebiten.Key{ebiten.KeyA}, nil},
+	}
+
+	contains := func(keys []ebiten.Key, key ebiten.Key) bool {
+		for _, k := range keys {
+			if k == key {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i, f := range frames {
+		theInputState.m.Lock()
+		theInputState.updateKeys(func(key ebiten.Key) bool {
+			return contains(f.Pressed, key)
+		})
+		theInputState.m.Unlock()
+
+		for _, k := range []ebiten.Key{ebiten.KeyA, ebiten.KeyB, ebiten.KeyC} {
+			if got, want := IsKeyJustPressed(k), contains(f.JustPressed, k); got != want {
+				t.Errorf("frame %d: IsKeyJustPressed(%v): got: %v, want: %v", i, k, got, want)
+			}
+			if got, want := IsKeyJustReleased(k), contains(f.JustReleased, k); got != want {
+				t.Errorf("frame %d: IsKeyJustReleased(%v): got: %v, want: %v", i, k, got, want)
+			}
+		}
+	}
+
+	// KeyPressDuration counts the frames since the key is pressed.
+	theInputState.m.Lock()
+	theInputState.updateKeys(func(key ebiten.Key) bool {
+		return key == ebiten.KeyA
+	})
+	theInputState.m.Unlock()
+	if got, want := KeyPressDuration(ebiten.KeyA), 2; got != want {
+		t.Errorf("KeyPressDuration(KeyA): got: %d, want: %d", got, want)
+	}
+}
+
+func TestTouches(t *testing.T) {
+	orig := theInputState
+	defer func() {
+		theInputState = orig
+	}()
+	theInputState = &inputState{
+		touchDurations:     map[int]int{},
+		prevTouchDurations: map[int]int{},
+		touchPositions:     map[int]touchPosition{},
+		prevTouchPositions: map[int]touchPosition{},
+	}
+
+	// A two-finger sequence: the touch 1 starts and drags, the touch 2 joins and stays,
+	// and then they are released one by one.
+	frames := []struct {
+		Touches      map[int]touchPosition
+		JustPressed  []int
+		JustMoved    []int
+		JustReleased []int
+		Durations    map[int]int
+	}{
+		{
+			Touches:     map[int]touchPosition{1: {10, 10}},
+			JustPressed: []int{1},
+			Durations:   map[int]int{1: 1},
+		},
+		{
+			Touches:     map[int]touchPosition{1: {12, 10}, 2: {50, 50}},
+			JustPressed: []int{2},
+			JustMoved:   []int{1},
+			Durations:   map[int]int{1: 2, 2: 1},
+		},
+		{
+			Touches:   map[int]touchPosition{1: {12, 10}, 2: {50, 50}},
+			Durations: map[int]int{1: 3, 2: 2},
+		},
+		{
+			Touches:      map[int]touchPosition{2: {50, 52}},
+			JustMoved:    []int{2},
+			JustReleased: []int{1},
+			Durations:    map[int]int{1: 0, 2: 3},
+		},
+		{
+			Touches:      map[int]touchPosition{},
+			JustReleased: []int{2},
+			Durations:    map[int]int{1: 0, 2: 0},
+		},
+	}
+
+	contains := func(ids []int, id int) bool {
+		for _, i := range ids {
+			if i == id {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i, f := range frames {
+		var ids []int
+		for id := range f.Touches {
+			ids = append(ids, id)
+		}
+		theInputState.m.Lock()
+		theInputState.updateTouches(ids, func(id int) (int, int) {
+			p := f.Touches[id]
+			return p.x, p.y
+		})
+		theInputState.m.Unlock()
+
+		if got := JustPressedTouchIDs(); !reflect.DeepEqual(got, f.JustPressed) {
+			t.Errorf("frame %d: JustPressedTouchIDs(): got: %v, want: %v", i, got, f.JustPressed)
+		}
+		for _, id := range []int{1, 2} {
+			if got, want := IsTouchJustMoved(id), contains(f.JustMoved, id); got != want {
+				t.Errorf("frame %d: IsTouchJustMoved(%d): got: %v, want: %v", i, id, got, want)
+			}
+			if got, want := IsTouchJustReleased(id), contains(f.JustReleased, id); got != want {
+				t.Errorf("frame %d: IsTouchJustReleased(%d): got: %v, want: %v", i, id, got, want)
+			}
+			if got, want := TouchPressDuration(id), f.Durations[id]; got != want {
+				t.Errorf("frame %d: TouchPressDuration(%d): got: %d, want: %d", i, id, got, want)
+			}
+		}
+	}
+}
@@ -47,8 +47,16 @@ type ColorM struct {
 	// elements are immutable and a new array must be created when updating.
 	body      []float32
 	translate []float32
+
+	// linear represents whether the matrix is applied in the linear color space.
+	// When linear is true, a color is converted to the linear space before applying the matrix,
+	// and converted back to the sRGB space after that.
+	linear bool
 }
 
+// gamma is the gamma value used for the conversion between the linear and sRGB spaces.
+const gamma = 2.2
+
 func clamp(x float32) float32 {
 	if x > 1 {
 		return 1
@@ -63,10 +71,44 @@ func (c *ColorM) isInited() bool {
 	return c != nil && (c.body != nil || c.translate != nil)
 }
 
+// IsIdentity reports whether c is an identity matrix.
+func (c *ColorM) IsIdentity() bool {
+	if !c.isInited() {
+		return true
+	}
+	return c.Equals(nil)
+}
+
+// InLinearSpace returns a new matrix that is same as c but applied in the linear color space.
+//
+// Even if c is identity, the returned matrix is in the linear space, since the vertex color scales
+// are applied with the matrix in the same color space.
+func (c *ColorM) InLinearSpace() *ColorM {
+	if !c.isInited() {
+		return &ColorM{
+			linear: true,
+		}
+	}
+	return &ColorM{
+		body:      c.body,
+		translate: c.translate,
+		linear:    true,
+	}
+}
+
+// IsInLinearSpace reports whether c is applied in the linear color space.
+func (c *ColorM) IsInLinearSpace() bool {
+	return c != nil && c.linear
+}
+
 func (c *ColorM) ScaleOnly() bool {
 	if c == nil {
 		return true
 	}
+	if c.linear {
+		// The vertex color scale is applied in the sRGB space.
+		return false
+	}
 	if c.body != nil {
 		for i, e := range c.body {
 			if i == 0 || i == 5 || i == 10 || i == 15 {
@@ -100,6 +142,11 @@ func (c *ColorM) Apply(clr color.Color) color.Color {
 		bf = float32(b) / float32(a)
 		af = float32(a) / 0xffff
 	}
+	if c.linear {
+		rf = float32(math.Pow(float64(rf), gamma))
+		gf = float32(math.Pow(float64(gf), gamma))
+		bf = float32(math.Pow(float64(bf), gamma))
+	}
 	eb := c.body
 	if eb == nil {
 		eb = colorMIdentityBody
@@ -116,6 +163,11 @@ func (c *ColorM) Apply(clr color.Color) color.Color {
 	gf2 = clamp(gf2)
 	bf2 = clamp(bf2)
 	af2 = clamp(af2)
+	if c.linear {
+		rf2 = float32(math.Pow(float64(rf2), 1/gamma))
+		gf2 = float32(math.Pow(float64(gf2), 1/gamma))
+		bf2 = float32(math.Pow(float64(bf2), 1/gamma))
+	}
 	return color.NRGBA64{
 		R: uint16(rf2 * 0xffff),
 		G: uint16(gf2 * 0xffff),
@@ -167,6 +219,9 @@ func (c *ColorM) Equals(other *ColorM) bool {
 	if !c.isInited() && !other.isInited() {
 		return true
 	}
+	if c.IsInLinearSpace() != other.IsInLinearSpace() {
+		return false
+	}
 
 	lhsb := colorMIdentityBody
 	lhst := colorMIdentityTranslate
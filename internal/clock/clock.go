@@ -16,6 +16,7 @@
 package clock
 
 import (
+	"runtime"
 	"sync"
 	"time"
 )
@@ -24,11 +25,38 @@ var (
 	// lastSystemTime is the last system time in the previous Update.
 	lastSystemTime int64
 
-	currentFPS  float64
-	currentTPS  float64
-	lastUpdated int64
-	fpsCount    = 0
-	tpsCount    = 0
+	currentFPS float64
+	currentTPS float64
+
+	// frameRecords is a ring buffer of the recent frames to calculate FPS and TPS.
+	frameRecords     [maxFrameRecords]frameRecord
+	frameRecordStart = 0
+	frameRecordNum   = 0
+
+	// maxFPS is the maximum FPS. 0 means unlimited.
+	maxFPS int64
+
+	// lastLimitedFrameTime is the scheduled time of the previous frame limited by maxFPS.
+	lastLimitedFrameTime int64
+
+	// tickSum is the sum of the counts of frameRecords except for the oldest one.
+	tickSum = 0
+
+	// lastFrameTime is the system time of the previous Update.
+	lastFrameTime int64
+
+	// frameDropped represents whether the previous frame was dropped.
+	frameDropped bool
+
+	// maxDeltaTime is the maximum time that is fed to the game time in one Update.
+	// If maxDeltaTime is 0, the default policy is used.
+	maxDeltaTime int64
+
+	// lastTickTime is the system time of the last Update that returned a positive count.
+	lastTickTime int64
+
+	// deltaTime is the elapsed time per tick in the last Update that returned a positive count.
+	deltaTime int64
 
 	m sync.Mutex
 )
@@ -47,6 +75,62 @@ func CurrentTPS() float64 {
 	return v
 }
 
+// IsFrameDropped returns a boolean value indicating whether the previous frame was dropped.
+func IsFrameDropped() bool {
+	m.Lock()
+	v := frameDropped
+	m.Unlock()
+	return v
+}
+
+// targetFrameTime returns the expected time of one frame.
+//
+// The target is the time of one tick, but not shorter than 1/60 seconds,
+// that is a typical display refresh interval.
+func targetFrameTime(tps int) int64 {
+	t := int64(time.Second) / 60
+	if tps > 0 && int64(time.Second)/int64(tps) > t {
+		t = int64(time.Second) / int64(tps)
+	}
+	return t
+}
+
+// updateFrameDropped updates frameDropped.
+//
+// A frame is regarded as dropped when the time from the previous frame exceeds 1.5 times the target frame time.
+// This means that at least one vsync was missed.
+func updateFrameDropped(now int64, tps int) {
+	if lastFrameTime == 0 || now < lastFrameTime {
+		lastFrameTime = now
+		frameDropped = false
+		return
+	}
+	frameDropped = now-lastFrameTime > targetFrameTime(tps)*3/2
+	lastFrameTime = now
+}
+
+// MaxDeltaTime returns the maximum delta time set by SetMaxDeltaTime.
+func MaxDeltaTime() time.Duration {
+	m.Lock()
+	v := maxDeltaTime
+	m.Unlock()
+	return time.Duration(v)
+}
+
+// SetMaxDeltaTime sets the maximum time that is fed to the game time in one Update.
+// The time exceeding d is dropped instead of being simulated.
+//
+// If d is 0, the default policy is used: when the previous Update is too old,
+// the game time is synced with the system clock and the game is updated only once.
+func SetMaxDeltaTime(d time.Duration) {
+	if d < 0 {
+		panic("clock: d must be >= 0")
+	}
+	m.Lock()
+	maxDeltaTime = int64(d)
+	m.Unlock()
+}
+
 func calcCountFromTPS(tps int64, now int64) int {
 	if tps == 0 {
 		return 0
@@ -68,7 +152,12 @@ func calcCountFromTPS(tps int64, now int64) int {
 	count := 0
 	syncWithSystemClock := false
 
-	if diff > int64(time.Second)*5/60 {
+	if maxDeltaTime > 0 && diff > maxDeltaTime {
+		// The previous time is too old.
+		// Simulate only maxDeltaTime and drop the rest to avoid an unbounded number of updates.
+		count = int(maxDeltaTime * tps / int64(time.Second))
+		syncWithSystemClock = true
+	} else if maxDeltaTime == 0 && diff > int64(time.Second)*5/60 {
 		// The previous time is too old.
 		// Let's force to sync the game time with the system clock.
 		syncWithSystemClock = true
@@ -94,20 +183,175 @@ func calcCountFromTPS(tps int64, now int64) int {
 	return count
 }
 
+// defaultMaxElapsedTime is the maximum elapsed time that is counted for the delta time
+// when maxDeltaTime is 0.
+const defaultMaxElapsedTime = int64(time.Second) * 5 / 60
+
+func updateDeltaTime(now int64, count int, tps int) {
+	if count == 0 {
+		return
+	}
+
+	if lastTickTime == 0 {
+		// This is the first tick. Assume the ideal delta time.
+		if tps > 0 {
+			deltaTime = int64(time.Second) / int64(tps)
+		} else {
+			deltaTime = int64(time.Second) / 60
+		}
+		lastTickTime = now
+		return
+	}
+
+	elapsed := now - lastTickTime
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	// Clamp the elapsed time so that resuming the game after a long pause doesn't cause a spike.
+	max := maxDeltaTime
+	if max == 0 {
+		max = defaultMaxElapsedTime
+	}
+	if elapsed > max {
+		elapsed = max
+	}
+	deltaTime = elapsed / int64(count)
+	lastTickTime = now
+}
+
+// DeltaTime returns the elapsed time per tick in the last Update that returned a positive count.
+func DeltaTime() time.Duration {
+	m.Lock()
+	v := deltaTime
+	m.Unlock()
+	return time.Duration(v)
+}
+
+const (
+	// fpsWindow is the time window to calculate FPS and TPS.
+	fpsWindow = int64(time.Second)
+
+	// maxFrameRecords is the maximum number of frames in the time window.
+	// If the framerate is higher than this, the actual window is shorter than fpsWindow.
+	maxFrameRecords = 256
+)
+
+type frameRecord struct {
+	time  int64
+	count int
+}
+
+func frameRecordAt(i int) *frameRecord {
+	return &frameRecords[(frameRecordStart+i)%maxFrameRecords]
+}
+
+func dropOldestFrameRecord() {
+	frameRecordStart = (frameRecordStart + 1) % maxFrameRecords
+	frameRecordNum--
+	// The new oldest record is no longer counted.
+	tickSum -= frameRecordAt(0).count
+}
+
+// updateFPSAndTPS updates FPS and TPS with the rolling average over the recent frames.
 func updateFPSAndTPS(now int64, count int) {
-	if lastUpdated == 0 {
-		lastUpdated = now
+	if frameRecordNum == maxFrameRecords {
+		dropOldestFrameRecord()
+	}
+	*frameRecordAt(frameRecordNum) = frameRecord{
+		time:  now,
+		count: count,
+	}
+	if frameRecordNum > 0 {
+		tickSum += count
 	}
-	fpsCount++
-	tpsCount += count
-	if time.Second > time.Duration(now-lastUpdated) {
+	frameRecordNum++
+
+	// Keep the oldest record at or just before the start of the window.
+	for frameRecordNum > 2 && now-frameRecordAt(1).time >= fpsWindow {
+		dropOldestFrameRecord()
+	}
+
+	span := now - frameRecordAt(0).time
+	if span <= 0 {
 		return
 	}
-	currentFPS = float64(fpsCount) * float64(time.Second) / float64(now-lastUpdated)
-	currentTPS = float64(tpsCount) * float64(time.Second) / float64(now-lastUpdated)
-	lastUpdated = now
-	fpsCount = 0
-	tpsCount = 0
+	currentFPS = float64(frameRecordNum-1) * float64(time.Second) / float64(span)
+	currentTPS = float64(tickSum) * float64(time.Second) / float64(span)
+}
+
+// MaxFPS returns the maximum FPS set by SetMaxFPS.
+func MaxFPS() int {
+	m.Lock()
+	v := maxFPS
+	m.Unlock()
+	return int(v)
+}
+
+// SetMaxFPS sets the maximum FPS.
+// If fps is 0, FPS is not limited.
+func SetMaxFPS(fps int) {
+	if fps < 0 {
+		panic("clock: fps must be >= 0")
+	}
+	m.Lock()
+	maxFPS = int64(fps)
+	lastLimitedFrameTime = 0
+	m.Unlock()
+}
+
+func calcFrameWait(now int64) int64 {
+	if maxFPS == 0 {
+		return 0
+	}
+
+	interval := int64(time.Second) / maxFPS
+	if lastLimitedFrameTime == 0 {
+		lastLimitedFrameTime = now
+		return 0
+	}
+
+	next := lastLimitedFrameTime + interval
+	if now < next {
+		lastLimitedFrameTime = next
+		return next - now
+	}
+	if now-next > interval {
+		// The frame is too late. Reschedule the frames from now instead of rushing to catch up.
+		lastLimitedFrameTime = now
+		return 0
+	}
+	lastLimitedFrameTime = next
+	return 0
+}
+
+// FrameWait returns how long the caller should wait before the next frame to keep the max FPS.
+//
+// FrameWait is expected to be called per frame.
+func FrameWait() time.Duration {
+	m.Lock()
+	defer m.Unlock()
+	return time.Duration(calcFrameWait(now()))
+}
+
+// spinDuration is the duration to spin-wait at the end of Sleep.
+// OS timers can wake up later than requested by about a millisecond.
+const spinDuration = time.Millisecond
+
+// Sleep sleeps for the duration d precisely.
+//
+// Sleep sleeps with the OS timer except for the last spinDuration, and spin-waits the rest,
+// since the OS timer's resolution is not enough to keep a framerate precisely.
+func Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	deadline := now() + int64(d)
+	if d > spinDuration {
+		time.Sleep(d - spinDuration)
+	}
+	for now() < deadline {
+		runtime.Gosched()
+	}
 }
 
 const UncappedTPS = -1
@@ -131,5 +375,7 @@ func Update(tps int) int {
 		c = calcCountFromTPS(int64(tps), n)
 	}
 	updateFPSAndTPS(n, c)
+	updateFrameDropped(n, tps)
+	updateDeltaTime(n, c, tps)
 	return c
 }
@@ -0,0 +1,469 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clock
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMaxDeltaTime(t *testing.T) {
+	defer func() {
+		lastSystemTime = 0
+		maxDeltaTime = 0
+	}()
+
+	const tps = 60
+	cases := []struct {
+		MaxDeltaTime time.Duration
+		Delta        time.Duration
+		Want         int
+	}{
+		{0, time.Second / tps, 1},
+		{0, time.Hour, 1},
+		{time.Second / 4, time.Second / tps, 1},
+		{time.Second / 4, time.Second / 10, 6},
+		{time.Second / 4, time.Hour, 15},
+		{time.Second, time.Hour, 60},
+	}
+	for _, c := range cases {
+		lastSystemTime = 0
+		SetMaxDeltaTime(c.MaxDeltaTime)
+
+		start := int64(time.Hour)
+		calcCountFromTPS(tps, start)
+		got := calcCountFromTPS(tps, start+int64(c.Delta))
+		if got != c.Want {
+			t.Errorf("calcCountFromTPS with max delta %v and delta %v: got %d; want %d", c.MaxDeltaTime, c.Delta, got, c.Want)
+		}
+
+		// The dropped time must not be simulated later.
+		got = calcCountFromTPS(tps, start+int64(c.Delta)+int64(time.Second/tps))
+		if got != 1 {
+			t.Errorf("calcCountFromTPS after delta %v: got %d; want 1", c.Delta, got)
+		}
+	}
+}
+
+func TestFrameDropped(t *testing.T) {
+	defer func() {
+		lastFrameTime = 0
+		frameDropped = false
+	}()
+
+	cases := []struct {
+		TPS       int
+		FrameTime time.Duration
+		Want      bool
+	}{
+		{60, time.Second / 60, false},
+		{60, time.Second / 60 * 3 / 2, false},
+		{60, time.Second/60*3/2 + 1, true},
+		{60, time.Second / 30, true},
+		{30, time.Second / 30, false},
+		{30, time.Second / 15, true},
+		{120, time.Second / 60, false},
+		{120, time.Second / 30, true},
+		{UncappedTPS, time.Second / 60, false},
+		{UncappedTPS, time.Second / 20, true},
+	}
+	for _, c := range cases {
+		lastFrameTime = 0
+
+		start := int64(time.Hour)
+		updateFrameDropped(start, c.TPS)
+		if frameDropped {
+			t.Errorf("the first frame must not be dropped")
+		}
+		updateFrameDropped(start+int64(c.FrameTime), c.TPS)
+		if frameDropped != c.Want {
+			t.Errorf("TPS: %d, frame time: %v: got %v; want %v", c.TPS, c.FrameTime, frameDropped, c.Want)
+		}
+	}
+}
+
+func TestUpdateCountOverTime(t *testing.T) {
+	defer func() {
+		lastSystemTime = 0
+		maxDeltaTime = 0
+	}()
+
+	const duration = 10 * time.Second
+	cases := []struct {
+		TPS          int
+		FrameRate    int
+		MaxDeltaTime time.Duration
+	}{
+		{60, 60, 0},
+		{60, 144, 0},
+		{60, 30, 0},
+		{60, 20, 0},
+		{30, 60, 0},
+		{120, 60, 0},
+		{60, 15, time.Second / 4},
+	}
+	for _, c := range cases {
+		lastSystemTime = 0
+		SetMaxDeltaTime(c.MaxDeltaTime)
+
+		// The number of updates must not depend on the frame rate.
+		start := int64(time.Hour)
+		calcCountFromTPS(int64(c.TPS), start)
+		count := 0
+		frames := int(int64(duration) * int64(c.FrameRate) / int64(time.Second))
+		for i := 1; i <= frames; i++ {
+			now := start + int64(i)*int64(time.Second)/int64(c.FrameRate)
+			count += calcCountFromTPS(int64(c.TPS), now)
+		}
+		want := int(int64(duration) * int64(c.TPS) / int64(time.Second))
+		if count < want-1 || want+1 < count {
+			t.Errorf("TPS: %d, frame rate: %d: got %d updates; want %d", c.TPS, c.FrameRate, count, want)
+		}
+	}
+}
+
+func TestUpdateCountAfterLongFrame(t *testing.T) {
+	defer func() {
+		lastSystemTime = 0
+		maxDeltaTime = 0
+	}()
+
+	const tps = 60
+	cases := []struct {
+		MaxDeltaTime time.Duration
+		Want         int
+	}{
+		// By default, the game time is synced with the system clock and the stalled frame updates only once.
+		{0, 60 + 1 + 30},
+		// The stalled frame is simulated up to the max delta time.
+		{time.Second / 2, 60 + 30 + 30},
+	}
+	for _, c := range cases {
+		lastSystemTime = 0
+		SetMaxDeltaTime(c.MaxDeltaTime)
+
+		// Run at 60 FPS for a second, stall for a second, and then run for another 0.5 second.
+		start := int64(time.Hour)
+		calcCountFromTPS(tps, start)
+		now := start
+		count := 0
+		for i := 0; i < 60; i++ {
+			now += int64(time.Second) / 60
+			count += calcCountFromTPS(tps, now)
+		}
+		now += int64(time.Second)
+		count += calcCountFromTPS(tps, now)
+		for i := 0; i < 30; i++ {
+			now += int64(time.Second) / 60
+			count += calcCountFromTPS(tps, now)
+		}
+		if count != c.Want {
+			t.Errorf("max delta %v: got %d updates; want %d", c.MaxDeltaTime, count, c.Want)
+		}
+	}
+}
+
+func TestDeltaTime(t *testing.T) {
+	defer func() {
+		lastTickTime = 0
+		deltaTime = 0
+		maxDeltaTime = 0
+	}()
+
+	type frame struct {
+		Elapsed time.Duration
+		Count   int
+		Want    time.Duration
+	}
+	cases := []struct {
+		Name         string
+		TPS          int
+		MaxDeltaTime time.Duration
+		Frames       []frame
+	}{
+		{
+			Name: "first frame",
+			TPS:  60,
+			Frames: []frame{
+				{0, 1, time.Second / 60},
+			},
+		},
+		{
+			Name: "first frame with uncapped TPS",
+			TPS:  UncappedTPS,
+			Frames: []frame{
+				{0, 1, time.Second / 60},
+			},
+		},
+		{
+			Name: "regular frames",
+			TPS:  60,
+			Frames: []frame{
+				{0, 1, time.Second / 60},
+				{time.Second / 60, 1, time.Second / 60},
+				{time.Second / 50, 1, time.Second / 50},
+			},
+		},
+		{
+			Name: "catch up",
+			TPS:  60,
+			Frames: []frame{
+				{0, 1, time.Second / 60},
+				{time.Second / 20, 3, time.Second / 60},
+			},
+		},
+		{
+			Name: "frames without ticks",
+			TPS:  30,
+			Frames: []frame{
+				{0, 1, time.Second / 30},
+				{20 * time.Millisecond, 0, time.Second / 30},
+				{20 * time.Millisecond, 1, 40 * time.Millisecond},
+			},
+		},
+		{
+			Name: "resume",
+			TPS:  60,
+			Frames: []frame{
+				{0, 1, time.Second / 60},
+				{time.Hour, 1, time.Second * 5 / 60},
+			},
+		},
+		{
+			Name:         "resume with max delta time",
+			TPS:          60,
+			MaxDeltaTime: time.Second / 4,
+			Frames: []frame{
+				{0, 1, time.Second / 60},
+				{time.Hour, 15, time.Second / 60},
+			},
+		},
+	}
+	for _, c := range cases {
+		lastTickTime = 0
+		deltaTime = 0
+		maxDeltaTime = int64(c.MaxDeltaTime)
+
+		now := int64(time.Hour)
+		for i, f := range c.Frames {
+			now += int64(f.Elapsed)
+			updateDeltaTime(now, f.Count, c.TPS)
+			if got := DeltaTime(); got != f.Want {
+				t.Errorf("%s: frame %d: got %v; want %v", c.Name, i, got, f.Want)
+			}
+		}
+	}
+}
+
+func TestFPSAndTPS(t *testing.T) {
+	reset := func() {
+		frameRecordStart = 0
+		frameRecordNum = 0
+		tickSum = 0
+		currentFPS = 0
+		currentTPS = 0
+	}
+	defer reset()
+
+	type period struct {
+		Duration  time.Duration
+		FrameRate int
+		Count     int
+		WantFPS   float64
+		WantTPS   float64
+	}
+	cases := []struct {
+		Name    string
+		Periods []period
+	}{
+		{
+			Name: "60 FPS",
+			Periods: []period{
+				{time.Second, 60, 1, 60, 60},
+			},
+		},
+		{
+			Name: "catch up",
+			Periods: []period{
+				{time.Second, 30, 2, 30, 60},
+			},
+		},
+		{
+			Name: "high framerate",
+			Periods: []period{
+				{time.Second / 2, 200, 0, 200, 0},
+			},
+		},
+		{
+			// The window contains only the frames in the last second.
+			Name: "framerate change",
+			Periods: []period{
+				{2 * time.Second, 60, 1, 60, 60},
+				{time.Second / 2, 30, 2, 45, 60},
+				{time.Second / 2, 30, 2, 30, 60},
+			},
+		},
+		{
+			// More frames than the ring buffer in a second.
+			Name: "very high framerate",
+			Periods: []period{
+				{2 * time.Second, 1000, 0, 1000, 0},
+			},
+		},
+	}
+	for _, c := range cases {
+		reset()
+
+		now := int64(time.Hour)
+		updateFPSAndTPS(now, 1)
+		for _, p := range c.Periods {
+			frames := int(int64(p.Duration) * int64(p.FrameRate) / int64(time.Second))
+			for i := 0; i < frames; i++ {
+				now += int64(time.Second) / int64(p.FrameRate)
+				updateFPSAndTPS(now, p.Count)
+			}
+			if got := CurrentFPS(); math.Abs(got-p.WantFPS) > 0.5 {
+				t.Errorf("%s: FPS: got %v; want %v", c.Name, got, p.WantFPS)
+			}
+			if got := CurrentTPS(); math.Abs(got-p.WantTPS) > 0.5 {
+				t.Errorf("%s: TPS: got %v; want %v", c.Name, got, p.WantTPS)
+			}
+		}
+	}
+}
+
+func TestFrameWait(t *testing.T) {
+	defer func() {
+		maxFPS = 0
+		lastLimitedFrameTime = 0
+	}()
+
+	const interval = time.Second / 100
+	cases := []struct {
+		Name      string
+		MaxFPS    int
+		FrameTime time.Duration
+		Want      time.Duration
+	}{
+		{"unlimited", 0, 0, 0},
+		{"fast frame", 100, interval / 4, interval * 3 / 4},
+		{"exact frame", 100, interval, 0},
+		{"slow frame", 100, interval * 3 / 2, 0},
+	}
+	for _, c := range cases {
+		lastLimitedFrameTime = 0
+		SetMaxFPS(c.MaxFPS)
+
+		now := int64(time.Hour)
+		if got := calcFrameWait(now); got != 0 {
+			t.Errorf("%s: the first frame: got %v; want 0", c.Name, time.Duration(got))
+		}
+		// Render frames that take FrameTime, and wait for the returned time.
+		for i := 0; i < 10; i++ {
+			now += int64(c.FrameTime)
+			got := time.Duration(calcFrameWait(now))
+			if got != c.Want {
+				t.Errorf("%s: frame %d: got %v; want %v", c.Name, i, got, c.Want)
+			}
+			now += int64(got)
+		}
+	}
+}
+
+func TestFrameWaitAfterLongFrame(t *testing.T) {
+	defer func() {
+		maxFPS = 0
+		lastLimitedFrameTime = 0
+	}()
+
+	SetMaxFPS(100)
+	const interval = int64(time.Second / 100)
+	now := int64(time.Hour)
+	calcFrameWait(now)
+
+	// A slightly late frame is compensated by the next frame.
+	now += interval * 3 / 2
+	if got := calcFrameWait(now); got != 0 {
+		t.Errorf("late frame: got %v; want 0", time.Duration(got))
+	}
+	if got, want := calcFrameWait(now), interval/2; got != want {
+		t.Errorf("the frame after the late frame: got %v; want %v", time.Duration(got), time.Duration(want))
+	}
+
+	// After a very long frame, the frames are rescheduled from the current time.
+	now += int64(time.Second)
+	if got := calcFrameWait(now); got != 0 {
+		t.Errorf("long frame: got %v; want 0", time.Duration(got))
+	}
+	if got, want := calcFrameWait(now), interval; got != want {
+		t.Errorf("the frame after the long frame: got %v; want %v", time.Duration(got), time.Duration(want))
+	}
+}
+
+func TestFrameWaitRate(t *testing.T) {
+	defer func() {
+		maxFPS = 0
+		lastLimitedFrameTime = 0
+	}()
+
+	const duration = int64(10 * time.Second)
+	const refresh = int64(time.Second / 60)
+	cases := []struct {
+		Name   string
+		MaxFPS int
+		Vsync  bool
+	}{
+		{"30 FPS", 30, false},
+		{"144 FPS", 144, false},
+		{"240 FPS", 240, false},
+		{"45 FPS with vsync", 45, true},
+		{"50 FPS with vsync", 50, true},
+	}
+	for _, c := range cases {
+		lastLimitedFrameTime = 0
+		SetMaxFPS(c.MaxFPS)
+
+		start := int64(time.Hour)
+		now := start
+		frames := 0
+		for now-start < duration {
+			// Render a frame, that takes 0-2ms.
+			now += int64(frames%5) * int64(time.Millisecond) / 2
+			if c.Vsync {
+				// Swapping buffers waits for the next refresh.
+				now += refresh - now%refresh
+			}
+			// Waiting can be late by 0-0.3ms.
+			now += calcFrameWait(now) + int64(frames%4)*int64(time.Millisecond)/10
+			frames++
+		}
+
+		got := float64(frames) * float64(time.Second) / float64(now-start)
+		if want := float64(c.MaxFPS); math.Abs(got-want) > want*0.01 {
+			t.Errorf("%s: got %f FPS; want %f FPS", c.Name, got, want)
+		}
+	}
+}
+
+func TestSleep(t *testing.T) {
+	for _, d := range []time.Duration{0, time.Millisecond / 2, 3 * time.Millisecond} {
+		start := now()
+		Sleep(d)
+		if got := time.Duration(now() - start); got < d {
+			t.Errorf("Sleep(%v): slept %v; want >= %v", d, got, d)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gamepaddb parses SDL game controller mappings.
+//
+// The format is same as SDL_GameControllerDB:
+//
+//     GUID,name,a:b0,b:b1,dpup:h0.1,lefttrigger:a2,platform:Windows,
+//
+// As GLFW 3.2 doesn't provide GUIDs of joysticks, mappings are looked up by names.
+package gamepaddb
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type StandardButton int
+
+const (
+	StandardButtonA StandardButton = iota
+	StandardButtonB
+	StandardButtonX
+	StandardButtonY
+	StandardButtonBack
+	StandardButtonGuide
+	StandardButtonStart
+	StandardButtonLeftStick
+	StandardButtonRightStick
+	StandardButtonLeftShoulder
+	StandardButtonRightShoulder
+	StandardButtonDPadUp
+	StandardButtonDPadDown
+	StandardButtonDPadLeft
+	StandardButtonDPadRight
+	StandardButtonLeftTrigger
+	StandardButtonRightTrigger
+	StandardButtonMax = StandardButtonRightTrigger
+)
+
+var sdlButtonNames = map[string]StandardButton{
+	"a":             StandardButtonA,
+	"b":             StandardButtonB,
+	"x":             StandardButtonX,
+	"y":             StandardButtonY,
+	"back":          StandardButtonBack,
+	"guide":         StandardButtonGuide,
+	"start":         StandardButtonStart,
+	"leftstick":     StandardButtonLeftStick,
+	"rightstick":    StandardButtonRightStick,
+	"leftshoulder":  StandardButtonLeftShoulder,
+	"rightshoulder": StandardButtonRightShoulder,
+	"dpup":          StandardButtonDPadUp,
+	"dpdown":        StandardButtonDPadDown,
+	"dpleft":        StandardButtonDPadLeft,
+	"dpright":       StandardButtonDPadRight,
+	"lefttrigger":   StandardButtonLeftTrigger,
+	"righttrigger":  StandardButtonRightTrigger,
+}
+
+type elementType int
+
+const (
+	elementTypeButton elementType = iota
+	elementTypeAxis
+	elementTypeHat
+)
+
+type element struct {
+	typ   elementType
+	index int
+
+	// hatState is the bit of the hat state. This is used only for hats.
+	hatState int
+
+	// axisMin and axisMax represents the range of the axis regarded as pressed.
+	axisMin float64
+	axisMax float64
+
+	// axisInverted indicates whether the axis value is negated before it is checked. This is used only for axes.
+	axisInverted bool
+}
+
+// Hat states are bitmasks of the directions, same as SDL.
+const (
+	HatUp    = 1
+	HatRight = 2
+	HatDown  = 4
+	HatLeft  = 8
+)
+
+// Mapping represents a mapping from the standard buttons to the raw buttons and axes.
+type Mapping struct {
+	name    string
+	buttons map[StandardButton]element
+}
+
+// Name returns the name of the mapping.
+func (m *Mapping) Name() string {
+	return m.name
+}
+
+// IsButtonPressed returns a boolean value indicating whether the standard button is pressed
+// with the given raw button states, axis values and hat states.
+//
+// A hat state is a bitmask of HatUp, HatRight, HatDown and HatLeft.
+func (m *Mapping) IsButtonPressed(button StandardButton, buttons []bool, axes []float64, hats []int) bool {
+	e, ok := m.buttons[button]
+	if !ok {
+		return false
+	}
+	switch e.typ {
+	case elementTypeButton:
+		if e.index < 0 || len(buttons) <= e.index {
+			return false
+		}
+		return buttons[e.index]
+	case elementTypeAxis:
+		if e.index < 0 || len(axes) <= e.index {
+			return false
+		}
+		v := axes[e.index]
+		if e.axisInverted {
+			v = -v
+		}
+		const threshold = 0.5
+		if e.axisMin < 0 && e.axisMax <= 0 {
+			return v <= -threshold
+		}
+		if e.axisMin >= 0 && e.axisMax > 0 {
+			return v >= threshold
+		}
+		// The full-range axis like a trigger: -1 is released and 1 is fully pressed.
+		return v >= 0
+	case elementTypeHat:
+		if e.index < 0 || len(hats) <= e.index {
+			return false
+		}
+		return hats[e.index]&e.hatState != 0
+	default:
+		panic("not reached")
+	}
+}
+
+var (
+	mappings = map[string]*Mapping{}
+	m        sync.RWMutex
+)
+
+// Get returns the mapping for the given joystick name.
+//
+// Get returns nil when the mapping is not found.
+func Get(name string) *Mapping {
+	m.RLock()
+	defer m.RUnlock()
+	return mappings[name]
+}
+
+// Update adds the given mappings to the database.
+// Mappings for other platforms are ignored.
+//
+// Update returns an error when the given mappings include an invalid line.
+func Update(mappingData []byte) error {
+	ms := map[string]*Mapping{}
+
+	s := bufio.NewScanner(bytes.NewReader(mappingData))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		mapping, ok, err := parseLine(line, currentPlatform())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		ms[mapping.name] = mapping
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	for name, mapping := range ms {
+		mappings[name] = mapping
+	}
+	return nil
+}
+
+func currentPlatform() string {
+	switch runtime.GOOS {
+	case "windows":
+		return "Windows"
+	case "darwin":
+		if runtime.GOARCH == "arm" || runtime.GOARCH == "arm64" {
+			return "iOS"
+		}
+		return "Mac OS X"
+	case "linux", "freebsd":
+		return "Linux"
+	case "android":
+		return "Android"
+	}
+	return ""
+}
+
+func parseLine(line string, platform string) (*Mapping, bool, error) {
+	tokens := strings.Split(line, ",")
+	if len(tokens) < 2 {
+		return nil, false, fmt.Errorf("gamepaddb: invalid line: %q", line)
+	}
+	mapping := &Mapping{
+		name:    tokens[1],
+		buttons: map[StandardButton]element{},
+	}
+	for _, token := range tokens[2:] {
+		if token == "" {
+			continue
+		}
+		kv := strings.SplitN(token, ":", 2)
+		if len(kv) != 2 {
+			return nil, false, fmt.Errorf("gamepaddb: invalid token: %q", token)
+		}
+		if kv[0] == "platform" {
+			if kv[1] != platform {
+				return nil, false, nil
+			}
+			continue
+		}
+		b, ok := sdlButtonNames[kv[0]]
+		if !ok {
+			// Axes of the sticks and unknown keys are ignored.
+			continue
+		}
+		e, err := parseElement(kv[1])
+		if err != nil {
+			return nil, false, err
+		}
+		mapping.buttons[b] = e
+	}
+	return mapping, true, nil
+}
+
+func parseElement(str string) (element, error) {
+	e := element{
+		axisMin: -1,
+		axisMax: 1,
+	}
+
+	if str == "" {
+		return element{}, fmt.Errorf("gamepaddb: empty element")
+	}
+	switch str[0] {
+	case '+':
+		e.axisMin = 0
+		str = str[1:]
+	case '-':
+		e.axisMax = 0
+		str = str[1:]
+	}
+	if strings.HasSuffix(str, "~") {
+		e.axisInverted = true
+		str = str[:len(str)-1]
+	}
+
+	if len(str) < 2 {
+		return element{}, fmt.Errorf("gamepaddb: invalid element: %q", str)
+	}
+	switch str[0] {
+	case 'b':
+		e.typ = elementTypeButton
+		if e.axisInverted {
+			return element{}, fmt.Errorf("gamepaddb: a button cannot be inverted: %q", str)
+		}
+	case 'a':
+		e.typ = elementTypeAxis
+	case 'h':
+		e.typ = elementTypeHat
+		tokens := strings.SplitN(str[1:], ".", 2)
+		if len(tokens) != 2 {
+			return element{}, fmt.Errorf("gamepaddb: invalid hat: %q", str)
+		}
+		index, err := strconv.Atoi(tokens[0])
+		if err != nil {
+			return element{}, fmt.Errorf("gamepaddb: invalid hat: %q", str)
+		}
+		state, err := strconv.Atoi(tokens[1])
+		if err != nil {
+			return element{}, fmt.Errorf("gamepaddb: invalid hat: %q", str)
+		}
+		if e.axisInverted {
+			return element{}, fmt.Errorf("gamepaddb: a hat cannot be inverted: %q", str)
+		}
+		e.index = index
+		e.hatState = state
+		return e, nil
+	default:
+		return element{}, fmt.Errorf("gamepaddb: invalid element: %q", str)
+	}
+
+	index, err := strconv.Atoi(str[1:])
+	if err != nil {
+		return element{}, fmt.Errorf("gamepaddb: invalid element: %q", str)
+	}
+	e.index = index
+	return e, nil
+}
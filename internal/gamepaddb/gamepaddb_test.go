@@ -0,0 +1,151 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gamepaddb_test
+
+import (
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten/internal/gamepaddb"
+)
+
+func TestUpdate(t *testing.T) {
+	const mappings = `# Comment
+0300000000000000000000000000000,Test Pad,a:b1,b:b0,lefttrigger:a2,dpleft:-a0,dpright:+a0,dpup:h0.1,leftx:a0,
+0300000000000000000000000000001,Other Platform Pad,a:b0,platform:Unknown,
+`
+	if err := Update([]byte(mappings)); err != nil {
+		t.Fatal(err)
+	}
+	if Get("Other Platform Pad") != nil {
+		t.Errorf("Get(%q): got non-nil; want nil", "Other Platform Pad")
+	}
+	m := Get("Test Pad")
+	if m == nil {
+		t.Fatalf("Get(%q): got nil; want non-nil", "Test Pad")
+	}
+
+	buttons := []bool{false, true}
+	axes := []float64{-1, 0, 1}
+	cases := []struct {
+		Button StandardButton
+		Want   bool
+	}{
+		{StandardButtonA, true},
+		{StandardButtonB, false},
+		{StandardButtonX, false},
+		{StandardButtonLeftTrigger, true},
+		{StandardButtonDPadLeft, true},
+		{StandardButtonDPadRight, false},
+		{StandardButtonDPadUp, false},
+	}
+	for _, c := range cases {
+		got := m.IsButtonPressed(c.Button, buttons, axes, nil)
+		if got != c.Want {
+			t.Errorf("IsButtonPressed(%d): got %v; want %v", c.Button, got, c.Want)
+		}
+	}
+}
+
+func TestHat(t *testing.T) {
+	// A DirectInput gamepad whose D-pad is reported as a hat.
+	const mappings = `03000000790000000600000000000000,G-Shark GS-GP702,a:b2,b:b1,back:b8,dpdown:h0.4,dpleft:h0.8,dpright:h0.2,dpup:h0.1,leftshoulder:b4,leftstick:b10,lefttrigger:b6,leftx:a0,lefty:a1,rightshoulder:b5,rightstick:b11,righttrigger:b7,rightx:a2,righty:a4,start:b9,x:b3,y:b0,platform:Windows,`
+	m, err := ParseLineForTesting(mappings, "Windows")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buttons := make([]bool, 12)
+	axes := make([]float64, 5)
+	cases := []struct {
+		Hat   int
+		Up    bool
+		Down  bool
+		Left  bool
+		Right bool
+	}{
+		{0, false, false, false, false},
+		{HatUp, true, false, false, false},
+		{HatDown, false, true, false, false},
+		{HatLeft, false, false, true, false},
+		{HatRight, false, false, false, true},
+		{HatUp | HatRight, true, false, false, true},
+		{HatDown | HatLeft, false, true, true, false},
+	}
+	for _, c := range cases {
+		hats := []int{c.Hat}
+		for _, b := range []struct {
+			Button StandardButton
+			Want   bool
+		}{
+			{StandardButtonDPadUp, c.Up},
+			{StandardButtonDPadDown, c.Down},
+			{StandardButtonDPadLeft, c.Left},
+			{StandardButtonDPadRight, c.Right},
+		} {
+			if got := m.IsButtonPressed(b.Button, buttons, axes, hats); got != b.Want {
+				t.Errorf("IsButtonPressed(%d) with hat %d: got %v; want %v", b.Button, c.Hat, got, b.Want)
+			}
+		}
+	}
+
+	// A hat that doesn't exist is never pressed.
+	if m.IsButtonPressed(StandardButtonDPadUp, buttons, axes, nil) {
+		t.Errorf("IsButtonPressed(%d) without hats: got true; want false", StandardButtonDPadUp)
+	}
+}
+
+func TestInvertedAxis(t *testing.T) {
+	// A gamepad whose triggers are reported as inverted axes: -1 is fully pressed and 1 is released.
+	const mappings = `030000006f0e00001304000000010000,Generic X-Box pad,a:b0,b:b1,back:b6,dpdown:h0.4,dpleft:h0.8,dpright:h0.2,dpup:h0.1,guide:b8,leftshoulder:b4,leftstick:b9,lefttrigger:a2~,leftx:a0,lefty:a1,rightshoulder:b5,rightstick:b10,righttrigger:a5~,rightx:a3,righty:a4,start:b7,x:b2,y:b3,platform:Linux,`
+	m, err := ParseLineForTesting(mappings, "Linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buttons := make([]bool, 11)
+	cases := []struct {
+		Axis float64
+		Want bool
+	}{
+		{1, false},
+		{0.5, false},
+		{-0.5, true},
+		{-1, true},
+	}
+	for _, c := range cases {
+		axes := []float64{0, 0, c.Axis, 0, 0, 1}
+		if got := m.IsButtonPressed(StandardButtonLeftTrigger, buttons, axes, nil); got != c.Want {
+			t.Errorf("IsButtonPressed(%d) with axis %v: got %v; want %v", StandardButtonLeftTrigger, c.Axis, got, c.Want)
+		}
+		if m.IsButtonPressed(StandardButtonRightTrigger, buttons, axes, nil) {
+			t.Errorf("IsButtonPressed(%d) with the released axis: got true; want false", StandardButtonRightTrigger)
+		}
+	}
+}
+
+func TestUpdateError(t *testing.T) {
+	for _, str := range []string{
+		"invalid",
+		"0300000000000000000000000000000,Pad,a:c1,",
+		"0300000000000000000000000000000,Pad,a:bx,",
+		"0300000000000000000000000000000,Pad,a,",
+		"0300000000000000000000000000000,Pad,a:b0~,",
+		"0300000000000000000000000000000,Pad,dpup:h0.1~,",
+	} {
+		if err := Update([]byte(str)); err == nil {
+			t.Errorf("Update(%q): got nil; want error", str)
+		}
+	}
+}
@@ -97,6 +97,10 @@ func (w *Window) GetCursorPos() (x, y float64) {
 	return w.w.GetCursorPos()
 }
 
+func (w *Window) GetFramebufferSize() (width, height int) {
+	return w.w.GetFramebufferSize()
+}
+
 func (w *Window) GetInputMode(mode InputMode) int {
 	return w.w.GetInputMode(glfw.InputMode(mode))
 }
@@ -238,6 +242,10 @@ func GetJoystickButtons(joy Joystick) []byte {
 	return glfw.GetJoystickButtons(glfw.Joystick(joy))
 }
 
+func GetJoystickName(joy Joystick) string {
+	return glfw.GetJoystickName(glfw.Joystick(joy))
+}
+
 func GetMonitors() []*Monitor {
 	ms := []*Monitor{}
 	for _, m := range glfw.GetMonitors() {
@@ -118,6 +118,12 @@ func (w *Window) GetCursorPos() (x, y float64) {
 	return
 }
 
+func (w *Window) GetFramebufferSize() (width, height int) {
+	glfwDLL.call("glfwGetFramebufferSize", w.w, uintptr(unsafe.Pointer(&width)), uintptr(unsafe.Pointer(&height)))
+	panicError()
+	return
+}
+
 func (w *Window) GetInputMode(mode InputMode) int {
 	r := glfwDLL.call("glfwGetInputMode", w.w, uintptr(mode))
 	panicError()
@@ -326,6 +332,15 @@ func GetJoystickButtons(joy Joystick) []byte {
 	return bs
 }
 
+func GetJoystickName(joy Joystick) string {
+	ptr := glfwDLL.call("glfwGetJoystickName", uintptr(joy))
+	panicError()
+	if ptr == 0 {
+		return ""
+	}
+	return uintptrToString(ptr)
+}
+
 func GetMonitors() []*Monitor {
 	l := 0
 	ptr := glfwDLL.call("glfwGetMonitors", uintptr(unsafe.Pointer(&l)))
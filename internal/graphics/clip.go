@@ -0,0 +1,118 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphics
+
+import (
+	"math"
+)
+
+// maxVertexNum is the maximum number of vertices that uint16 indices can refer to.
+const maxVertexNum = math.MaxUint16 + 1
+
+// ClipTriangles clips the triangles to the rectangle (x0, y0)-(x1, y1) on the destination, and calls f with
+// the clipped vertices and indices. vertices and indices are in the same format as the arguments of DrawImage.
+//
+// The vertex attributes at the clipped edges are interpolated linearly, as the rasterizer does, then the rendering
+// result in the rectangle is not changed by clipping.
+//
+// If all the vertices are in the rectangle, f is called once with the given vertices and indices as they are.
+// Otherwise, f might be called multiple times so that each call doesn't exceed the limits of the vertices and
+// the indices. f is not called if all the triangles are out of the rectangle.
+func ClipTriangles(vertices []float32, indices []uint16, x0, y0, x1, y1 float32, f func(vertices []float32, indices []uint16)) {
+	const n = VertexFloatNum
+
+	inside := true
+	for _, idx := range indices {
+		x, y := vertices[n*int(idx)], vertices[n*int(idx)+1]
+		if x < x0 || x1 < x || y < y0 || y1 < y {
+			inside = false
+			break
+		}
+	}
+	if inside {
+		if len(indices) > 0 {
+			f(vertices, indices)
+		}
+		return
+	}
+
+	// The signed distances from the four edges of the rectangle. A vertex is inside when all of them are not negative.
+	planes := []func(v []float32) float32{
+		func(v []float32) float32 { return v[0] - x0 },
+		func(v []float32) float32 { return x1 - v[0] },
+		func(v []float32) float32 { return v[1] - y0 },
+		func(v []float32) float32 { return y1 - v[1] },
+	}
+
+	var vs []float32
+	var is []uint16
+	flush := func() {
+		if len(is) > 0 {
+			f(vs, is)
+		}
+		vs = nil
+		is = nil
+	}
+
+	var poly, next [][]float32
+	for t := 0; t+2 < len(indices); t += 3 {
+		poly = poly[:0]
+		for _, idx := range indices[t : t+3] {
+			poly = append(poly, vertices[n*int(idx):n*int(idx)+n])
+		}
+
+		// Sutherland-Hodgman algorithm.
+		for _, d := range planes {
+			next = next[:0]
+			for k, cur := range poly {
+				nxt := poly[(k+1)%len(poly)]
+				dc, dn := d(cur), d(nxt)
+				if dc >= 0 {
+					next = append(next, cur)
+				}
+				if (dc >= 0) != (dn >= 0) {
+					next = append(next, lerpVertex(cur, nxt, dc/(dc-dn)))
+				}
+			}
+			poly, next = next, poly
+			if len(poly) < 3 {
+				break
+			}
+		}
+		if len(poly) < 3 {
+			continue
+		}
+
+		if len(vs)/n+len(poly) > maxVertexNum || len(is)+3*(len(poly)-2) > IndicesNum {
+			flush()
+		}
+		base := uint16(len(vs) / n)
+		for _, v := range poly {
+			vs = append(vs, v...)
+		}
+		for k := 1; k < len(poly)-1; k++ {
+			is = append(is, base, base+uint16(k), base+uint16(k+1))
+		}
+	}
+	flush()
+}
+
+func lerpVertex(v0, v1 []float32, t float32) []float32 {
+	v := make([]float32, len(v0))
+	for i := range v {
+		v[i] = v0[i] + (v1[i]-v0[i])*t
+	}
+	return v
+}
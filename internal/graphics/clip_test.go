@@ -0,0 +1,138 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphics_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten/internal/graphics"
+)
+
+// vertex returns a vertex at (x, y) whose attributes are linear functions of the position.
+func vertex(x, y float32) []float32 {
+	v := make([]float32, VertexFloatNum)
+	v[0] = x
+	v[1] = y
+	for i := 2; i < VertexFloatNum; i++ {
+		v[i] = float32(i)*x - y + 1
+	}
+	return v
+}
+
+func trianglesArea(vertices []float32, indices []uint16) float64 {
+	area := 0.0
+	for t := 0; t < len(indices); t += 3 {
+		p := func(k int) (float64, float64) {
+			i := int(indices[t+k]) * VertexFloatNum
+			return float64(vertices[i]), float64(vertices[i+1])
+		}
+		ax, ay := p(0)
+		bx, by := p(1)
+		cx, cy := p(2)
+		area += math.Abs((bx-ax)*(cy-ay)-(cx-ax)*(by-ay)) / 2
+	}
+	return area
+}
+
+func TestClipTrianglesInside(t *testing.T) {
+	vs := append(append(append(vertex(1, 1), vertex(3, 1)...), vertex(1, 3)...), vertex(3, 3)...)
+	is := []uint16{0, 1, 2, 1, 2, 3}
+	calls := 0
+	ClipTriangles(vs, is, 0, 0, 4, 4, func(vertices []float32, indices []uint16) {
+		calls++
+		if &vertices[0] != &vs[0] || &indices[0] != &is[0] {
+			t.Errorf("the vertices and the indices must be passed as they are")
+		}
+	})
+	if calls != 1 {
+		t.Errorf("calls: got: %d, want: 1", calls)
+	}
+}
+
+func TestClipTrianglesOutside(t *testing.T) {
+	vs := append(append(vertex(10, 10), vertex(20, 10)...), vertex(10, 20)...)
+	ClipTriangles(vs, []uint16{0, 1, 2}, 0, 0, 4, 4, func(vertices []float32, indices []uint16) {
+		t.Errorf("f must not be called for triangles out of the rectangle")
+	})
+}
+
+func TestClipTrianglesPartial(t *testing.T) {
+	// A quad (-4, -2)-(12, 6) clipped by (2, 1)-(6, 5).
+	vs := append(append(append(vertex(-4, -2), vertex(12, -2)...), vertex(-4, 6)...), vertex(12, 6)...)
+	is := []uint16{0, 1, 2, 1, 2, 3}
+
+	area := 0.0
+	ClipTriangles(vs, is, 2, 1, 6, 5, func(vertices []float32, indices []uint16) {
+		for i := 0; i < len(vertices); i += VertexFloatNum {
+			v := vertices[i : i+VertexFloatNum]
+			const eps = 1e-4
+			if v[0] < 2-eps || 6+eps < v[0] || v[1] < 1-eps || 5+eps < v[1] {
+				t.Errorf("vertex (%v, %v) must be in the rectangle", v[0], v[1])
+			}
+			// The attributes must be interpolated linearly.
+			want := vertex(v[0], v[1])
+			for j := 2; j < VertexFloatNum; j++ {
+				if math.Abs(float64(v[j]-want[j])) > eps {
+					t.Errorf("attribute %d at (%v, %v): got: %v, want: %v", j, v[0], v[1], v[j], want[j])
+				}
+			}
+		}
+		area += trianglesArea(vertices, indices)
+	})
+	if math.Abs(area-16) > 1e-4 {
+		t.Errorf("area: got: %v, want: 16", area)
+	}
+}
+
+func TestClipTrianglesLimits(t *testing.T) {
+	// Each triangle is clipped into a hexagon, which needs 12 indices.
+	const num = IndicesNum / 3
+	var vs []float32
+	var is []uint16
+	for i := 0; i < num; i++ {
+		vs = append(vs, vertex(-1, 0)...)
+		vs = append(vs, vertex(3, 0)...)
+		vs = append(vs, vertex(1, 4)...)
+		base := uint16(3 * (i % (math.MaxUint16 / 3)))
+		is = append(is, base, base+1, base+2)
+	}
+
+	calls := 0
+	area := 0.0
+	ClipTriangles(vs, is, 0, 0, 2, 3, func(vertices []float32, indices []uint16) {
+		calls++
+		if len(indices) > IndicesNum {
+			t.Errorf("len(indices): got: %d, want: <= %d", len(indices), IndicesNum)
+		}
+		if n := len(vertices) / VertexFloatNum; n > math.MaxUint16+1 {
+			t.Errorf("the number of vertices: got: %d, want: <= %d", n, math.MaxUint16+1)
+		}
+		for _, idx := range indices {
+			if int(idx)*VertexFloatNum >= len(vertices) {
+				t.Fatalf("index %d is out of range", idx)
+			}
+		}
+		area += trianglesArea(vertices, indices)
+	})
+	if calls < 2 {
+		t.Errorf("calls: got: %d, want: >= 2", calls)
+	}
+	// The clipped area of each triangle: the rectangle (0, 0)-(2, 3) minus two corner triangles of 1 * 0.5 / 2.
+	want := num * (6 - 2*1*0.5/2)
+	if math.Abs(area-want)/want > 1e-4 {
+		t.Errorf("area: got: %v, want: %v", area, want)
+	}
+}
@@ -44,9 +44,25 @@ const (
 	DstAlpha
 	OneMinusSrcAlpha
 	OneMinusDstAlpha
+	SrcColor
+	DstColor
+	OneMinusSrcColor
+	OneMinusDstColor
 )
 
+// compositeModeCustom is a flag for composite modes with custom operations.
+// The lower bits represent the source and the destination operations.
+const compositeModeCustom CompositeMode = 1 << 8
+
+// CustomCompositeMode returns a composite mode with the given source and destination operations.
+func CustomCompositeMode(src, dst Operation) CompositeMode {
+	return compositeModeCustom | CompositeMode(src)<<4 | CompositeMode(dst)
+}
+
 func (c CompositeMode) Operations() (src Operation, dst Operation) {
+	if c&compositeModeCustom != 0 {
+		return Operation((c >> 4) & 0xf), Operation(c & 0xf)
+	}
 	switch c {
 	case CompositeModeSourceOver:
 		return One, OneMinusSrcAlpha
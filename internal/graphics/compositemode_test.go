@@ -0,0 +1,43 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphics_test
+
+import (
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten/internal/graphics"
+)
+
+func TestCustomCompositeMode(t *testing.T) {
+	ops := []Operation{Zero, One, SrcAlpha, DstAlpha, OneMinusSrcAlpha, OneMinusDstAlpha, SrcColor, DstColor, OneMinusSrcColor, OneMinusDstColor}
+	modes := map[CompositeMode]struct{}{}
+	for c := CompositeModeSourceOver; c <= CompositeModeMax; c++ {
+		modes[c] = struct{}{}
+	}
+	for _, src := range ops {
+		for _, dst := range ops {
+			c := CustomCompositeMode(src, dst)
+			if _, ok := modes[c]; ok {
+				t.Errorf("CustomCompositeMode(%d, %d) conflicts with another mode", src, dst)
+			}
+			modes[c] = struct{}{}
+
+			s, d := c.Operations()
+			if s != src || d != dst {
+				t.Errorf("CustomCompositeMode(%d, %d).Operations(): got: (%d, %d), want: (%d, %d)", src, dst, s, d, src, dst)
+			}
+		}
+	}
+}
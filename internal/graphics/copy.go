@@ -32,6 +32,11 @@ func CopyImage(img image.Image) []byte {
 	bs := make([]byte, 4*w*h)
 
 	switch img := img.(type) {
+	case *image.RGBA:
+		// Even img is a subimage of another image, Pix starts with 0-th index.
+		for j := 0; j < h; j++ {
+			copy(bs[4*w*j:4*w*(j+1)], img.Pix[img.Stride*j:img.Stride*j+4*w])
+		}
 	case *image.Paletted:
 		b := img.Bounds()
 		x0 := b.Min.X
@@ -73,3 +78,94 @@ func CopyImage(img image.Image) []byte {
 	}
 	return bs
 }
+
+// CopyPremultipliedImage copies img to a new RGBA image, regarding img's color values as
+// premultiplied by alpha even if img's color model is straight alpha.
+//
+// Colors with a straight-alpha color model like color.NRGBA are copied without multiplying
+// the color values by alpha. Colors with the other color models are copied as they are,
+// since they are already premultiplied.
+// The color values that are more than alpha are clamped to alpha.
+//
+// CopyPremultipliedImage is used only internally but it is exposed for testing.
+func CopyPremultipliedImage(img image.Image) []byte {
+	size := img.Bounds().Size()
+	w, h := size.X, size.Y
+	bs := make([]byte, 4*w*h)
+
+	switch img := img.(type) {
+	case *image.NRGBA:
+		// Even img is a subimage of another image, Pix starts with 0-th index.
+		for j := 0; j < h; j++ {
+			copy(bs[4*w*j:4*w*(j+1)], img.Pix[img.Stride*j:img.Stride*j+4*w])
+		}
+	default:
+		b := img.Bounds()
+		idx := 0
+		for j := b.Min.Y; j < b.Max.Y; j++ {
+			for i := b.Min.X; i < b.Max.X; i++ {
+				switch c := img.At(i, j).(type) {
+				case color.NRGBA:
+					bs[idx] = c.R
+					bs[idx+1] = c.G
+					bs[idx+2] = c.B
+					bs[idx+3] = c.A
+				case color.NRGBA64:
+					bs[idx] = uint8(c.R >> 8)
+					bs[idx+1] = uint8(c.G >> 8)
+					bs[idx+2] = uint8(c.B >> 8)
+					bs[idx+3] = uint8(c.A >> 8)
+				default:
+					// Colors of the other color models are already premultiplied.
+					r, g, b, a := c.RGBA()
+					bs[idx] = uint8(r >> 8)
+					bs[idx+1] = uint8(g >> 8)
+					bs[idx+2] = uint8(b >> 8)
+					bs[idx+3] = uint8(a >> 8)
+				}
+				idx += 4
+			}
+		}
+	}
+
+	for i := 0; i < len(bs); i += 4 {
+		a := bs[i+3]
+		for j := 0; j < 3; j++ {
+			if bs[i+j] > a {
+				bs[i+j] = a
+			}
+		}
+	}
+	return bs
+}
+
+// subImager is implemented by images that have a SubImage method like *image.RGBA.
+type subImager interface {
+	SubImage(r image.Rectangle) image.Image
+}
+
+// regionImage is an image.Image that represents a region of another image.
+type regionImage struct {
+	image.Image
+	rect image.Rectangle
+}
+
+func (r *regionImage) Bounds() image.Rectangle {
+	return r.rect
+}
+
+// SubImage returns an image representing the region r of img without copying the pixels.
+//
+// If img has a SubImage method like *image.RGBA, SubImage uses it and the returned image shares
+// the pixels and the stride with img.
+//
+// r must be in the bounds of img.
+func SubImage(img image.Image, r image.Rectangle) image.Image {
+	if s, ok := img.(subImager); ok {
+		return s.SubImage(r)
+	}
+	return &regionImage{
+		Image: img,
+		rect:  r,
+	}
+}
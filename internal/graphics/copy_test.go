@@ -19,6 +19,7 @@ import (
 	"image"
 	"image/color"
 	"image/color/palette"
+	"image/draw"
 	"testing"
 
 	. "github.com/hajimehoshi/ebiten/internal/graphics"
@@ -101,6 +102,92 @@ func TestCopyImage(t *testing.T) {
 	}
 }
 
+// imageWithoutSubImage hides the SubImage method of the embedded image.
+type imageWithoutSubImage struct {
+	image.Image
+}
+
+func TestSubImage(t *testing.T) {
+	rgba := image.NewRGBA(image.Rect(0, 0, 7, 5))
+	for j := 0; j < 5; j++ {
+		for i := 0; i < 7; i++ {
+			rgba.Pix[rgba.PixOffset(i, j)] = uint8(i)
+			rgba.Pix[rgba.PixOffset(i, j)+1] = uint8(j)
+			rgba.Pix[rgba.PixOffset(i, j)+2] = uint8(i * j)
+			rgba.Pix[rgba.PixOffset(i, j)+3] = 0xff
+		}
+	}
+	nrgba := image.NewNRGBA(rgba.Bounds())
+	draw.Draw(nrgba, nrgba.Bounds(), rgba, image.ZP, draw.Src)
+
+	cases := []struct {
+		Name string
+		In   image.Image
+		Rect image.Rectangle
+	}{
+		{"RGBA", rgba, image.Rect(2, 1, 5, 4)},
+		{"RGBA whole", rgba, rgba.Bounds()},
+		{"RGBA row", rgba, image.Rect(0, 4, 7, 5)},
+		{"NRGBA", nrgba, image.Rect(1, 2, 6, 3)},
+		{"without SubImage", imageWithoutSubImage{rgba}, image.Rect(3, 0, 7, 2)},
+		{"sub-image", rgba.SubImage(image.Rect(1, 1, 7, 5)), image.Rect(2, 2, 4, 5)},
+	}
+	for _, c := range cases {
+		// Compare with a manual crop.
+		crop := image.NewRGBA(image.Rect(0, 0, c.Rect.Dx(), c.Rect.Dy()))
+		draw.Draw(crop, crop.Bounds(), c.In, c.Rect.Min, draw.Src)
+
+		sub := SubImage(c.In, c.Rect)
+		if got := sub.Bounds(); got != c.Rect {
+			t.Errorf("%s: bounds: got: %v, want: %v", c.Name, got, c.Rect)
+		}
+		if got, want := CopyImage(sub), crop.Pix; !bytes.Equal(got, want) {
+			t.Errorf("%s: got: %v, want: %v", c.Name, got, want)
+		}
+	}
+}
+
+func TestCopyPremultipliedImage(t *testing.T) {
+	cases := []struct {
+		In  image.Image
+		Out []uint8
+	}{
+		{
+			In: &image.NRGBA{
+				Pix:    []uint8{0, 0, 0, 0, 0x80, 0x80, 0x80, 0x80, 0x40, 0x20, 0x10, 0x80, 0xff, 0xff, 0xff, 0x80},
+				Stride: 8,
+				Rect:   image.Rect(0, 0, 2, 2),
+			},
+			// The color values more than alpha are clamped.
+			Out: []uint8{0, 0, 0, 0, 0x80, 0x80, 0x80, 0x80, 0x40, 0x20, 0x10, 0x80, 0x80, 0x80, 0x80, 0x80},
+		},
+		{
+			In: (&image.NRGBA{
+				Pix:    []uint8{0, 0, 0, 0, 0x80, 0x80, 0x80, 0x80, 0x40, 0x20, 0x10, 0x80, 0, 0, 0, 0},
+				Stride: 8,
+				Rect:   image.Rect(0, 0, 2, 2),
+			}).SubImage(image.Rect(1, 0, 2, 2)),
+			Out: []uint8{0x80, 0x80, 0x80, 0x80, 0, 0, 0, 0},
+		},
+		{
+			In: &image.RGBA{
+				Pix:    []uint8{0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0x40, 0x20, 0x10, 0x80, 0, 0, 0, 0},
+				Stride: 8,
+				Rect:   image.Rect(0, 0, 2, 2),
+			},
+			// The RGBA image is already premultiplied.
+			Out: []uint8{0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0x40, 0x20, 0x10, 0x80, 0, 0, 0, 0},
+		},
+	}
+	for i, c := range cases {
+		got := CopyPremultipliedImage(c.In)
+		want := c.Out
+		if !bytes.Equal(got, want) {
+			t.Errorf("Test %d: got: %v, want: %v", i, got, want)
+		}
+	}
+}
+
 func BenchmarkCopyImageRGBA(b *testing.B) {
 	img := image.NewRGBA(image.Rect(0, 0, 4096, 4096))
 	b.ResetTimer()
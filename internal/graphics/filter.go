@@ -21,6 +21,7 @@ const (
 	FilterNearest
 	FilterLinear
 	FilterScreen
+	FilterBicubic
 )
 
 type Address int
@@ -28,4 +29,18 @@ type Address int
 const (
 	AddressClampToZero Address = iota
 	AddressRepeat
+	AddressMirroredRepeat
+)
+
+// ColorMask represents a set of color channels that are not written by rendering.
+type ColorMask int
+
+const (
+	ColorMaskRed ColorMask = 1 << iota
+	ColorMaskGreen
+	ColorMaskBlue
+	ColorMaskAlpha
+
+	// ColorMaskNone means that all the channels are written.
+	ColorMaskNone ColorMask = 0
 )
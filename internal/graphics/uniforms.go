@@ -0,0 +1,40 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphics
+
+// Uniforms represents values of uniform variables of a shader, keyed by the variable names.
+//
+// The number of the elements of a value represents the type: 1 for float, 2 for vec2, 3 for vec3,
+// 4 for vec4 and 16 for mat4.
+type Uniforms map[string][]float32
+
+// Equals reports whether u and other have the same values.
+func (u Uniforms) Equals(other Uniforms) bool {
+	if len(u) != len(other) {
+		return false
+	}
+	for k, v := range u {
+		w, ok := other[k]
+		if !ok || len(v) != len(w) {
+			return false
+		}
+		for i := range v {
+			if v[i] != w[i] {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphics_test
+
+import (
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten/internal/graphics"
+)
+
+func TestUniformsEquals(t *testing.T) {
+	cases := []struct {
+		U0   Uniforms
+		U1   Uniforms
+		Want bool
+	}{
+		{
+			U0:   nil,
+			U1:   Uniforms{},
+			Want: true,
+		},
+		{
+			U0:   Uniforms{"a": {1}},
+			U1:   Uniforms{"a": {1}},
+			Want: true,
+		},
+		{
+			U0:   Uniforms{"a": {1}},
+			U1:   Uniforms{"a": {2}},
+			Want: false,
+		},
+		{
+			U0:   Uniforms{"a": {1}},
+			U1:   Uniforms{"b": {1}},
+			Want: false,
+		},
+		{
+			U0:   Uniforms{"a": {1, 2}},
+			U1:   Uniforms{"a": {1, 2, 3}},
+			Want: false,
+		},
+		{
+			U0:   Uniforms{"a": {1}, "b": {1, 2, 3, 4}},
+			U1:   Uniforms{"a": {1}},
+			Want: false,
+		},
+	}
+	for _, c := range cases {
+		if got := c.U0.Equals(c.U1); got != c.Want {
+			t.Errorf("%v.Equals(%v): got: %t, want: %t", c.U0, c.U1, got, c.Want)
+		}
+		if got := c.U1.Equals(c.U0); got != c.Want {
+			t.Errorf("%v.Equals(%v): got: %t, want: %t", c.U1, c.U0, got, c.Want)
+		}
+	}
+}
+
+func BenchmarkUniformsEquals(b *testing.B) {
+	u0 := Uniforms{
+		"time":   {1},
+		"offset": {1, 2},
+		"color":  {1, 2, 3, 4},
+		"matrix": {1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1},
+	}
+	u1 := Uniforms{}
+	for k, v := range u0 {
+		u1[k] = append([]float32{}, v...)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		u0.Equals(u1)
+	}
+}
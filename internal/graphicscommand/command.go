@@ -19,6 +19,7 @@ import (
 
 	"github.com/hajimehoshi/ebiten/internal/affine"
 	"github.com/hajimehoshi/ebiten/internal/graphics"
+	"github.com/hajimehoshi/ebiten/internal/graphicsdriver"
 )
 
 // command represents a drawing command.
@@ -35,7 +36,7 @@ type command interface {
 	NumIndices() int
 	AddNumVertices(n int)
 	AddNumIndices(n int)
-	CanMerge(dst, src *Image, color *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address) bool
+	CanMerge(dst, src *Image, color *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address, mask graphics.ColorMask, shader *Shader, uniforms graphics.Uniforms) bool
 }
 
 // commandQueue is a command queue for drawing commands.
@@ -87,12 +88,12 @@ func (q *commandQueue) appendIndices(indices []uint16, offset uint16) {
 	q.nindices += len(indices)
 }
 
-func (q *commandQueue) doEnqueueDrawImageCommand(dst, src *Image, nvertices, nindices int, color *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address, forceNewCommand bool) {
+func (q *commandQueue) doEnqueueDrawImageCommand(dst, src *Image, nvertices, nindices int, color *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address, mask graphics.ColorMask, shader *Shader, uniforms graphics.Uniforms, forceNewCommand bool) {
 	if nindices > graphics.IndicesNum {
 		panic("not reached")
 	}
 	if !forceNewCommand && 0 < len(q.commands) {
-		if last := q.commands[len(q.commands)-1]; last.CanMerge(dst, src, color, mode, filter, address) {
+		if last := q.commands[len(q.commands)-1]; last.CanMerge(dst, src, color, mode, filter, address, mask, shader, uniforms) {
 			last.AddNumVertices(nvertices)
 			last.AddNumIndices(nindices)
 			return
@@ -107,12 +108,15 @@ func (q *commandQueue) doEnqueueDrawImageCommand(dst, src *Image, nvertices, nin
 		mode:      mode,
 		filter:    filter,
 		address:   address,
+		mask:      mask,
+		shader:    shader,
+		uniforms:  uniforms,
 	}
 	q.commands = append(q.commands, c)
 }
 
 // EnqueueDrawImageCommand enqueues a drawing-image command.
-func (q *commandQueue) EnqueueDrawImageCommand(dst, src *Image, vertices []float32, indices []uint16, color *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address) {
+func (q *commandQueue) EnqueueDrawImageCommand(dst, src *Image, vertices []float32, indices []uint16, color *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address, mask graphics.ColorMask, shader *Shader, uniforms graphics.Uniforms) {
 	if len(indices) > graphics.IndicesNum {
 		panic("not reached")
 	}
@@ -129,7 +133,7 @@ func (q *commandQueue) EnqueueDrawImageCommand(dst, src *Image, vertices []float
 	q.nextIndex += len(vertices) / graphics.VertexFloatNum
 	q.tmpNumIndices += len(indices)
 
-	q.doEnqueueDrawImageCommand(dst, src, len(vertices), len(indices), color, mode, filter, address, split)
+	q.doEnqueueDrawImageCommand(dst, src, len(vertices), len(indices), color, mode, filter, address, mask, shader, uniforms, split)
 }
 
 // Enqueue enqueues a drawing command other than a draw-image command.
@@ -207,6 +211,12 @@ func FlushCommands() {
 	theCommandQueue.Flush()
 }
 
+// FinishCommands flushes the command queue and waits for all the commands to be completed by the GPU.
+func FinishCommands() {
+	theCommandQueue.Flush()
+	Driver().Finish()
+}
+
 // drawImageCommand represents a drawing command to draw an image on another image.
 type drawImageCommand struct {
 	dst       *Image
@@ -217,6 +227,9 @@ type drawImageCommand struct {
 	mode      graphics.CompositeMode
 	filter    graphics.Filter
 	address   graphics.Address
+	mask      graphics.ColorMask
+	shader    *Shader
+	uniforms  graphics.Uniforms
 }
 
 func (c *drawImageCommand) String() string {
@@ -232,7 +245,11 @@ func (c *drawImageCommand) Exec(indexOffset int) error {
 
 	c.dst.image.SetAsDestination()
 	c.src.image.SetAsSource()
-	if err := Driver().Draw(c.nindices, indexOffset, c.mode, c.color, c.filter, c.address); err != nil {
+	var shader graphicsdriver.Shader
+	if c.shader != nil {
+		shader = c.shader.shader
+	}
+	if err := Driver().Draw(c.nindices, indexOffset, c.mode, c.color, c.filter, c.address, c.mask, shader, c.uniforms); err != nil {
 		return err
 	}
 	return nil
@@ -256,7 +273,7 @@ func (c *drawImageCommand) AddNumIndices(n int) {
 
 // CanMerge returns a boolean value indicating whether the other drawImageCommand can be merged
 // with the drawImageCommand c.
-func (c *drawImageCommand) CanMerge(dst, src *Image, color *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address) bool {
+func (c *drawImageCommand) CanMerge(dst, src *Image, color *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address, mask graphics.ColorMask, shader *Shader, uniforms graphics.Uniforms) bool {
 	if c.dst != dst {
 		return false
 	}
@@ -275,6 +292,15 @@ func (c *drawImageCommand) CanMerge(dst, src *Image, color *affine.ColorM, mode
 	if c.address != address {
 		return false
 	}
+	if c.mask != mask {
+		return false
+	}
+	if c.shader != shader {
+		return false
+	}
+	if !c.uniforms.Equals(uniforms) {
+		return false
+	}
 	return true
 }
 
@@ -312,7 +338,7 @@ func (c *replacePixelsCommand) AddNumVertices(n int) {
 func (c *replacePixelsCommand) AddNumIndices(n int) {
 }
 
-func (c *replacePixelsCommand) CanMerge(dst, src *Image, color *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address) bool {
+func (c *replacePixelsCommand) CanMerge(dst, src *Image, color *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address, mask graphics.ColorMask, shader *Shader, uniforms graphics.Uniforms) bool {
 	return false
 }
 
@@ -349,7 +375,7 @@ func (c *pixelsCommand) AddNumVertices(n int) {
 func (c *pixelsCommand) AddNumIndices(n int) {
 }
 
-func (c *pixelsCommand) CanMerge(dst, src *Image, color *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address) bool {
+func (c *pixelsCommand) CanMerge(dst, src *Image, color *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address, mask graphics.ColorMask, shader *Shader, uniforms graphics.Uniforms) bool {
 	return false
 }
 
@@ -382,7 +408,40 @@ func (c *disposeCommand) AddNumVertices(n int) {
 func (c *disposeCommand) AddNumIndices(n int) {
 }
 
-func (c *disposeCommand) CanMerge(dst, src *Image, color *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address) bool {
+func (c *disposeCommand) CanMerge(dst, src *Image, color *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address, mask graphics.ColorMask, shader *Shader, uniforms graphics.Uniforms) bool {
+	return false
+}
+
+// disposeShaderCommand represents a command to dispose a shader.
+type disposeShaderCommand struct {
+	target *Shader
+}
+
+func (c *disposeShaderCommand) String() string {
+	return fmt.Sprintf("dispose-shader: target: %p", c.target)
+}
+
+// Exec executes the disposeShaderCommand.
+func (c *disposeShaderCommand) Exec(indexOffset int) error {
+	c.target.shader.Dispose()
+	return nil
+}
+
+func (c *disposeShaderCommand) NumVertices() int {
+	return 0
+}
+
+func (c *disposeShaderCommand) NumIndices() int {
+	return 0
+}
+
+func (c *disposeShaderCommand) AddNumVertices(n int) {
+}
+
+func (c *disposeShaderCommand) AddNumIndices(n int) {
+}
+
+func (c *disposeShaderCommand) CanMerge(dst, src *Image, color *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address, mask graphics.ColorMask, shader *Shader, uniforms graphics.Uniforms) bool {
 	return false
 }
 
@@ -421,7 +480,7 @@ func (c *newImageCommand) AddNumVertices(n int) {
 func (c *newImageCommand) AddNumIndices(n int) {
 }
 
-func (c *newImageCommand) CanMerge(dst, src *Image, color *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address) bool {
+func (c *newImageCommand) CanMerge(dst, src *Image, color *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address, mask graphics.ColorMask, shader *Shader, uniforms graphics.Uniforms) bool {
 	return false
 }
 
@@ -457,7 +516,7 @@ func (c *newScreenFramebufferImageCommand) AddNumVertices(n int) {
 func (c *newScreenFramebufferImageCommand) AddNumIndices(n int) {
 }
 
-func (c *newScreenFramebufferImageCommand) CanMerge(dst, src *Image, color *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address) bool {
+func (c *newScreenFramebufferImageCommand) CanMerge(dst, src *Image, color *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address, mask graphics.ColorMask, shader *Shader, uniforms graphics.Uniforms) bool {
 	return false
 }
 
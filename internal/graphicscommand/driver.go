@@ -0,0 +1,71 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicscommand
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/internal/graphicsdriver"
+	"github.com/hajimehoshi/ebiten/internal/graphicsdriver/software"
+)
+
+var (
+	theDriver graphicsdriver.GraphicsDriver
+	driverM   sync.Mutex
+)
+
+// Driver returns the current graphics driver.
+//
+// If no driver is selected by SetDriver, Driver returns the platform's default driver.
+func Driver() graphicsdriver.GraphicsDriver {
+	driverM.Lock()
+	defer driverM.Unlock()
+	if theDriver == nil {
+		theDriver = defaultDriver()
+	}
+	return theDriver
+}
+
+// SetDriver selects the graphics driver by the given name.
+//
+// The available names are "opengl", "metal" (macOS only) and "software".
+// The empty name selects the platform's default driver.
+// SetDriver returns an error if the name is unknown or the driver is not available on the platform.
+//
+// SetDriver must be called before any graphics command is issued.
+func SetDriver(name string) error {
+	d, err := driverByName(name)
+	if err != nil {
+		return err
+	}
+	driverM.Lock()
+	theDriver = d
+	driverM.Unlock()
+	return nil
+}
+
+func driverByName(name string) (graphicsdriver.GraphicsDriver, error) {
+	switch name {
+	case "":
+		return defaultDriver(), nil
+	case "software":
+		return software.Get(), nil
+	}
+	if d := platformDriver(name); d != nil {
+		return d, nil
+	}
+	return nil, fmt.Errorf("graphicscommand: unknown or unavailable graphics driver: %q", name)
+}
@@ -52,9 +52,21 @@ func init() {
 	}
 }
 
-func Driver() graphicsdriver.GraphicsDriver {
+func defaultDriver() graphicsdriver.GraphicsDriver {
 	if isMetalSupported {
 		return metal.Get()
 	}
 	return opengl.Get()
 }
+
+func platformDriver(name string) graphicsdriver.GraphicsDriver {
+	switch name {
+	case "metal":
+		if isMetalSupported {
+			return metal.Get()
+		}
+	case "opengl":
+		return opengl.Get()
+	}
+	return nil
+}
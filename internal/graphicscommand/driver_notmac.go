@@ -21,6 +21,13 @@ import (
 	"github.com/hajimehoshi/ebiten/internal/graphicsdriver/opengl"
 )
 
-func Driver() graphicsdriver.GraphicsDriver {
+func defaultDriver() graphicsdriver.GraphicsDriver {
 	return opengl.Get()
 }
+
+func platformDriver(name string) graphicsdriver.GraphicsDriver {
+	if name == "opengl" {
+		return opengl.Get()
+	}
+	return nil
+}
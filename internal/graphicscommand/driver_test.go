@@ -0,0 +1,63 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicscommand
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/internal/graphicsdriver/software"
+)
+
+func TestDriverByName(t *testing.T) {
+	d, err := driverByName("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := d, defaultDriver(); got != want {
+		t.Errorf("driverByName(\"\"): got: %T, want: %T", got, want)
+	}
+
+	d, err = driverByName("opengl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.IsGL() {
+		t.Errorf("driverByName(\"opengl\").IsGL(): got: false, want: true")
+	}
+
+	d, err = driverByName("software")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := d, software.Get(); got != want {
+		t.Errorf("driverByName(\"software\"): got: %T, want: %T", got, want)
+	}
+
+	for _, name := range []string{"unknown", "OpenGL", "vulkan"} {
+		if _, err := driverByName(name); err == nil {
+			t.Errorf("driverByName(%q) must return an error", name)
+		}
+	}
+}
+
+func TestSetUnknownDriver(t *testing.T) {
+	d := Driver()
+	if err := SetDriver("unknown"); err == nil {
+		t.Errorf("SetDriver(\"unknown\") must return an error")
+	}
+	if got := Driver(); got != d {
+		t.Errorf("Driver() must not be changed by an unknown driver: got: %T, want: %T", got, d)
+	}
+}
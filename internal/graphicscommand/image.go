@@ -82,14 +82,14 @@ func (i *Image) Size() (int, int) {
 	return i.width, i.height
 }
 
-func (i *Image) DrawImage(src *Image, vertices []float32, indices []uint16, clr *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address) {
+func (i *Image) DrawImage(src *Image, vertices []float32, indices []uint16, clr *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address, mask graphics.ColorMask, shader *Shader, uniforms graphics.Uniforms) {
 	if i.lastCommand == lastCommandNone {
 		if !i.screen && mode != graphics.CompositeModeClear {
 			panic("graphicscommand: the image must be cleared first")
 		}
 	}
 
-	theCommandQueue.EnqueueDrawImageCommand(i, src, vertices, indices, clr, mode, filter, address)
+	theCommandQueue.EnqueueDrawImageCommand(i, src, vertices, indices, clr, mode, filter, address, mask, shader, uniforms)
 
 	if i.lastCommand == lastCommandNone && !i.screen {
 		i.lastCommand = lastCommandClear
@@ -138,3 +138,15 @@ func (i *Image) IsInvalidated() bool {
 	}
 	return i.image.IsInvalidated()
 }
+
+// BlitToDefaultFramebuffer copies the image to the default framebuffer of the current context.
+//
+// BlitToDefaultFramebuffer doesn't flush the command queue. The queue must be flushed before
+// switching the current context so that the commands are executed on the main context.
+func (i *Image) BlitToDefaultFramebuffer(dstWidth, dstHeight int) error {
+	if i.image == nil {
+		// The image is not initialized yet.
+		return nil
+	}
+	return i.image.BlitToDefaultFramebuffer(dstWidth, dstHeight)
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicscommand
+
+import (
+	"github.com/hajimehoshi/ebiten/internal/graphicsdriver"
+)
+
+// Shader represents a user-defined shader.
+type Shader struct {
+	shader graphicsdriver.Shader
+}
+
+// NewShader returns a new shader with the given source.
+//
+// NewShader returns an error when the source is invalid. If the graphics driver is initialized,
+// NewShader also compiles the source and returns the compiling error. Otherwise, the source is
+// compiled at the first draw call with the shader and a compiling error is reported by Error.
+func NewShader(src string) (*Shader, error) {
+	s, err := Driver().NewShader(src)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Compile(); err != nil {
+		return nil, err
+	}
+	return &Shader{
+		shader: s,
+	}, nil
+}
+
+// UniformSizes returns the numbers of float values of the declared uniform variables keyed by their names.
+//
+// The returned map must not be modified.
+func (s *Shader) UniformSizes() map[string]int {
+	return s.shader.UniformSizes()
+}
+
+func (s *Shader) Dispose() {
+	c := &disposeShaderCommand{
+		target: s,
+	}
+	theCommandQueue.Enqueue(c)
+}
@@ -23,10 +23,17 @@ type GraphicsDriver interface {
 	SetWindow(window uintptr)
 	SetVertices(vertices []float32, indices []uint16)
 	Flush()
+	Finish()
 	NewImage(width, height int) (Image, error)
 	NewScreenFramebufferImage(width, height int) (Image, error)
+
+	// NewShader creates a new shader with a user-defined source.
+	//
+	// NewShader only validates the source and must not call the graphics API,
+	// so that NewShader can be called before the graphics context is initialized.
+	NewShader(src string) (Shader, error)
 	Reset() error
-	Draw(indexLen int, indexOffset int, mode graphics.CompositeMode, colorM *affine.ColorM, filter graphics.Filter, address graphics.Address) error
+	Draw(indexLen int, indexOffset int, mode graphics.CompositeMode, colorM *affine.ColorM, filter graphics.Filter, address graphics.Address, mask graphics.ColorMask, shader Shader, uniforms graphics.Uniforms) error
 	SetVsyncEnabled(enabled bool)
 	VDirection() VDirection
 	IsGL() bool
@@ -39,6 +46,20 @@ type Image interface {
 	SetAsDestination()
 	SetAsSource()
 	ReplacePixels(pixels []byte, x, y, width, height int)
+	BlitToDefaultFramebuffer(dstWidth, dstHeight int) error
+}
+
+// Shader represents a user-defined shader.
+type Shader interface {
+	// Compile compiles the shader and returns an error with the driver's log if compiling fails.
+	// If the graphics driver is not initialized yet, Compile does nothing and returns nil, and
+	// the shader is compiled at the first draw call with the shader.
+	Compile() error
+
+	// UniformSizes returns the numbers of float values of the declared uniform variables keyed by their names.
+	UniformSizes() map[string]int
+
+	Dispose()
 }
 
 type VDirection int
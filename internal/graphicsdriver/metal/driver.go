@@ -35,9 +35,11 @@ const source = `#include <metal_stdlib>
 #define FILTER_NEAREST ({{.FilterNearest}})
 #define FILTER_LINEAR ({{.FilterLinear}})
 #define FILTER_SCREEN ({{.FilterScreen}})
+#define FILTER_BICUBIC ({{.FilterBicubic}})
 
 #define ADDRESS_CLAMP_TO_ZERO ({{.AddressClampToZero}})
 #define ADDRESS_REPEAT ({{.AddressRepeat}})
+#define ADDRESS_MIRRORED_REPEAT ({{.AddressMirroredRepeat}})
 
 using namespace metal;
 
@@ -99,7 +101,7 @@ float FloorMod(float x, float y) {
   return x - y * floor(x/y);
 }
 
-float2 AdjustTexelByAddress(float2 p, float4 tex_region, uint8_t address)  {
+float2 AdjustTexelByAddress(float2 p, float4 tex_region, float2 texel_size, uint8_t address)  {
   switch (address) {
   case ADDRESS_CLAMP_TO_ZERO: {
     return p;
@@ -109,6 +111,15 @@ float2 AdjustTexelByAddress(float2 p, float4 tex_region, uint8_t address)  {
     float2 size = float2(tex_region[2] - tex_region[0], tex_region[3] - tex_region[1]);
     return float2(FloorMod((p.x - o.x), size.x) + o.x, FloorMod((p.y - o.y), size.y) + o.y);
   }
+  case ADDRESS_MIRRORED_REPEAT: {
+    float2 o = float2(tex_region[0], tex_region[1]);
+    float2 size = float2(tex_region[2] - tex_region[0], tex_region[3] - tex_region[1]);
+    float2 t = float2(FloorMod((p.x - o.x), 2.0 * size.x), FloorMod((p.y - o.y), 2.0 * size.y));
+    // At the mirror boundary (t == size), the result is the region's max edge, which is out of the region.
+    // Clamp it a little inner than the region check's threshold.
+    float2 region_max = float2(tex_region[2], tex_region[3]) - texel_size / 256.0;
+    return min(size - abs(t - size) + o, region_max);
+  }
   default:
     // Not reached.
     break;
@@ -116,13 +127,33 @@ float2 AdjustTexelByAddress(float2 p, float4 tex_region, uint8_t address)  {
   return 0.0;
 }
 
+float4 SourceTexel(texture2d<float> texture, sampler texture_sampler, float2 texel_size, float2 p, float4 tex_region, uint8_t address) {
+  p = AdjustTexelByAddress(p, tex_region, texel_size, address);
+  if (p.x < tex_region[0] ||
+      p.y < tex_region[1] ||
+      (tex_region[2] - texel_size.x / 512.0) <= p.x ||
+      (tex_region[3] - texel_size.y / 512.0) <= p.y) {
+    return 0;
+  }
+  return texture.sample(texture_sampler, p);
+}
+
+float4 CatmullRomWeights(float f) {
+  return float4(
+    f * (-0.5 + f * (1.0 - 0.5 * f)),
+    1.0 + f * f * (-2.5 + 1.5 * f),
+    f * (0.5 + f * (2.0 - 1.5 * f)),
+    f * f * (-0.5 + 0.5 * f));
+}
+
 fragment float4 FragmentShader(VertexOut v [[stage_in]],
                                texture2d<float> texture [[texture(0)]],
                                constant float4x4& color_matrix_body [[buffer(2)]],
                                constant float4& color_matrix_translation [[buffer(3)]],
                                constant uint8_t& filter [[buffer(4)]],
                                constant uint8_t& address [[buffer(5)]],
-                               constant float& scale [[buffer(6)]]) {
+                               constant float& scale [[buffer(6)]],
+                               constant bool& color_matrix_linear [[buffer(7)]]) {
   constexpr sampler texture_sampler(filter::nearest);
   float2 source_size = 1;
   while (source_size.x < texture.get_width()) {
@@ -137,7 +168,7 @@ fragment float4 FragmentShader(VertexOut v [[stage_in]],
 
   switch (filter) {
   case FILTER_NEAREST: {
-    float2 p = AdjustTexelByAddress(v.tex, v.tex_region, address);
+    float2 p = AdjustTexelByAddress(v.tex, v.tex_region, texel_size, address);
     c = texture.sample(texture_sampler, p);
     if (p.x < v.tex_region[0] ||
         p.y < v.tex_region[1] ||
@@ -152,8 +183,8 @@ fragment float4 FragmentShader(VertexOut v [[stage_in]],
     float2 p0 = v.tex - texel_size / 2.0;
     float2 p1 = v.tex + texel_size / 2.0;
     p1 = AdjustTexel(source_size, p0, p1);
-    p0 = AdjustTexelByAddress(p0, v.tex_region, address);
-    p1 = AdjustTexelByAddress(p1, v.tex_region, address);
+    p0 = AdjustTexelByAddress(p0, v.tex_region, texel_size, address);
+    p1 = AdjustTexelByAddress(p1, v.tex_region, texel_size, address);
 
     float4 c0 = texture.sample(texture_sampler, p0);
     float4 c1 = texture.sample(texture_sampler, float2(p1.x, p0.y));
@@ -198,6 +229,25 @@ fragment float4 FragmentShader(VertexOut v [[stage_in]],
     break;
   }
 
+  case FILTER_BICUBIC: {
+    float2 q = v.tex * source_size - 0.5;
+    float2 p = (floor(q) + 0.5) * texel_size;
+    float4 wx = CatmullRomWeights(fract(q.x));
+    float4 wy = CatmullRomWeights(fract(q.y));
+
+    c = 0;
+    for (int j = 0; j < 4; j++) {
+      float4 row = 0;
+      for (int i = 0; i < 4; i++) {
+        row += wx[i] * SourceTexel(texture, texture_sampler, texel_size, p + float2(float(i - 1), float(j - 1)) * texel_size, v.tex_region, address);
+      }
+      c += wy[j] * row;
+    }
+    c = clamp(c, 0.0, 1.0);
+    c.rgb = min(c.rgb, float3(c.a));
+    break;
+  }
+
   default:
     // Not reached.
     discard_fragment();
@@ -207,21 +257,34 @@ fragment float4 FragmentShader(VertexOut v [[stage_in]],
   if (0 < c.a) {
     c.rgb /= c.a;
   }
+  if (color_matrix_linear) {
+    c.rgb = pow(c.rgb, float3(2.2));
+  }
   c = (color_matrix_body * c) + color_matrix_translation;
   c *= v.color;
   c = clamp(c, 0.0, 1.0);
+  if (color_matrix_linear) {
+    c.rgb = pow(c.rgb, float3(1.0 / 2.2));
+  }
   c.rgb *= c.a;
   return c;
 }
 `
 
+type rpsKey struct {
+	mode graphics.CompositeMode
+	mask graphics.ColorMask
+}
+
 type Driver struct {
 	window uintptr
 
 	device    mtl.Device
 	ml        ca.MetalLayer
 	screenRPS mtl.RenderPipelineState
-	rpss      map[graphics.CompositeMode]mtl.RenderPipelineState
+	rpss      map[rpsKey]mtl.RenderPipelineState
+	vs        mtl.Function
+	fs        mtl.Function
 	cq        mtl.CommandQueue
 	cb        mtl.CommandBuffer
 
@@ -284,6 +347,22 @@ func (d *Driver) Flush() {
 	})
 }
 
+func (d *Driver) Finish() {
+	mainthread.Run(func() error {
+		if d.cb == (mtl.CommandBuffer{}) {
+			return nil
+		}
+
+		// Unlike Flush, the screen drawable is not presented here. This is presented at the next Flush.
+		d.cb.Commit()
+		d.cb.WaitUntilCompleted()
+
+		d.cb = mtl.CommandBuffer{}
+
+		return nil
+	})
+}
+
 func (d *Driver) checkSize(width, height int) {
 	m := 0
 	mainthread.Run(func() error {
@@ -371,6 +450,11 @@ func (d *Driver) NewScreenFramebufferImage(width, height int) (graphicsdriver.Im
 	}, nil
 }
 
+// NewShader always returns an error since user-defined shaders are not supported on Metal so far.
+func (d *Driver) NewShader(src string) (graphicsdriver.Shader, error) {
+	return nil, fmt.Errorf("metal: user-defined shaders are not supported")
+}
+
 func (d *Driver) Reset() error {
 	if err := mainthread.Run(func() error {
 		if d.cq != (mtl.CommandQueue{}) {
@@ -379,9 +463,7 @@ func (d *Driver) Reset() error {
 		}
 
 		// TODO: Release existing rpss
-		if d.rpss == nil {
-			d.rpss = map[graphics.CompositeMode]mtl.RenderPipelineState{}
-		}
+		d.rpss = map[rpsKey]mtl.RenderPipelineState{}
 
 		var err error
 		d.device, err = mtl.CreateSystemDefaultDevice()
@@ -400,11 +482,13 @@ func (d *Driver) Reset() error {
 		d.ml.SetMaximumDrawableCount(3)
 
 		replaces := map[string]string{
-			"{{.FilterNearest}}":      fmt.Sprintf("%d", graphics.FilterNearest),
-			"{{.FilterLinear}}":       fmt.Sprintf("%d", graphics.FilterLinear),
-			"{{.FilterScreen}}":       fmt.Sprintf("%d", graphics.FilterScreen),
-			"{{.AddressClampToZero}}": fmt.Sprintf("%d", graphics.AddressClampToZero),
-			"{{.AddressRepeat}}":      fmt.Sprintf("%d", graphics.AddressRepeat),
+			"{{.FilterNearest}}":         fmt.Sprintf("%d", graphics.FilterNearest),
+			"{{.FilterLinear}}":          fmt.Sprintf("%d", graphics.FilterLinear),
+			"{{.FilterScreen}}":          fmt.Sprintf("%d", graphics.FilterScreen),
+			"{{.FilterBicubic}}":         fmt.Sprintf("%d", graphics.FilterBicubic),
+			"{{.AddressClampToZero}}":    fmt.Sprintf("%d", graphics.AddressClampToZero),
+			"{{.AddressRepeat}}":         fmt.Sprintf("%d", graphics.AddressRepeat),
+			"{{.AddressMirroredRepeat}}": fmt.Sprintf("%d", graphics.AddressMirroredRepeat),
 		}
 		src := source
 		for k, v := range replaces {
@@ -433,49 +517,19 @@ func (d *Driver) Reset() error {
 		rpld.ColorAttachments[0].DestinationRGBBlendFactor = mtl.BlendFactorZero
 		rpld.ColorAttachments[0].SourceAlphaBlendFactor = mtl.BlendFactorOne
 		rpld.ColorAttachments[0].SourceRGBBlendFactor = mtl.BlendFactorOne
+		rpld.ColorAttachments[0].WriteMask = mtl.ColorWriteMaskAll
 		rps, err := d.device.MakeRenderPipelineState(rpld)
 		if err != nil {
 			return err
 		}
 		d.screenRPS = rps
-
-		conv := func(c graphics.Operation) mtl.BlendFactor {
-			switch c {
-			case graphics.Zero:
-				return mtl.BlendFactorZero
-			case graphics.One:
-				return mtl.BlendFactorOne
-			case graphics.SrcAlpha:
-				return mtl.BlendFactorSourceAlpha
-			case graphics.DstAlpha:
-				return mtl.BlendFactorDestinationAlpha
-			case graphics.OneMinusSrcAlpha:
-				return mtl.BlendFactorOneMinusSourceAlpha
-			case graphics.OneMinusDstAlpha:
-				return mtl.BlendFactorOneMinusDestinationAlpha
-			default:
-				panic("not reached")
-			}
-		}
+		d.vs = vs
+		d.fs = fs
 
 		for c := graphics.CompositeModeSourceOver; c <= graphics.CompositeModeMax; c++ {
-			rpld := mtl.RenderPipelineDescriptor{
-				VertexFunction:   vs,
-				FragmentFunction: fs,
-			}
-			rpld.ColorAttachments[0].PixelFormat = mtl.PixelFormatRGBA8UNorm
-			rpld.ColorAttachments[0].BlendingEnabled = true
-
-			src, dst := c.Operations()
-			rpld.ColorAttachments[0].DestinationAlphaBlendFactor = conv(dst)
-			rpld.ColorAttachments[0].DestinationRGBBlendFactor = conv(dst)
-			rpld.ColorAttachments[0].SourceAlphaBlendFactor = conv(src)
-			rpld.ColorAttachments[0].SourceRGBBlendFactor = conv(src)
-			rps, err := d.device.MakeRenderPipelineState(rpld)
-			if err != nil {
+			if _, err := d.renderPipelineState(c, graphics.ColorMaskNone); err != nil {
 				return err
 			}
-			d.rpss[c] = rps
 		}
 
 		d.cq = d.device.MakeCommandQueue()
@@ -487,7 +541,85 @@ func (d *Driver) Reset() error {
 	return nil
 }
 
-func (d *Driver) Draw(indexLen int, indexOffset int, mode graphics.CompositeMode, colorM *affine.ColorM, filter graphics.Filter, address graphics.Address) error {
+func convertBlendFactor(c graphics.Operation) mtl.BlendFactor {
+	switch c {
+	case graphics.Zero:
+		return mtl.BlendFactorZero
+	case graphics.One:
+		return mtl.BlendFactorOne
+	case graphics.SrcAlpha:
+		return mtl.BlendFactorSourceAlpha
+	case graphics.DstAlpha:
+		return mtl.BlendFactorDestinationAlpha
+	case graphics.OneMinusSrcAlpha:
+		return mtl.BlendFactorOneMinusSourceAlpha
+	case graphics.OneMinusDstAlpha:
+		return mtl.BlendFactorOneMinusDestinationAlpha
+	case graphics.SrcColor:
+		return mtl.BlendFactorSourceColor
+	case graphics.DstColor:
+		return mtl.BlendFactorDestinationColor
+	case graphics.OneMinusSrcColor:
+		return mtl.BlendFactorOneMinusSourceColor
+	case graphics.OneMinusDstColor:
+		return mtl.BlendFactorOneMinusDestinationColor
+	default:
+		panic("not reached")
+	}
+}
+
+func convertColorMask(mask graphics.ColorMask) mtl.ColorWriteMask {
+	m := mtl.ColorWriteMaskAll
+	if mask&graphics.ColorMaskRed != 0 {
+		m &^= mtl.ColorWriteMaskRed
+	}
+	if mask&graphics.ColorMaskGreen != 0 {
+		m &^= mtl.ColorWriteMaskGreen
+	}
+	if mask&graphics.ColorMaskBlue != 0 {
+		m &^= mtl.ColorWriteMaskBlue
+	}
+	if mask&graphics.ColorMaskAlpha != 0 {
+		m &^= mtl.ColorWriteMaskAlpha
+	}
+	return m
+}
+
+// renderPipelineState returns a render pipeline state for offscreen images.
+// Render pipeline states are created lazily since there are many combinations of masks.
+//
+// renderPipelineState must be called on the main thread.
+func (d *Driver) renderPipelineState(mode graphics.CompositeMode, mask graphics.ColorMask) (mtl.RenderPipelineState, error) {
+	key := rpsKey{mode: mode, mask: mask}
+	if rps, ok := d.rpss[key]; ok {
+		return rps, nil
+	}
+
+	rpld := mtl.RenderPipelineDescriptor{
+		VertexFunction:   d.vs,
+		FragmentFunction: d.fs,
+	}
+	rpld.ColorAttachments[0].PixelFormat = mtl.PixelFormatRGBA8UNorm
+	rpld.ColorAttachments[0].BlendingEnabled = true
+
+	src, dst := mode.Operations()
+	rpld.ColorAttachments[0].DestinationAlphaBlendFactor = convertBlendFactor(dst)
+	rpld.ColorAttachments[0].DestinationRGBBlendFactor = convertBlendFactor(dst)
+	rpld.ColorAttachments[0].SourceAlphaBlendFactor = convertBlendFactor(src)
+	rpld.ColorAttachments[0].SourceRGBBlendFactor = convertBlendFactor(src)
+	rpld.ColorAttachments[0].WriteMask = convertColorMask(mask)
+	rps, err := d.device.MakeRenderPipelineState(rpld)
+	if err != nil {
+		return mtl.RenderPipelineState{}, err
+	}
+	d.rpss[key] = rps
+	return rps, nil
+}
+
+func (d *Driver) Draw(indexLen int, indexOffset int, mode graphics.CompositeMode, colorM *affine.ColorM, filter graphics.Filter, address graphics.Address, mask graphics.ColorMask, shader graphicsdriver.Shader, uniforms graphics.Uniforms) error {
+	if shader != nil {
+		panic("metal: user-defined shaders are not supported")
+	}
 	// TODO: Use address
 	if err := mainthread.Run(func() error {
 		// NSView can be changed anytime (probably). Set this everyframe.
@@ -525,13 +657,16 @@ func (d *Driver) Draw(indexLen int, indexOffset int, mode graphics.CompositeMode
 		if d.cb == (mtl.CommandBuffer{}) {
 			d.cb = d.cq.MakeCommandBuffer()
 		}
-		rce := d.cb.MakeRenderCommandEncoder(rpd)
-
-		if d.dst.screen {
-			rce.SetRenderPipelineState(d.screenRPS)
-		} else {
-			rce.SetRenderPipelineState(d.rpss[mode])
+		rps := d.screenRPS
+		if !d.dst.screen {
+			var err error
+			rps, err = d.renderPipelineState(mode, mask)
+			if err != nil {
+				return err
+			}
 		}
+		rce := d.cb.MakeRenderCommandEncoder(rpd)
+		rce.SetRenderPipelineState(rps)
 		rce.SetViewport(mtl.Viewport{0, 0, float64(w), float64(h), -1, 1})
 		rce.SetVertexBuffer(d.vb, 0, 0)
 
@@ -551,6 +686,9 @@ func (d *Driver) Draw(indexLen int, indexOffset int, mode graphics.CompositeMode
 		scale := float32(d.dst.width) / float32(d.src.width)
 		rce.SetFragmentBytes(unsafe.Pointer(&scale), unsafe.Sizeof(scale), 6)
 
+		linear := colorM.IsInLinearSpace()
+		rce.SetFragmentBytes(unsafe.Pointer(&linear), unsafe.Sizeof(linear), 7)
+
 		if d.src != nil {
 			rce.SetFragmentTexture(d.src.texture, 0)
 		} else {
@@ -674,3 +812,7 @@ func (i *Image) ReplacePixels(pixels []byte, x, y, width, height int) {
 		return nil
 	})
 }
+
+func (i *Image) BlitToDefaultFramebuffer(dstWidth, dstHeight int) error {
+	return fmt.Errorf("metal: BlitToDefaultFramebuffer is not supported")
+}
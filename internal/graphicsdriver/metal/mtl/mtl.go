@@ -284,6 +284,20 @@ const (
 	BlendFactorOneMinusSource1Alpha     BlendFactor = 18
 )
 
+// ColorWriteMask specifies which color channels are written by a render pipeline.
+//
+// Reference: https://developer.apple.com/documentation/metal/mtlcolorwritemask.
+type ColorWriteMask uint8
+
+const (
+	ColorWriteMaskNone  ColorWriteMask = 0x0
+	ColorWriteMaskRed   ColorWriteMask = 0x8
+	ColorWriteMaskGreen ColorWriteMask = 0x4
+	ColorWriteMaskBlue  ColorWriteMask = 0x2
+	ColorWriteMaskAlpha ColorWriteMask = 0x1
+	ColorWriteMaskAll   ColorWriteMask = 0xf
+)
+
 // Resource represents a memory allocation for storing specialized data
 // that is accessible to the GPU.
 //
@@ -321,6 +335,10 @@ type RenderPipelineColorAttachmentDescriptor struct {
 	DestinationRGBBlendFactor   BlendFactor
 	SourceAlphaBlendFactor      BlendFactor
 	SourceRGBBlendFactor        BlendFactor
+
+	// WriteMask specifies the color channels that are written.
+	// Note that the zero value means that no channel is written.
+	WriteMask ColorWriteMask
 }
 
 // RenderPassDescriptor describes a group of render targets that serve as
@@ -470,6 +488,7 @@ func (d Device) MakeRenderPipelineState(rpd RenderPipelineDescriptor) (RenderPip
 		ColorAttachment0DestinationRGBBlendFactor:   C.uint8_t(c.DestinationRGBBlendFactor),
 		ColorAttachment0SourceAlphaBlendFactor:      C.uint8_t(c.SourceAlphaBlendFactor),
 		ColorAttachment0SourceRGBBlendFactor:        C.uint8_t(c.SourceRGBBlendFactor),
+		ColorAttachment0WriteMask:                   C.uint8_t(c.WriteMask),
 	}
 	rps := C.Device_MakeRenderPipelineState(d.device, descriptor)
 	if rps.RenderPipelineState == nil {
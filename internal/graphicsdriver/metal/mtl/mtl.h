@@ -50,6 +50,7 @@ struct RenderPipelineDescriptor {
   uint8_t ColorAttachment0DestinationRGBBlendFactor;
   uint8_t ColorAttachment0SourceAlphaBlendFactor;
   uint8_t ColorAttachment0SourceRGBBlendFactor;
+  uint8_t ColorAttachment0WriteMask;
 };
 
 struct RenderPipelineState {
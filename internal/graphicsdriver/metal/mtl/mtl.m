@@ -95,6 +95,8 @@ Device_MakeRenderPipelineState(void *device,
       descriptor.ColorAttachment0SourceAlphaBlendFactor;
   renderPipelineDescriptor.colorAttachments[0].sourceRGBBlendFactor =
       descriptor.ColorAttachment0SourceRGBBlendFactor;
+  renderPipelineDescriptor.colorAttachments[0].writeMask =
+      descriptor.ColorAttachment0WriteMask;
   NSError *error;
   id<MTLRenderPipelineState> renderPipelineState = [(id<MTLDevice>)device
       newRenderPipelineStateWithDescriptor:renderPipelineDescriptor
@@ -32,11 +32,22 @@ func convertOperation(op graphics.Operation) operation {
 		return oneMinusSrcAlpha
 	case graphics.OneMinusDstAlpha:
 		return oneMinusDstAlpha
+	case graphics.SrcColor:
+		return srcColor
+	case graphics.DstColor:
+		return dstColor
+	case graphics.OneMinusSrcColor:
+		return oneMinusSrcColor
+	case graphics.OneMinusDstColor:
+		return oneMinusDstColor
 	default:
 		panic("not reached")
 	}
 }
 
+// colorMaskUnknown is an invalid color mask to force the next colorMask call to take effect.
+const colorMaskUnknown graphics.ColorMask = -1
+
 type context struct {
 	locationCache      *locationCache
 	screenFramebuffer  framebufferNative // This might not be the default frame buffer '0' (e.g. iOS).
@@ -45,6 +56,7 @@ type context struct {
 	lastViewportWidth  int
 	lastViewportHeight int
 	lastCompositeMode  graphics.CompositeMode
+	lastColorMask      graphics.ColorMask
 	maxTextureSize     int
 	contextImpl
 }
@@ -69,6 +69,10 @@ const (
 	dstAlpha         = operation(gl.DST_ALPHA)
 	oneMinusSrcAlpha = operation(gl.ONE_MINUS_SRC_ALPHA)
 	oneMinusDstAlpha = operation(gl.ONE_MINUS_DST_ALPHA)
+	srcColor         = operation(gl.SRC_COLOR)
+	dstColor         = operation(gl.DST_COLOR)
+	oneMinusSrcColor = operation(gl.ONE_MINUS_SRC_COLOR)
+	oneMinusDstColor = operation(gl.ONE_MINUS_DST_COLOR)
 )
 
 type contextImpl struct {
@@ -95,11 +99,13 @@ func (c *context) reset() error {
 	c.lastViewportWidth = 0
 	c.lastViewportHeight = 0
 	c.lastCompositeMode = graphics.CompositeModeUnknown
+	c.lastColorMask = colorMaskUnknown
 	_ = mainthread.Run(func() error {
 		gl.Enable(gl.BLEND)
 		return nil
 	})
 	c.blendFunc(graphics.CompositeModeSourceOver)
+	c.colorMask(graphics.ColorMaskNone)
 	_ = mainthread.Run(func() error {
 		f := int32(0)
 		gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &f)
@@ -122,6 +128,17 @@ func (c *context) blendFunc(mode graphics.CompositeMode) {
 	})
 }
 
+func (c *context) colorMask(mask graphics.ColorMask) {
+	_ = mainthread.Run(func() error {
+		if c.lastColorMask == mask {
+			return nil
+		}
+		c.lastColorMask = mask
+		gl.ColorMask(mask&graphics.ColorMaskRed == 0, mask&graphics.ColorMaskGreen == 0, mask&graphics.ColorMaskBlue == 0, mask&graphics.ColorMaskAlpha == 0)
+		return nil
+	})
+}
+
 func (c *context) newTexture(width, height int) (textureNative, error) {
 	var texture textureNative
 	if err := mainthread.Run(func() error {
@@ -327,7 +344,14 @@ func (c *context) newProgram(shaders []shader) (program, error) {
 		var v int32
 		gl.GetProgramiv(p, gl.LINK_STATUS, &v)
 		if v == gl.FALSE {
-			return errors.New("opengl: program error")
+			log := []byte{}
+			gl.GetProgramiv(p, gl.INFO_LOG_LENGTH, &v)
+			if v != 0 {
+				log = make([]byte, int(v))
+				gl.GetProgramInfoLog(p, v, nil, (*uint8)(gl.Ptr(log)))
+			}
+			gl.DeleteProgram(p)
+			return fmt.Errorf("opengl: program error: %s", log)
 		}
 		pr = program(p)
 		return nil
@@ -364,6 +388,19 @@ func (c *context) getUniformLocationImpl(p program, location string) uniformLoca
 	return uniform
 }
 
+// isUniformActive reports whether the uniform variable location is active in the program p.
+// A uniform variable that is declared but not used can be removed by the driver.
+func (c *context) isUniformActive(p program, location string) bool {
+	active := false
+	_ = mainthread.Run(func() error {
+		l, free := gl.Strs(location + "\x00")
+		active = gl.GetUniformLocation(uint32(p), *l) != -1
+		free()
+		return nil
+	})
+	return active
+}
+
 func (c *context) uniformInt(p program, location string, v int) {
 	_ = mainthread.Run(func() error {
 		l := int32(c.locationCache.GetUniformLocation(c, p, location))
@@ -386,6 +423,8 @@ func (c *context) uniformFloats(p program, location string, v []float32) {
 		switch len(v) {
 		case 2:
 			gl.Uniform2fv(l, 1, (*float32)(gl.Ptr(v)))
+		case 3:
+			gl.Uniform3fv(l, 1, (*float32)(gl.Ptr(v)))
 		case 4:
 			gl.Uniform4fv(l, 1, (*float32)(gl.Ptr(v)))
 		case 16:
@@ -493,6 +532,15 @@ func (c *context) drawElements(len int, offsetInBytes int) {
 	})
 }
 
+func (c *context) getError() int {
+	var e uint32
+	_ = mainthread.Run(func() error {
+		e = gl.GetError()
+		return nil
+	})
+	return int(e)
+}
+
 func (c *context) maxTextureSizeImpl() int {
 	size := 0
 	_ = mainthread.Run(func() error {
@@ -510,3 +558,36 @@ func (c *context) flush() {
 		return nil
 	})
 }
+
+func (c *context) finish() {
+	_ = mainthread.Run(func() error {
+		gl.Finish()
+		return nil
+	})
+}
+
+func (c *context) blitToDefaultFramebuffer(t textureNative, width, height, dstWidth, dstHeight int) error {
+	return mainthread.Run(func() error {
+		// Framebuffer objects are not shared among contexts. Create a temporary one in the current context.
+		// Note that the current context might not be the context that c's states are for. Don't update them.
+		var f uint32
+		gl.GenFramebuffersEXT(1, &f)
+		if f <= 0 {
+			return errors.New("opengl: creating framebuffer failed")
+		}
+		defer gl.DeleteFramebuffersEXT(1, &f)
+
+		gl.BindFramebufferEXT(gl.READ_FRAMEBUFFER_EXT, f)
+		gl.FramebufferTexture2DEXT(gl.READ_FRAMEBUFFER_EXT, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, uint32(t), 0)
+		if s := gl.CheckFramebufferStatusEXT(gl.READ_FRAMEBUFFER_EXT); s != gl.FRAMEBUFFER_COMPLETE {
+			gl.BindFramebufferEXT(gl.FRAMEBUFFER, 0)
+			return fmt.Errorf("opengl: glCheckFramebufferStatus failed: %d", s)
+		}
+		gl.BindFramebufferEXT(gl.DRAW_FRAMEBUFFER_EXT, 0)
+
+		// The texture's origin is upper-left while the default framebuffer's origin is lower-left.
+		gl.BlitFramebufferEXT(0, 0, int32(width), int32(height), 0, int32(dstHeight), int32(dstWidth), 0, gl.COLOR_BUFFER_BIT, gl.LINEAR)
+		gl.BindFramebufferEXT(gl.FRAMEBUFFER, 0)
+		return nil
+	})
+}
@@ -64,6 +64,10 @@ var (
 	dstAlpha         = operation(contextPrototype.Get("DST_ALPHA").Int())
 	oneMinusSrcAlpha = operation(contextPrototype.Get("ONE_MINUS_SRC_ALPHA").Int())
 	oneMinusDstAlpha = operation(contextPrototype.Get("ONE_MINUS_DST_ALPHA").Int())
+	srcColor         = operation(contextPrototype.Get("SRC_COLOR").Int())
+	dstColor         = operation(contextPrototype.Get("DST_COLOR").Int())
+	oneMinusSrcColor = operation(contextPrototype.Get("ONE_MINUS_SRC_COLOR").Int())
+	oneMinusDstColor = operation(contextPrototype.Get("ONE_MINUS_DST_COLOR").Int())
 
 	blend               = contextPrototype.Get("BLEND")
 	clampToEdge         = contextPrototype.Get("CLAMP_TO_EDGE")
@@ -129,11 +133,13 @@ func (c *context) reset() error {
 	c.lastViewportWidth = 0
 	c.lastViewportHeight = 0
 	c.lastCompositeMode = graphics.CompositeModeUnknown
+	c.lastColorMask = colorMaskUnknown
 
 	c.ensureGL()
 	gl := c.gl
 	gl.Call("enable", blend)
 	c.blendFunc(graphics.CompositeModeSourceOver)
+	c.colorMask(graphics.ColorMaskNone)
 	f := gl.Call("getParameter", framebufferBinding)
 	c.screenFramebuffer = framebufferNative(f)
 	return nil
@@ -151,6 +157,16 @@ func (c *context) blendFunc(mode graphics.CompositeMode) {
 	gl.Call("blendFunc", int(s2), int(d2))
 }
 
+func (c *context) colorMask(mask graphics.ColorMask) {
+	if c.lastColorMask == mask {
+		return
+	}
+	c.lastColorMask = mask
+	c.ensureGL()
+	gl := c.gl
+	gl.Call("colorMask", mask&graphics.ColorMaskRed == 0, mask&graphics.ColorMaskGreen == 0, mask&graphics.ColorMaskBlue == 0, mask&graphics.ColorMaskAlpha == 0)
+}
+
 func (c *context) newTexture(width, height int) (textureNative, error) {
 	c.ensureGL()
 	gl := c.gl
@@ -311,7 +327,9 @@ func (c *context) newProgram(shaders []shader) (program, error) {
 	}
 	gl.Call("linkProgram", v)
 	if !gl.Call("getProgramParameter", v, linkStatus).Bool() {
-		return program{}, errors.New("opengl: program error")
+		log := gl.Call("getProgramInfoLog", v).String()
+		gl.Call("deleteProgram", v)
+		return program{}, fmt.Errorf("opengl: program error: %s", log)
 	}
 
 	id := c.lastProgramID
@@ -343,6 +361,14 @@ func (c *context) getUniformLocationImpl(p program, location string) uniformLoca
 	return uniformLocation(gl.Call("getUniformLocation", p.value, location))
 }
 
+// isUniformActive reports whether the uniform variable location is active in the program p.
+// A uniform variable that is declared but not used can be removed by the driver.
+func (c *context) isUniformActive(p program, location string) bool {
+	c.ensureGL()
+	gl := c.gl
+	return gl.Call("getUniformLocation", p.value, location) != js.Null()
+}
+
 func (c *context) uniformInt(p program, location string, v int) {
 	c.ensureGL()
 	gl := c.gl
@@ -368,6 +394,8 @@ func (c *context) uniformFloats(p program, location string, v []float32) {
 	switch len(v) {
 	case 2:
 		gl.Call("uniform2f", js.Value(l), v[0], v[1])
+	case 3:
+		gl.Call("uniform3f", js.Value(l), v[0], v[1], v[2])
 	case 4:
 		gl.Call("uniform4f", js.Value(l), v[0], v[1], v[2], v[3])
 	case 16:
@@ -458,6 +486,12 @@ func (c *context) drawElements(len int, offsetInBytes int) {
 	gl.Call("drawElements", triangles, len, unsignedShort, offsetInBytes)
 }
 
+func (c *context) getError() int {
+	c.ensureGL()
+	gl := c.gl
+	return gl.Call("getError").Int()
+}
+
 func (c *context) maxTextureSizeImpl() int {
 	c.ensureGL()
 	gl := c.gl
@@ -470,6 +504,12 @@ func (c *context) flush() {
 	gl.Call("flush")
 }
 
+func (c *context) finish() {
+	c.ensureGL()
+	gl := c.gl
+	gl.Call("finish")
+}
+
 func (c *context) isContextLost() bool {
 	c.ensureGL()
 	gl := c.gl
@@ -481,3 +521,7 @@ func (c *context) restoreContext() {
 		c.loseContext.Call("restoreContext")
 	}
 }
+
+func (c *context) blitToDefaultFramebuffer(t textureNative, width, height, dstWidth, dstHeight int) error {
+	return errors.New("opengl: blitToDefaultFramebuffer is not supported on browsers")
+}
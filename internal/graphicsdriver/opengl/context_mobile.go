@@ -66,6 +66,10 @@ const (
 	dstAlpha         = operation(mgl.DST_ALPHA)
 	oneMinusSrcAlpha = operation(mgl.ONE_MINUS_SRC_ALPHA)
 	oneMinusDstAlpha = operation(mgl.ONE_MINUS_DST_ALPHA)
+	srcColor         = operation(mgl.SRC_COLOR)
+	dstColor         = operation(mgl.DST_COLOR)
+	oneMinusSrcColor = operation(mgl.ONE_MINUS_SRC_COLOR)
+	oneMinusDstColor = operation(mgl.ONE_MINUS_DST_COLOR)
 )
 
 type contextImpl struct {
@@ -100,8 +104,10 @@ func (c *context) reset() error {
 	c.lastViewportWidth = 0
 	c.lastViewportHeight = 0
 	c.lastCompositeMode = graphics.CompositeModeUnknown
+	c.lastColorMask = colorMaskUnknown
 	c.gl.Enable(mgl.BLEND)
 	c.blendFunc(graphics.CompositeModeSourceOver)
+	c.colorMask(graphics.ColorMaskNone)
 	f := c.gl.GetInteger(mgl.FRAMEBUFFER_BINDING)
 	c.screenFramebuffer = framebufferNative(mgl.Framebuffer{uint32(f)})
 	// TODO: Need to update screenFramebufferWidth/Height?
@@ -119,6 +125,15 @@ func (c *context) blendFunc(mode graphics.CompositeMode) {
 	gl.BlendFunc(mgl.Enum(s2), mgl.Enum(d2))
 }
 
+func (c *context) colorMask(mask graphics.ColorMask) {
+	gl := c.gl
+	if c.lastColorMask == mask {
+		return
+	}
+	c.lastColorMask = mask
+	gl.ColorMask(mask&graphics.ColorMaskRed == 0, mask&graphics.ColorMaskGreen == 0, mask&graphics.ColorMaskBlue == 0, mask&graphics.ColorMaskAlpha == 0)
+}
+
 func (c *context) newTexture(width, height int) (textureNative, error) {
 	gl := c.gl
 	t := gl.CreateTexture()
@@ -261,7 +276,9 @@ func (c *context) newProgram(shaders []shader) (program, error) {
 	gl.LinkProgram(p)
 	v := gl.GetProgrami(p, mgl.LINK_STATUS)
 	if v == mgl.FALSE {
-		return program{}, errors.New("opengl: program error")
+		log := gl.GetProgramInfoLog(p)
+		gl.DeleteProgram(p)
+		return program{}, fmt.Errorf("opengl: program error: %s", log)
 	}
 	return program(p), nil
 }
@@ -288,6 +305,13 @@ func (c *context) getUniformLocationImpl(p program, location string) uniformLoca
 	return u
 }
 
+// isUniformActive reports whether the uniform variable location is active in the program p.
+// A uniform variable that is declared but not used can be removed by the driver.
+func (c *context) isUniformActive(p program, location string) bool {
+	gl := c.gl
+	return gl.GetUniformLocation(mgl.Program(p), location).Value != -1
+}
+
 func (c *context) uniformInt(p program, location string, v int) {
 	gl := c.gl
 	gl.Uniform1i(mgl.Uniform(c.locationCache.GetUniformLocation(c, p, location)), v)
@@ -304,6 +328,8 @@ func (c *context) uniformFloats(p program, location string, v []float32) {
 	switch len(v) {
 	case 2:
 		gl.Uniform2fv(l, v)
+	case 3:
+		gl.Uniform3fv(l, v)
 	case 4:
 		gl.Uniform4fv(l, v)
 	case 16:
@@ -381,6 +407,11 @@ func (c *context) drawElements(len int, offsetInBytes int) {
 	gl.DrawElements(mgl.TRIANGLES, len, mgl.UNSIGNED_SHORT, offsetInBytes)
 }
 
+func (c *context) getError() int {
+	gl := c.gl
+	return int(gl.GetError())
+}
+
 func (c *context) maxTextureSizeImpl() int {
 	gl := c.gl
 	return gl.GetInteger(mgl.MAX_TEXTURE_SIZE)
@@ -390,3 +421,12 @@ func (c *context) flush() {
 	gl := c.gl
 	gl.Flush()
 }
+
+func (c *context) finish() {
+	gl := c.gl
+	gl.Finish()
+}
+
+func (c *context) blitToDefaultFramebuffer(t textureNative, width, height, dstWidth, dstHeight int) error {
+	return errors.New("opengl: blitToDefaultFramebuffer is not supported on mobiles")
+}
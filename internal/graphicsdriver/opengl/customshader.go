@@ -0,0 +1,158 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opengl
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/internal/graphics"
+	"github.com/hajimehoshi/ebiten/internal/graphicsdriver"
+)
+
+// Shader is a user-defined shader.
+//
+// The program of a shader is compiled by Compile, or lazily at the first draw call with the shader
+// if the OpenGL state is not initialized at Compile. The program is compiled again after the OpenGL
+// state is reset.
+type Shader struct {
+	driver   *Driver
+	source   string
+	uniforms map[string]int
+	program  program
+
+	// activeUniforms is the set of the uniform variables that are active in the program.
+	// A uniform variable that is declared but not used in shade can be removed by the driver,
+	// and such variables are skipped at setUniforms.
+	activeUniforms map[string]struct{}
+
+	// lastUniforms is the values of the uniform variables that are set to the program.
+	lastUniforms graphics.Uniforms
+}
+
+// NewShader creates a new shader whose fragment shader calls the user-defined function shade in src.
+func (d *Driver) NewShader(src string) (graphicsdriver.Shader, error) {
+	uniforms, err := parseCustomShader(src)
+	if err != nil {
+		return nil, err
+	}
+	return &Shader{
+		driver:   d,
+		source:   fragmentShaderStr(src),
+		uniforms: uniforms,
+	}, nil
+}
+
+// Compile compiles the program of the shader and returns the compiling or linking error with the driver's log.
+//
+// If the OpenGL state is not initialized yet, Compile does nothing and returns nil.
+func (s *Shader) Compile() error {
+	if !s.driver.state.initialized {
+		return nil
+	}
+	_, err := s.ensureProgram()
+	return err
+}
+
+// UniformSizes returns the numbers of float values of the declared uniform variables.
+func (s *Shader) UniformSizes() map[string]int {
+	return s.uniforms
+}
+
+// Dispose deletes the program of the shader.
+func (s *Shader) Dispose() {
+	if s.program == zeroProgram {
+		return
+	}
+	if s.driver.state.lastProgram == s.program {
+		s.driver.state.lastProgram = zeroProgram
+	}
+	s.driver.context.deleteProgram(s.program)
+	s.driver.context.locationCache.deleteProgram(s.program)
+	s.program = zeroProgram
+	delete(s.driver.state.shaders, s)
+}
+
+// ensureProgram compiles the program of the shader if needed, and returns the program.
+func (s *Shader) ensureProgram() (program, error) {
+	if s.program != zeroProgram {
+		return s.program, nil
+	}
+
+	c := &s.driver.context
+	vs, err := c.newShader(vertexShader, shaderStr(shaderVertexModelview))
+	if err != nil {
+		return zeroProgram, fmt.Errorf("opengl: shader compiling error:\n%s", err)
+	}
+	defer c.deleteShader(vs)
+
+	fs, err := c.newShader(fragmentShader, s.source)
+	if err != nil {
+		return zeroProgram, fmt.Errorf("opengl: shader compiling error:\n%s", err)
+	}
+	defer c.deleteShader(fs)
+
+	p, err := c.newProgram([]shader{vs, fs})
+	if err != nil {
+		return zeroProgram, err
+	}
+	s.program = p
+	s.activeUniforms = map[string]struct{}{}
+	for name := range s.uniforms {
+		if c.isUniformActive(p, name) {
+			s.activeUniforms[name] = struct{}{}
+		}
+	}
+	s.lastUniforms = graphics.Uniforms{}
+	s.driver.state.shaders[s] = struct{}{}
+	return p, nil
+}
+
+// setUniforms sets the uniform variables to the program.
+// The variables not in uniforms are set to 0. The variables that are not active in the program are skipped.
+//
+// As the uniform variables are a state of the program, the values that are same as the last values
+// are not set again.
+func (s *Shader) setUniforms(uniforms graphics.Uniforms) error {
+	for name, v := range uniforms {
+		size, ok := s.uniforms[name]
+		if !ok {
+			return fmt.Errorf("opengl: uniform %q is not declared in the shader", name)
+		}
+		if len(v) != size {
+			return fmt.Errorf("opengl: uniform %q is declared as %s but the given value is %s", name, uniformTypeName(size), uniformTypeName(len(v)))
+		}
+	}
+
+	for name, size := range s.uniforms {
+		if _, ok := s.activeUniforms[name]; !ok {
+			continue
+		}
+		v, ok := uniforms[name]
+		if !ok {
+			v = make([]float32, size)
+		}
+		if last, ok := s.lastUniforms[name]; ok && areSameFloat32Array(last, v) {
+			continue
+		}
+		if size == 1 {
+			s.driver.context.uniformFloat(s.program, name, v[0])
+		} else {
+			s.driver.context.uniformFloats(s.program, name, v)
+		}
+		// uniforms is immutable. It's OK to hold the reference without copying.
+		s.lastUniforms[name] = v
+	}
+	return nil
+}
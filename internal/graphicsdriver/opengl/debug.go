@@ -0,0 +1,55 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opengl
+
+import (
+	"fmt"
+	"log"
+)
+
+// checkError logs the OpenGL error after the operation name if exists.
+//
+// checkError does nothing unless the build tag 'ebitendebug' is specified.
+// glGetError is used instead of a debug callback of GL_KHR_debug, since the extension is not available
+// on all the environments, e.g., macOS and WebGL.
+func (c *context) checkError(name string) {
+	if !checkErrors() {
+		return
+	}
+	if e := c.getError(); e != 0 {
+		log.Printf("opengl: %s: %s", name, glErrorString(e))
+	}
+}
+
+// glErrorString returns a string representation of an OpenGL error value.
+func glErrorString(e int) string {
+	switch e {
+	case 0x0500:
+		return "GL_INVALID_ENUM"
+	case 0x0501:
+		return "GL_INVALID_VALUE"
+	case 0x0502:
+		return "GL_INVALID_OPERATION"
+	case 0x0503:
+		return "GL_STACK_OVERFLOW"
+	case 0x0504:
+		return "GL_STACK_UNDERFLOW"
+	case 0x0505:
+		return "GL_OUT_OF_MEMORY"
+	case 0x0506:
+		return "GL_INVALID_FRAMEBUFFER_OPERATION"
+	}
+	return fmt.Sprintf("unknown error (0x%04x)", e)
+}
@@ -0,0 +1,36 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opengl
+
+import (
+	"testing"
+)
+
+func TestGLErrorString(t *testing.T) {
+	cases := []struct {
+		Error int
+		Want  string
+	}{
+		{0x0500, "GL_INVALID_ENUM"},
+		{0x0502, "GL_INVALID_OPERATION"},
+		{0x0506, "GL_INVALID_FRAMEBUFFER_OPERATION"},
+		{0x1234, "unknown error (0x1234)"},
+	}
+	for _, c := range cases {
+		if got := glErrorString(c.Error); got != c.Want {
+			t.Errorf("glErrorString(0x%04x): got %q, want %q", c.Error, got, c.Want)
+		}
+	}
+}
@@ -66,6 +66,7 @@ func (d *Driver) NewImage(width, height int) (graphicsdriver.Image, error) {
 	if err != nil {
 		return nil, err
 	}
+	d.context.checkError("newTexture")
 	i.textureNative = t
 	return i, nil
 }
@@ -94,11 +95,17 @@ func (d *Driver) SetVertices(vertices []float32, indices []uint16) {
 	d.context.elementArrayBufferSubData(indices)
 }
 
-func (d *Driver) Draw(indexLen int, indexOffset int, mode graphics.CompositeMode, colorM *affine.ColorM, filter graphics.Filter, address graphics.Address) error {
-	if err := d.useProgram(mode, colorM, filter, address); err != nil {
+func (d *Driver) Draw(indexLen int, indexOffset int, mode graphics.CompositeMode, colorM *affine.ColorM, filter graphics.Filter, address graphics.Address, mask graphics.ColorMask, shader graphicsdriver.Shader, uniforms graphics.Uniforms) error {
+	var s *Shader
+	if shader != nil {
+		s = shader.(*Shader)
+	}
+	d.context.colorMask(mask)
+	if err := d.useProgram(mode, colorM, filter, address, s, uniforms); err != nil {
 		return err
 	}
 	d.context.drawElements(indexLen, indexOffset*2) // 2 is uint16 size in bytes
+	d.context.checkError("drawElements")
 	// glFlush() might be necessary at least on MacBook Pro (a smilar problem at #419),
 	// but basically this pass the tests (esp. TestImageTooManyFill).
 	// As glFlush() causes performance problems, this should be avoided as much as possible.
@@ -110,6 +117,10 @@ func (d *Driver) Flush() {
 	d.context.flush()
 }
 
+func (d *Driver) Finish() {
+	d.context.finish()
+}
+
 func (d *Driver) SetVsyncEnabled(enabled bool) {
 	// Do nothing
 }
@@ -37,6 +37,7 @@ func (i *Image) Dispose() {
 	}
 	if i.textureNative != *new(textureNative) {
 		i.driver.context.deleteTexture(i.textureNative)
+		i.driver.context.checkError("deleteTexture")
 	}
 }
 
@@ -95,8 +96,21 @@ func (i *Image) ReplacePixels(p []byte, x, y, width, height int) {
 	// glTexSubImage2D didn't work without this hack at least on Nexus 5x and NuAns NEO [Reloaded] (#211).
 	i.driver.context.flush()
 	i.driver.context.texSubImage2D(i.textureNative, p, x, y, width, height)
+	i.driver.context.checkError("texSubImage2D")
 }
 
 func (i *Image) SetAsSource() {
 	i.driver.state.source = i
 }
+
+// BlitToDefaultFramebuffer copies the image to the default framebuffer of the current context,
+// stretching it to dstWidth x dstHeight.
+//
+// The current context can be different from the context where the image is created,
+// as long as the contexts share their textures.
+func (i *Image) BlitToDefaultFramebuffer(dstWidth, dstHeight int) error {
+	if i.screen {
+		panic("opengl: the screen image cannot be a source of BlitToDefaultFramebuffer")
+	}
+	return i.driver.context.blitToDefaultFramebuffer(i.textureNative, i.width, i.height, dstWidth, dstHeight)
+}
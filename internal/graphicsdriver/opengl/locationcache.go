@@ -53,3 +53,12 @@ func (c *locationCache) GetAttribLocation(context *context, p program, location
 	}
 	return l
 }
+
+// deleteProgram removes the cached locations of the program p.
+//
+// deleteProgram must be called when p is deleted, since the ID of p can be reused by a new program.
+func (c *locationCache) deleteProgram(p program) {
+	id := getProgramID(p)
+	delete(c.uniformLocationCache, id)
+	delete(c.attribLocationCache, id)
+}
@@ -118,11 +118,19 @@ type openGLState struct {
 	// program is OpenGL's program for rendering a texture.
 	program program
 
+	// shaders is the set of user-defined shaders whose programs are compiled.
+	shaders map[*Shader]struct{}
+
+	// initialized indicates whether the OpenGL state is initialized by reset.
+	// Programs can be compiled only after the state is initialized.
+	initialized bool
+
 	lastProgram                program
 	lastViewportWidth          int
 	lastViewportHeight         int
 	lastColorMatrix            []float32
 	lastColorMatrixTranslation []float32
+	lastColorMatrixLinear      *bool
 	lastSourceWidth            int
 	lastSourceHeight           int
 	lastFilter                 *graphics.Filter
@@ -153,6 +161,7 @@ func (s *openGLState) reset(context *context) error {
 	s.lastViewportHeight = 0
 	s.lastColorMatrix = nil
 	s.lastColorMatrixTranslation = nil
+	s.lastColorMatrixLinear = nil
 	s.lastSourceWidth = 0
 	s.lastSourceHeight = 0
 	s.lastFilter = nil
@@ -164,6 +173,11 @@ func (s *openGLState) reset(context *context) error {
 	if s.program != zeroProgram {
 		context.deleteProgram(s.program)
 	}
+	for shader := range s.shaders {
+		context.deleteProgram(shader.program)
+		shader.program = zeroProgram
+	}
+	s.shaders = map[*Shader]struct{}{}
 
 	// On browsers (at least Chrome), buffers are already detached from the context
 	// and must not be deleted by DeleteBuffer.
@@ -203,6 +217,7 @@ func (s *openGLState) reset(context *context) error {
 	// See NewElementArrayBuffer in context_mobile.go.
 	s.elementArrayBuffer = context.newElementArrayBuffer(graphics.IndicesNum * 2)
 
+	s.initialized = true
 	return nil
 }
 
@@ -219,8 +234,16 @@ func areSameFloat32Array(a, b []float32) bool {
 	return true
 }
 
-// useProgram uses the program (programTexture).
-func (d *Driver) useProgram(mode graphics.CompositeMode, colorM *affine.ColorM, filter graphics.Filter, address graphics.Address) error {
+// screenFilterScale returns the scale for the screen filter, which renders the source image
+// with the width srcWidth to the destination with the width dstWidth.
+//
+// The scale is calculated for each draw, so a change of the screen scale is reflected immediately.
+func screenFilterScale(dstWidth, srcWidth int) float32 {
+	return float32(dstWidth) / float32(srcWidth)
+}
+
+// useProgram uses the program (programTexture), or the program of shader if shader is not nil.
+func (d *Driver) useProgram(mode graphics.CompositeMode, colorM *affine.ColorM, filter graphics.Filter, address graphics.Address, shader *Shader, uniforms graphics.Uniforms) error {
 	destination := d.state.destination
 	if destination == nil {
 		panic("destination image is not set")
@@ -239,6 +262,13 @@ func (d *Driver) useProgram(mode graphics.CompositeMode, colorM *affine.ColorM,
 	d.context.blendFunc(mode)
 
 	program := d.state.program
+	if shader != nil {
+		p, err := shader.ensureProgram()
+		if err != nil {
+			return err
+		}
+		program = p
+	}
 	if d.state.lastProgram != program {
 		d.context.useProgram(program)
 		if d.state.lastProgram != zeroProgram {
@@ -249,16 +279,19 @@ func (d *Driver) useProgram(mode graphics.CompositeMode, colorM *affine.ColorM,
 		if d.state.lastProgram == zeroProgram {
 			d.context.bindBuffer(arrayBuffer, d.state.arrayBuffer)
 			d.context.bindBuffer(elementArrayBuffer, d.state.elementArrayBuffer)
-			d.context.uniformInt(program, "texture", 0)
 		}
+		d.context.uniformInt(program, "texture", 0)
 
 		d.state.lastProgram = program
 		d.state.lastViewportWidth = 0
 		d.state.lastViewportHeight = 0
 		d.state.lastColorMatrix = nil
 		d.state.lastColorMatrixTranslation = nil
+		d.state.lastColorMatrixLinear = nil
 		d.state.lastSourceWidth = 0
 		d.state.lastSourceHeight = 0
+		d.state.lastFilter = nil
+		d.state.lastAddress = nil
 	}
 
 	vw := destination.framebuffer.width
@@ -281,6 +314,14 @@ func (d *Driver) useProgram(mode graphics.CompositeMode, colorM *affine.ColorM,
 		// ColorM's elements are immutable. It's OK to hold the reference without copying.
 		d.state.lastColorMatrixTranslation = esTranslate
 	}
+	if linear := colorM.IsInLinearSpace(); d.state.lastColorMatrixLinear == nil || *d.state.lastColorMatrixLinear != linear {
+		v := 0
+		if linear {
+			v = 1
+		}
+		d.context.uniformInt(program, "color_matrix_linear", v)
+		d.state.lastColorMatrixLinear = &linear
+	}
 
 	sw := graphics.NextPowerOf2Int(srcW)
 	sh := graphics.NextPowerOf2Int(srcH)
@@ -301,8 +342,13 @@ func (d *Driver) useProgram(mode graphics.CompositeMode, colorM *affine.ColorM,
 	}
 
 	if filter == graphics.FilterScreen {
-		scale := float32(dstW) / float32(srcW)
-		d.context.uniformFloat(program, "scale", scale)
+		d.context.uniformFloat(program, "scale", screenFilterScale(dstW, srcW))
+	}
+
+	if shader != nil {
+		if err := shader.setUniforms(uniforms); err != nil {
+			return err
+		}
 	}
 
 	// We don't have to call gl.ActiveTexture here: GL_TEXTURE0 is the default active texture
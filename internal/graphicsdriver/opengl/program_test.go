@@ -0,0 +1,39 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opengl
+
+import (
+	"testing"
+)
+
+func TestScreenFilterScale(t *testing.T) {
+	cases := []struct {
+		DstWidth int
+		SrcWidth int
+		Want     float32
+	}{
+		{320, 320, 1},
+		{480, 320, 1.5},
+		{960, 320, 3},
+		// The screen scale is changed at runtime.
+		{640, 320, 2},
+		{400, 320, 1.25},
+	}
+	for _, c := range cases {
+		if got := screenFilterScale(c.DstWidth, c.SrcWidth); got != c.Want {
+			t.Errorf("screenFilterScale(%d, %d): got: %v, want: %v", c.DstWidth, c.SrcWidth, got, c.Want)
+		}
+	}
+}
@@ -49,17 +49,123 @@ var glslReservedKeywords = map[string]struct{}{
 
 var glslIdentifier = regexp.MustCompile(`[_a-zA-Z][_a-zA-Z0-9]*`)
 
-func checkGLSL(src string) {
-	for _, l := range strings.Split(src, "\n") {
-		if strings.Contains(l, "//") {
-			l = l[:strings.Index(l, "//")]
+// validateGLSL returns an error if src uses a reserved keyword as an identifier.
+// The error message has the keyword, and the line number and the byte column (both 1-based)
+// where the keyword is found.
+//
+// Comments, quoted strings, preprocessor directives and identifiers after '.' are not checked.
+func validateGLSL(src string) error {
+	inComment := false
+	for i, l := range strings.Split(src, "\n") {
+		directive := !inComment && strings.HasPrefix(strings.TrimSpace(l), "#")
+		l, inComment = stripGLSLCommentsAndStrings(l, inComment)
+		if directive {
+			continue
 		}
-		for _, token := range glslIdentifier.FindAllString(l, -1) {
+		for _, idx := range glslIdentifier.FindAllStringIndex(l, -1) {
+			// A member access or a swizzle like s.filter is not a use of a reserved keyword.
+			if strings.HasSuffix(strings.TrimRight(l[:idx[0]], " \t"), ".") {
+				continue
+			}
+			token := l[idx[0]:idx[1]]
 			if _, ok := glslReservedKeywords[token]; ok {
-				panic(fmt.Sprintf("opengl: %q is a reserved keyword", token))
+				return fmt.Errorf("opengl: %q is a reserved keyword at line %d, column %d", token, i+1, idx[0]+1)
 			}
 		}
 	}
+	return nil
+}
+
+// stripGLSLCommentsAndStrings returns the line l with the comments and the quoted strings replaced
+// with spaces. The byte columns of the other parts are kept.
+//
+// inComment indicates whether l starts in a block comment. stripGLSLCommentsAndStrings also returns
+// whether the next line starts in a block comment. A block comment without the closing '*/' continues
+// until the end of the source.
+func stripGLSLCommentsAndStrings(l string, inComment bool) (string, bool) {
+	b := []byte(l)
+	inString := false
+	for i := 0; i < len(b); i++ {
+		switch {
+		case inComment:
+			if b[i] == '*' && i+1 < len(b) && b[i+1] == '/' {
+				inComment = false
+				b[i+1] = ' '
+			}
+			b[i] = ' '
+			if !inComment {
+				i++
+			}
+		case inString:
+			if b[i] == '"' {
+				inString = false
+			}
+			b[i] = ' '
+		case b[i] == '"':
+			inString = true
+			b[i] = ' '
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '*':
+			inComment = true
+			b[i] = ' '
+			b[i+1] = ' '
+			i++
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '/':
+			return string(b[:i]), false
+		}
+	}
+	return string(b), inComment
+}
+
+// checkGLSL is same as validateGLSL except that checkGLSL panics instead of returning an error.
+// checkGLSL is used for the built-in shaders.
+func checkGLSL(src string) {
+	if err := validateGLSL(src); err != nil {
+		panic(err.Error())
+	}
+}
+
+var (
+	glslShadeFunc = regexp.MustCompile(`\bvec4\s+shade\s*\(\s*vec4\s+[_a-zA-Z][_a-zA-Z0-9]*\s*\)`)
+	glslUniform   = regexp.MustCompile(`\buniform\s+(?:(?:lowp|mediump|highp)\s+)?([_a-zA-Z][_a-zA-Z0-9]*)\s+([_a-zA-Z][_a-zA-Z0-9]*)\s*;`)
+)
+
+// uniformTypeSizes is the numbers of float values of the supported uniform types.
+var uniformTypeSizes = map[string]int{
+	"float": 1,
+	"vec2":  2,
+	"vec3":  3,
+	"vec4":  4,
+	"mat4":  16,
+}
+
+// uniformTypeName returns the name of the uniform type whose number of float values is size.
+func uniformTypeName(size int) string {
+	for name, s := range uniformTypeSizes {
+		if s == size {
+			return name
+		}
+	}
+	return fmt.Sprintf("(%d floats)", size)
+}
+
+// parseCustomShader validates a user-defined shader source and returns the numbers of float values
+// of the uniform variables.
+func parseCustomShader(src string) (map[string]int, error) {
+	if err := validateGLSL(src); err != nil {
+		return nil, err
+	}
+	if !glslShadeFunc.MatchString(src) {
+		return nil, fmt.Errorf("opengl: a shader must define a function vec4 shade(vec4 color)")
+	}
+	uniforms := map[string]int{}
+	for _, m := range glslUniform.FindAllStringSubmatch(src, -1) {
+		size, ok := uniformTypeSizes[m[1]]
+		if !ok {
+			return nil, fmt.Errorf("opengl: the type of uniform %q must be float, vec2, vec3, vec4 or mat4 but %s", m[2], m[1])
+		}
+		uniforms[m[2]] = size
+	}
+	return uniforms, nil
 }
 
 func shaderStr(id shaderID) string {
@@ -68,17 +174,7 @@ func shaderStr(id shaderID) string {
 	case shaderVertexModelview:
 		src = shaderStrVertex
 	case shaderFragmentColorMatrix:
-		replaces := map[string]string{
-			"{{.FilterNearest}}":      fmt.Sprintf("%d", graphics.FilterNearest),
-			"{{.FilterLinear}}":       fmt.Sprintf("%d", graphics.FilterLinear),
-			"{{.FilterScreen}}":       fmt.Sprintf("%d", graphics.FilterScreen),
-			"{{.AddressClampToZero}}": fmt.Sprintf("%d", graphics.AddressClampToZero),
-			"{{.AddressRepeat}}":      fmt.Sprintf("%d", graphics.AddressRepeat),
-		}
-		src = shaderStrFragment
-		for k, v := range replaces {
-			src = strings.Replace(src, k, v, -1)
-		}
+		src = fragmentShaderStr("")
 	default:
 		panic("not reached")
 	}
@@ -87,6 +183,29 @@ func shaderStr(id shaderID) string {
 	return src
 }
 
+// fragmentShaderStr returns the fragment shader source.
+// If custom is not empty, the user-defined shade function in custom is called at the end.
+func fragmentShaderStr(custom string) string {
+	if custom != "" {
+		custom = "#define CUSTOM_SHADER\n" + custom
+	}
+	replaces := map[string]string{
+		"{{.FilterNearest}}":         fmt.Sprintf("%d", graphics.FilterNearest),
+		"{{.FilterLinear}}":          fmt.Sprintf("%d", graphics.FilterLinear),
+		"{{.FilterScreen}}":          fmt.Sprintf("%d", graphics.FilterScreen),
+		"{{.FilterBicubic}}":         fmt.Sprintf("%d", graphics.FilterBicubic),
+		"{{.AddressClampToZero}}":    fmt.Sprintf("%d", graphics.AddressClampToZero),
+		"{{.AddressRepeat}}":         fmt.Sprintf("%d", graphics.AddressRepeat),
+		"{{.AddressMirroredRepeat}}": fmt.Sprintf("%d", graphics.AddressMirroredRepeat),
+		"{{.CustomShader}}":          custom,
+	}
+	src := shaderStrFragment
+	for k, v := range replaces {
+		src = strings.Replace(src, k, v, -1)
+	}
+	return src
+}
+
 const (
 	shaderStrVertex = `
 uniform vec2 viewport_size;
@@ -124,12 +243,15 @@ precision mediump float;
 #define FILTER_NEAREST ({{.FilterNearest}})
 #define FILTER_LINEAR ({{.FilterLinear}})
 #define FILTER_SCREEN ({{.FilterScreen}})
+#define FILTER_BICUBIC ({{.FilterBicubic}})
 #define ADDRESS_CLAMP_TO_ZERO ({{.AddressClampToZero}})
 #define ADDRESS_REPEAT ({{.AddressRepeat}})
+#define ADDRESS_MIRRORED_REPEAT ({{.AddressMirroredRepeat}})
 
 uniform sampler2D texture;
 uniform mat4 color_matrix_body;
 uniform vec4 color_matrix_translation;
+uniform bool color_matrix_linear;
 
 uniform int filter_type;
 uniform highp vec2 source_size;
@@ -173,10 +295,44 @@ highp vec2 adjustTexelByAddress(highp vec2 p, highp vec4 tex_region, int address
     highp vec2 size = vec2(tex_region[2] - tex_region[0], tex_region[3] - tex_region[1]);
     return vec2(floorMod((p.x - o.x), size.x) + o.x, floorMod((p.y - o.y), size.y) + o.y);
   }
+  if (address == ADDRESS_MIRRORED_REPEAT) {
+    highp vec2 o = vec2(tex_region[0], tex_region[1]);
+    highp vec2 size = vec2(tex_region[2] - tex_region[0], tex_region[3] - tex_region[1]);
+    highp vec2 t = vec2(floorMod((p.x - o.x), 2.0 * size.x), floorMod((p.y - o.y), 2.0 * size.y));
+    // At the mirror boundary (t == size), the result is the region's max edge, which is out of the region.
+    // Clamp it a little inner than the region check's threshold.
+    highp vec2 texel_size = 1.0 / source_size;
+    highp vec2 region_max = vec2(tex_region[2], tex_region[3]) - texel_size / 256.0;
+    return min(size - abs(t - size) + o, region_max);
+  }
   // Not reached.
   return vec2(0.0);
 }
 
+// sourceTexel returns the texel at p, or a transparent color if p is out of the source region.
+vec4 sourceTexel(highp vec2 p) {
+  highp vec2 texel_size = 1.0 / source_size;
+  p = adjustTexelByAddress(p, varying_tex_region, address);
+  if (p.x < varying_tex_region[0] ||
+    p.y < varying_tex_region[1] ||
+    (varying_tex_region[2] - texel_size.x / 512.0) <= p.x ||
+    (varying_tex_region[3] - texel_size.y / 512.0) <= p.y) {
+    return vec4(0, 0, 0, 0);
+  }
+  return texture2D(texture, p);
+}
+
+// catmullRomWeights returns the weights of the four texels for the fractional position f.
+vec4 catmullRomWeights(highp float f) {
+  return vec4(
+    f * (-0.5 + f * (1.0 - 0.5 * f)),
+    1.0 + f * f * (-2.5 + 1.5 * f),
+    f * (0.5 + f * (2.0 - 1.5 * f)),
+    f * f * (-0.5 + 0.5 * f));
+}
+
+{{.CustomShader}}
+
 void main(void) {
   highp vec2 pos = varying_tex;
   highp vec2 texel_size = 1.0 / source_size;
@@ -238,6 +394,23 @@ void main(void) {
     vec2 rateCenter = vec2(1.0, 1.0) - texel_size / 2.0 / scale;
     vec2 rate = clamp(((fract(p0 * source_size) - rateCenter) * scale) + rateCenter, 0.0, 1.0);
     color = mix(mix(c0, c1, rate.x), mix(c2, c3, rate.x), rate.y);
+  } else if (filter_type == FILTER_BICUBIC) {
+    highp vec2 q = pos * source_size - 0.5;
+    highp vec2 p = (floor(q) + 0.5) * texel_size;
+    vec4 wx = catmullRomWeights(fract(q.x));
+    vec4 wy = catmullRomWeights(fract(q.y));
+
+    color = vec4(0, 0, 0, 0);
+    for (int j = 0; j < 4; j++) {
+      vec4 row = vec4(0, 0, 0, 0);
+      for (int i = 0; i < 4; i++) {
+        row += wx[i] * sourceTexel(p + vec2(float(i - 1), float(j - 1)) * texel_size);
+      }
+      color += wy[j] * row;
+    }
+    // Catmull-Rom can overshoot. Keep the color a valid premultiplied-alpha color.
+    color = clamp(color, 0.0, 1.0);
+    color.rgb = min(color.rgb, vec3(color.a));
   } else {
     // Not reached.
     discard;
@@ -247,10 +420,22 @@ void main(void) {
   if (0.0 < color.a) {
     color.rgb /= color.a;
   }
+  // Convert the color to the linear space if needed.
+  if (color_matrix_linear) {
+    color.rgb = pow(color.rgb, vec3(2.2));
+  }
   // Apply the color matrix or scale.
   color = (color_matrix_body * color) + color_matrix_translation;
   color *= varying_color_scale;
   color = clamp(color, 0.0, 1.0);
+  // Convert the color back to the sRGB space.
+  if (color_matrix_linear) {
+    color.rgb = pow(color.rgb, vec3(1.0 / 2.2));
+  }
+#if defined(CUSTOM_SHADER)
+  // Apply the user-defined shader.
+  color = clamp(shade(color), 0.0, 1.0);
+#endif
   // Premultiply alpha
   color.rgb *= color.a;
 
@@ -0,0 +1,243 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opengl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateGLSL(t *testing.T) {
+	cases := []struct {
+		Src string
+		Err string
+	}{
+		{
+			Src: `uniform float rate;
+
+vec4 shade(vec4 color) {
+  // sampler3DRect in a comment is fine.
+  sampler3DRect s;
+  return color;
+}
+`,
+			Err: `"sampler3DRect" is a reserved keyword at line 5, column 3`,
+		},
+		{
+			Src: `vec4 shade(vec4 color) {
+  vec4 c = color; // filter
+  float x = 1.0; float filter = 2.0;
+  return c;
+}`,
+			Err: `"filter" is a reserved keyword at line 3, column 24`,
+		},
+		{
+			Src: `#define USE_FILTER
+#if defined(filter)
+#endif
+vec4 shade(vec4 color) {
+  return color;
+}`,
+		},
+		{
+			Src: `#pragma debug(on)
+#error "filter is not allowed"
+vec4 shade(vec4 color) {
+  // "filter"
+  return color;
+}`,
+		},
+		{
+			Src: `vec4 shade(vec4 color) { return color; }
+#line 1 "filter.glsl"
+float cast(float x) { return x; }`,
+			Err: `"cast" is a reserved keyword at line 3, column 7`,
+		},
+		{
+			Src: `/* filter */
+vec4 shade(vec4 color) {
+  /*
+   * The filter and the cast are
+   * reserved keywords.
+   */
+  return color;
+}`,
+		},
+		{
+			// Nested comments are not supported in GLSL. The first */ closes the comment.
+			Src: `vec4 shade(vec4 color) {
+  /* /* comment */ float filter = 1.0; */
+  return color;
+}`,
+			Err: `"filter" is a reserved keyword at line 2, column 26`,
+		},
+		{
+			// A // inside a block comment doesn't hide the end of the block comment.
+			Src: `vec4 shade(vec4 color) {
+  /* comment // comment */ float cast = 1.0;
+  return color;
+}`,
+			Err: `"cast" is a reserved keyword at line 2, column 34`,
+		},
+		{
+			// A /* without */ continues until the end.
+			Src: `vec4 shade(vec4 color) {
+  return color;
+}
+/* filter
+cast`,
+		},
+		{
+			// A # in a block comment is not a directive.
+			Src: `vec4 shade(vec4 color) {
+  return color; /*
+#define */ float filter;
+}`,
+			Err: `"filter" is a reserved keyword at line 3, column 18`,
+		},
+		{
+			// Member accesses are not reserved keyword uses.
+			Src: `vec4 shade(vec4 color) {
+  Light l = lights.output;
+  float x = l.filter + l . input;
+  return color;
+}`,
+		},
+		{
+			Src: `vec4 shade(vec4 color) {
+  float x = s.filter;
+  filter;
+  return color;
+}`,
+			Err: `"filter" is a reserved keyword at line 3, column 3`,
+		},
+	}
+	for _, c := range cases {
+		err := validateGLSL(c.Src)
+		if c.Err == "" {
+			if err != nil {
+				t.Errorf("validateGLSL(%q): %v", c.Src, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), c.Err) {
+			t.Errorf("validateGLSL(%q): got error: %v, want error containing %q", c.Src, err, c.Err)
+		}
+	}
+
+	if err := validateGLSL(shaderStr(shaderFragmentColorMatrix)); err != nil {
+		t.Errorf("the built-in shader must be valid: %v", err)
+	}
+}
+
+func TestStripGLSLCommentsAndStrings(t *testing.T) {
+	cases := []struct {
+		In           string
+		InComment    bool
+		Out          string
+		OutInComment bool
+	}{
+		{
+			In:  "float x; // comment",
+			Out: "float x; ",
+		},
+		{
+			In:  `a "b // c" d // e`,
+			Out: `a          d `,
+		},
+		{
+			In:  `a "b`,
+			Out: `a   `,
+		},
+		{
+			In:  "a /* b */ c",
+			Out: "a         c",
+		},
+		{
+			In:           "a /* b",
+			Out:          "a     ",
+			OutInComment: true,
+		},
+		{
+			In:        "b */ c",
+			InComment: true,
+			Out:       "     c",
+		},
+		{
+			In:           "b // c",
+			InComment:    true,
+			Out:          "      ",
+			OutInComment: true,
+		},
+		{
+			In:  "a /*/ b */ c",
+			Out: "a          c",
+		},
+	}
+	for _, c := range cases {
+		got, gotInComment := stripGLSLCommentsAndStrings(c.In, c.InComment)
+		if got != c.Out || gotInComment != c.OutInComment {
+			t.Errorf("stripGLSLCommentsAndStrings(%q, %t): got: %q, %t, want: %q, %t", c.In, c.InComment, got, gotInComment, c.Out, c.OutInComment)
+		}
+	}
+}
+
+func TestParseCustomShader(t *testing.T) {
+	cases := []struct {
+		Src      string
+		Uniforms map[string]int
+		Err      string
+	}{
+		{
+			Src:      "uniform float a;\nuniform highp vec2 b;\nuniform mat4 c;\nvec4 shade(vec4 color) { return color; }",
+			Uniforms: map[string]int{"a": 1, "b": 2, "c": 16},
+		},
+		{
+			Src: "vec4 foo(vec4 color) { return color; }",
+			Err: "shade",
+		},
+		{
+			Src: "uniform ivec2 a;\nvec4 shade(vec4 color) { return color; }",
+			Err: `"a"`,
+		},
+		{
+			Src: "vec4 shade(vec4 color) {\n  sampler3DRect s;\n  return color;\n}",
+			Err: `"sampler3DRect" is a reserved keyword at line 2, column 3`,
+		},
+	}
+	for _, c := range cases {
+		got, err := parseCustomShader(c.Src)
+		if c.Err != "" {
+			if err == nil || !strings.Contains(err.Error(), c.Err) {
+				t.Errorf("parseCustomShader(%q): got error: %v, want error containing %q", c.Src, err, c.Err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseCustomShader(%q): %v", c.Src, err)
+			continue
+		}
+		if len(got) != len(c.Uniforms) {
+			t.Errorf("parseCustomShader(%q): got: %v, want: %v", c.Src, got, c.Uniforms)
+			continue
+		}
+		for k, v := range c.Uniforms {
+			if got[k] != v {
+				t.Errorf("parseCustomShader(%q): got: %v, want: %v", c.Src, got, c.Uniforms)
+				break
+			}
+		}
+	}
+}
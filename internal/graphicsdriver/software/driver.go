@@ -0,0 +1,161 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package software provides a graphics driver that renders on the CPU without any graphics API.
+//
+// The driver is slow, and is intended for environments without GPUs like headless CI, e.g.,
+// to compare rendering results in tests. The rendering follows the OpenGL driver's shaders:
+// the filters, the address modes, the color matrices, the composite modes and the color masks
+// are supported, though the results can differ by a small rounding error.
+// FilterScreen and FilterBicubic are approximated by FilterLinear.
+// User-defined shaders are not supported.
+//
+// The driver is selected by ebiten.SetGraphicsDriver("software"). The rendering results are not presented on
+// the window so far. Tests can also use the driver directly as a graphicsdriver.GraphicsDriver.
+package software
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/internal/affine"
+	"github.com/hajimehoshi/ebiten/internal/graphics"
+	"github.com/hajimehoshi/ebiten/internal/graphicsdriver"
+)
+
+var theDriver Driver
+
+func Get() *Driver {
+	return &theDriver
+}
+
+type Driver struct {
+	vertices []float32
+	indices  []uint16
+
+	source      *Image
+	destination *Image
+}
+
+func (d *Driver) SetWindow(window uintptr) {
+	// Do nothing.
+}
+
+func (d *Driver) SetVertices(vertices []float32, indices []uint16) {
+	d.vertices = make([]float32, len(vertices))
+	copy(d.vertices, vertices)
+	d.indices = make([]uint16, len(indices))
+	copy(d.indices, indices)
+}
+
+func (d *Driver) Flush() {
+	// Do nothing.
+}
+
+func (d *Driver) Finish() {
+	// Do nothing.
+}
+
+func checkSize(width, height int) {
+	if width < 1 {
+		panic(fmt.Sprintf("software: width (%d) must be equal or more than 1", width))
+	}
+	if height < 1 {
+		panic(fmt.Sprintf("software: height (%d) must be equal or more than 1", height))
+	}
+}
+
+func (d *Driver) NewImage(width, height int) (graphicsdriver.Image, error) {
+	checkSize(width, height)
+	// Allocate a power-of-two size as the texture coordinates are normalized by the power-of-two size.
+	w := graphics.NextPowerOf2Int(width)
+	h := graphics.NextPowerOf2Int(height)
+	return &Image{
+		driver:       d,
+		width:        width,
+		height:       height,
+		bufferWidth:  w,
+		bufferHeight: h,
+		pixels:       make([]byte, 4*w*h),
+	}, nil
+}
+
+func (d *Driver) NewScreenFramebufferImage(width, height int) (graphicsdriver.Image, error) {
+	checkSize(width, height)
+	return &Image{
+		driver:       d,
+		width:        width,
+		height:       height,
+		bufferWidth:  width,
+		bufferHeight: height,
+		pixels:       make([]byte, 4*width*height),
+		screen:       true,
+	}, nil
+}
+
+func (d *Driver) NewShader(src string) (graphicsdriver.Shader, error) {
+	return nil, errors.New("software: user-defined shaders are not supported")
+}
+
+func (d *Driver) Reset() error {
+	return nil
+}
+
+func (d *Driver) Draw(indexLen int, indexOffset int, mode graphics.CompositeMode, colorM *affine.ColorM, filter graphics.Filter, address graphics.Address, mask graphics.ColorMask, shader graphicsdriver.Shader, uniforms graphics.Uniforms) error {
+	if shader != nil {
+		return errors.New("software: user-defined shaders are not supported")
+	}
+	if d.source == nil {
+		return errors.New("software: the source image is not set")
+	}
+	if d.destination == nil {
+		return errors.New("software: the destination image is not set")
+	}
+	if d.source == d.destination {
+		panic("software: the source and the destination images must be different")
+	}
+
+	r := &renderer{
+		src:     d.source,
+		dst:     d.destination,
+		colorM:  colorM,
+		filter:  filter,
+		address: address,
+		mask:    mask,
+	}
+	r.srcOp, r.dstOp = mode.Operations()
+	is := d.indices[indexOffset : indexOffset+indexLen]
+	for i := 0; i+2 < len(is); i += 3 {
+		r.drawTriangle(d.vertex(is[i]), d.vertex(is[i+1]), d.vertex(is[i+2]))
+	}
+	return nil
+}
+
+// vertex returns the vertex values at the index i.
+func (d *Driver) vertex(i uint16) []float32 {
+	n := graphics.VertexFloatNum
+	return d.vertices[int(i)*n : (int(i)+1)*n]
+}
+
+func (d *Driver) SetVsyncEnabled(enabled bool) {
+	// Do nothing.
+}
+
+func (d *Driver) VDirection() graphicsdriver.VDirection {
+	return graphicsdriver.VUpward
+}
+
+func (d *Driver) IsGL() bool {
+	return false
+}
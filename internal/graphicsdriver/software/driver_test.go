@@ -0,0 +1,359 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package software_test
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/internal/affine"
+	"github.com/hajimehoshi/ebiten/internal/graphics"
+	"github.com/hajimehoshi/ebiten/internal/graphicsdriver"
+	. "github.com/hajimehoshi/ebiten/internal/graphicsdriver/software"
+)
+
+// draw draws the whole src (w x h) to dst at (x, y) with the given options.
+func draw(t *testing.T, d graphicsdriver.GraphicsDriver, dst, src graphicsdriver.Image, w, h int, x, y float32, scale float32, colorM *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, mask graphics.ColorMask) {
+	vs := graphics.QuadVertices(graphics.NextPowerOf2Int(w), graphics.NextPowerOf2Int(h), 0, 0, w, h, scale, 0, 0, scale, x, y, 1, 1, 1, 1)
+	is := graphics.QuadIndices()
+	d.SetVertices(vs, is)
+	dst.SetAsDestination()
+	src.SetAsSource()
+	if err := d.Draw(len(is), 0, mode, colorM, filter, graphics.AddressClampToZero, mask, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newImage(t *testing.T, d graphicsdriver.GraphicsDriver, w, h int, pix []byte) graphicsdriver.Image {
+	img, err := d.NewImage(w, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pix != nil {
+		img.ReplacePixels(pix, 0, 0, w, h)
+	}
+	return img
+}
+
+func pixels(t *testing.T, img graphicsdriver.Image) []byte {
+	p, err := img.Pixels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func fill(w, h int, r, g, b, a byte) []byte {
+	p := make([]byte, 4*w*h)
+	for i := 0; i < w*h; i++ {
+		p[4*i] = r
+		p[4*i+1] = g
+		p[4*i+2] = b
+		p[4*i+3] = a
+	}
+	return p
+}
+
+func TestDrawNearest(t *testing.T) {
+	var d graphicsdriver.GraphicsDriver = Get()
+
+	src := newImage(t, d, 2, 2, []byte{
+		0xff, 0, 0, 0xff, 0, 0xff, 0, 0xff,
+		0, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	})
+	dst := newImage(t, d, 5, 5, nil)
+
+	// Draw the source scaled by 2 at (1, 1).
+	draw(t, d, dst, src, 2, 2, 1, 1, 2, nil, graphics.CompositeModeCopy, graphics.FilterNearest, graphics.ColorMaskNone)
+
+	got := pixels(t, dst)
+	colors := map[[2]int][4]byte{
+		{0, 0}: {0xff, 0, 0, 0xff},
+		{1, 0}: {0, 0xff, 0, 0xff},
+		{0, 1}: {0, 0, 0xff, 0xff},
+		{1, 1}: {0xff, 0xff, 0xff, 0xff},
+	}
+	for j := 0; j < 5; j++ {
+		for i := 0; i < 5; i++ {
+			var want [4]byte
+			if 1 <= i && i < 5 && 1 <= j && j < 5 {
+				want = colors[[2]int{(i - 1) / 2, (j - 1) / 2}]
+			}
+			idx := 4 * (i + j*5)
+			var c [4]byte
+			copy(c[:], got[idx:idx+4])
+			if c != want {
+				t.Errorf("At(%d, %d): got: %v, want: %v", i, j, c, want)
+			}
+		}
+	}
+}
+
+func TestDrawColorM(t *testing.T) {
+	var d graphicsdriver.GraphicsDriver = Get()
+
+	src := newImage(t, d, 4, 4, fill(4, 4, 0x80, 0x80, 0x80, 0x80))
+	dst := newImage(t, d, 4, 4, nil)
+
+	// The color matrix is applied to the straight-alpha color (0xff, 0xff, 0xff, 0x80).
+	var m *affine.ColorM
+	m = m.Scale(1, 0.5, 0, 1)
+	m = m.Translate(0, 0, 0, 0.25)
+	draw(t, d, dst, src, 4, 4, 0, 0, 1, m, graphics.CompositeModeCopy, graphics.FilterNearest, graphics.ColorMaskNone)
+
+	got := pixels(t, dst)
+	// The alpha is 0x80/0xff + 0.25 = 0.752, and the result is premultiplied.
+	want := []byte{0xc0, 0x60, 0, 0xc0}
+	for i := 0; i < 4; i++ {
+		if diff := int(got[i]) - int(want[i]); diff < -1 || 1 < diff {
+			t.Errorf("got: %v, want: %v", got[:4], want)
+			break
+		}
+	}
+}
+
+func TestDrawCompositeMode(t *testing.T) {
+	var d graphicsdriver.GraphicsDriver = Get()
+
+	cases := []struct {
+		Mode graphics.CompositeMode
+		Want []byte
+	}{
+		{graphics.CompositeModeSourceOver, []byte{0x80, 0x40, 0x40, 0xc0}},
+		{graphics.CompositeModeCopy, []byte{0x80, 0, 0, 0x80}},
+		{graphics.CompositeModeClear, []byte{0, 0, 0, 0}},
+		{graphics.CompositeModeDestination, []byte{0, 0x80, 0x80, 0x80}},
+		{graphics.CompositeModeLighter, []byte{0x80, 0x80, 0x80, 0xff}},
+	}
+	for _, c := range cases {
+		src := newImage(t, d, 4, 4, fill(4, 4, 0x80, 0, 0, 0x80))
+		dst := newImage(t, d, 4, 4, fill(4, 4, 0, 0x80, 0x80, 0x80))
+		draw(t, d, dst, src, 4, 4, 0, 0, 1, nil, c.Mode, graphics.FilterNearest, graphics.ColorMaskNone)
+
+		got := pixels(t, dst)
+		for i := 0; i < 4*4*4; i++ {
+			if diff := int(got[i]) - int(c.Want[i%4]); diff < -1 || 1 < diff {
+				t.Errorf("mode: %d, pixel %d: got: %v, want: %v", c.Mode, i/4, got[i/4*4:i/4*4+4], c.Want)
+				break
+			}
+		}
+	}
+}
+
+func TestDrawSharedEdges(t *testing.T) {
+	var d graphicsdriver.GraphicsDriver = Get()
+
+	// The two triangles of a quad share the diagonal edge. With the additive composite mode,
+	// pixels rendered twice would be brighter.
+	src := newImage(t, d, 8, 8, fill(8, 8, 0x40, 0x40, 0x40, 0x40))
+	dst := newImage(t, d, 16, 16, nil)
+	draw(t, d, dst, src, 8, 8, 0, 0, 2, nil, graphics.CompositeModeLighter, graphics.FilterNearest, graphics.ColorMaskNone)
+
+	got := pixels(t, dst)
+	for i := 0; i < len(got); i++ {
+		if got[i] != 0x40 {
+			t.Errorf("pixel %d: got: %v, want: %v", i/4, got[i/4*4:i/4*4+4], []byte{0x40, 0x40, 0x40, 0x40})
+			break
+		}
+	}
+}
+
+func TestDrawColorMask(t *testing.T) {
+	var d graphicsdriver.GraphicsDriver = Get()
+
+	src := newImage(t, d, 4, 4, fill(4, 4, 0xff, 0xff, 0xff, 0xff))
+	dst := newImage(t, d, 4, 4, fill(4, 4, 0x20, 0x20, 0x20, 0x40))
+	draw(t, d, dst, src, 4, 4, 0, 0, 1, nil, graphics.CompositeModeCopy, graphics.FilterNearest, graphics.ColorMaskRed|graphics.ColorMaskBlue)
+
+	got := pixels(t, dst)[:4]
+	want := []byte{0x20, 0xff, 0x20, 0xff}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got: %v, want: %v", got, want)
+			break
+		}
+	}
+}
+
+func TestDrawLinear(t *testing.T) {
+	var d graphicsdriver.GraphicsDriver = Get()
+
+	src := newImage(t, d, 2, 2, []byte{
+		0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff,
+	})
+	dst := newImage(t, d, 8, 8, nil)
+	draw(t, d, dst, src, 2, 2, 0, 0, 4, nil, graphics.CompositeModeCopy, graphics.FilterLinear, graphics.ColorMaskNone)
+
+	got := pixels(t, dst)
+	const y = 3
+	cases := []struct {
+		X    int
+		Want [4]byte
+	}{
+		// The colors are interpolated between the texel centers.
+		{3, [4]byte{0x60, 0x60, 0x60, 0xff}},
+		{4, [4]byte{0x9f, 0x9f, 0x9f, 0xff}},
+		// The colors at the edges are interpolated with the transparent color outside of the source.
+		{0, [4]byte{0, 0, 0, 0x9f}},
+		{7, [4]byte{0x9f, 0x9f, 0x9f, 0x9f}},
+	}
+	for _, c := range cases {
+		idx := 4 * (c.X + y*8)
+		for k := 0; k < 4; k++ {
+			if diff := int(got[idx+k]) - int(c.Want[k]); diff < -1 || 1 < diff {
+				t.Errorf("At(%d, %d): got: %v, want: %v", c.X, y, got[idx:idx+4], c.Want)
+				break
+			}
+		}
+	}
+}
+
+func TestPixels(t *testing.T) {
+	var d graphicsdriver.GraphicsDriver = Get()
+
+	// The internal buffer of a non-power-of-two image is larger than the image.
+	img := newImage(t, d, 3, 3, nil)
+	img.ReplacePixels([]byte{1, 2, 3, 4, 5, 6, 7, 8}, 1, 2, 2, 1)
+	got := pixels(t, img)
+	if len(got) != 4*3*3 {
+		t.Fatalf("len(Pixels()): got: %d, want: %d", len(got), 4*3*3)
+	}
+	want := make([]byte, 4*3*3)
+	copy(want[4*(1+2*3):], []byte{1, 2, 3, 4, 5, 6, 7, 8})
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got: %v, want: %v", got, want)
+			break
+		}
+	}
+}
+
+func TestDrawClippedTriangles(t *testing.T) {
+	var d graphicsdriver.GraphicsDriver = Get()
+
+	const size = 16
+	pix := make([]byte, 4*4*4)
+	for i := 0; i < 16; i++ {
+		pix[4*i] = byte(i * 16)
+		pix[4*i+1] = byte(0xff - i*16)
+		pix[4*i+2] = 0x80
+		pix[4*i+3] = 0xff
+	}
+	src := newImage(t, d, 4, 4, pix)
+
+	// A rotated and scaled quad covering a part of the destination.
+	vs := graphics.QuadVertices(4, 4, 0, 0, 4, 4, 2.5, 1.5, -1.5, 2.5, 6, -1, 1, 1, 1, 1)
+	is := graphics.QuadIndices()
+	drawVertices := func(dst graphicsdriver.Image, vs []float32, is []uint16) {
+		d.SetVertices(vs, is)
+		dst.SetAsDestination()
+		src.SetAsSource()
+		if err := d.Draw(len(is), 0, graphics.CompositeModeSourceOver, nil, graphics.FilterLinear, graphics.AddressClampToZero, graphics.ColorMaskNone, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	whole := newImage(t, d, size, size, nil)
+	drawVertices(whole, vs, is)
+
+	const x0, y0, x1, y1 = 3, 2, 11, 13
+	clipped := newImage(t, d, size, size, nil)
+	graphics.ClipTriangles(vs, is, x0, y0, x1, y1, func(vs []float32, is []uint16) {
+		drawVertices(clipped, vs, is)
+	})
+
+	// Clipping must not change the result inside the rectangle, and must not render outside.
+	p0 := pixels(t, whole)
+	p1 := pixels(t, clipped)
+	for j := 0; j < size; j++ {
+		for i := 0; i < size; i++ {
+			for c := 0; c < 4; c++ {
+				idx := 4*(i+j*size) + c
+				want := p0[idx]
+				if i < x0 || x1 <= i || j < y0 || y1 <= j {
+					want = 0
+				}
+				if diff := int(p1[idx]) - int(want); diff < -1 || 1 < diff {
+					t.Errorf("pixel (%d, %d)[%d]: got: %d, want: %d", i, j, c, p1[idx], want)
+				}
+			}
+		}
+	}
+}
+
+func TestDrawVertexColorsInLinearSpace(t *testing.T) {
+	var d graphicsdriver.GraphicsDriver = Get()
+
+	const w, h = 4, 4
+	src := newImage(t, d, w, h, fill(w, h, 0xff, 0xff, 0xff, 0xff))
+	for _, c := range []struct {
+		ColorM *affine.ColorM
+		Want   byte
+	}{
+		{nil, 0x80},
+		// The identity matrix in the linear space still applies the vertex colors in the linear space.
+		// 0.5 in the linear space is 0.5^(1/2.2) ≈ 0.73 in the sRGB space.
+		{(*affine.ColorM)(nil).InLinearSpace(), 0xba},
+	} {
+		dst := newImage(t, d, w, h, nil)
+		vs := graphics.QuadVertices(w, h, 0, 0, w, h, 1, 0, 0, 1, 0, 0, 0.5, 0.5, 0.5, 1)
+		is := graphics.QuadIndices()
+		d.SetVertices(vs, is)
+		dst.SetAsDestination()
+		src.SetAsSource()
+		if err := d.Draw(len(is), 0, graphics.CompositeModeCopy, c.ColorM, graphics.FilterNearest, graphics.AddressClampToZero, graphics.ColorMaskNone, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		got := pixels(t, dst)
+		if d := int(got[0]) - int(c.Want); d < -1 || 1 < d {
+			t.Errorf("linear: %v: got: %#x, want: %#x", c.ColorM.IsInLinearSpace(), got[0], c.Want)
+		}
+	}
+}
+
+func TestDrawMirroredRepeatBoundary(t *testing.T) {
+	var d graphicsdriver.GraphicsDriver = Get()
+
+	src := newImage(t, d, 2, 1, []byte{
+		0xff, 0, 0, 0xff, 0, 0xff, 0, 0xff,
+	})
+	dst := newImage(t, d, 4, 1, nil)
+
+	// Draw the source scaled by 2, and shift the texture coordinates so that the pixel centers of dst
+	// sample the source exactly at the texel boundaries 0, 1, 2 and 3. 2 is the mirror boundary.
+	vs := graphics.QuadVertices(2, 1, 0, 0, 2, 1, 2, 0, 0, 1, 0, 0, 1, 1, 1, 1)
+	for i := 0; i < len(vs)/graphics.VertexFloatNum; i++ {
+		u := &vs[i*graphics.VertexFloatNum+2]
+		*u = 2**u - 0.25
+	}
+	is := graphics.QuadIndices()
+	d.SetVertices(vs, is)
+	dst.SetAsDestination()
+	src.SetAsSource()
+	if err := d.Draw(len(is), 0, graphics.CompositeModeCopy, nil, graphics.FilterNearest, graphics.AddressMirroredRepeat, graphics.ColorMaskNone, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	got := pixels(t, dst)
+	red := [4]byte{0xff, 0, 0, 0xff}
+	green := [4]byte{0, 0xff, 0, 0xff}
+	for i, want := range [][4]byte{red, green, green, green} {
+		var c [4]byte
+		copy(c[:], got[4*i:4*i+4])
+		if c != want {
+			t.Errorf("At(%d, 0): got: %v, want: %v", i, c, want)
+		}
+	}
+}
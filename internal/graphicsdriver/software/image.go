@@ -0,0 +1,69 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package software
+
+type Image struct {
+	driver       *Driver
+	width        int
+	height       int
+	bufferWidth  int
+	bufferHeight int
+	screen       bool
+
+	// pixels is the premultiplied RGBA pixels of bufferWidth x bufferHeight.
+	pixels []byte
+}
+
+func (i *Image) Dispose() {
+	i.pixels = nil
+}
+
+func (i *Image) IsInvalidated() bool {
+	return false
+}
+
+func (i *Image) Pixels() ([]byte, error) {
+	p := make([]byte, 4*i.width*i.height)
+	for j := 0; j < i.height; j++ {
+		copy(p[4*i.width*j:4*i.width*(j+1)], i.pixels[4*i.bufferWidth*j:])
+	}
+	return p, nil
+}
+
+func (i *Image) SetAsDestination() {
+	i.driver.destination = i
+}
+
+func (i *Image) SetAsSource() {
+	if i.screen {
+		panic("software: the screen image cannot be a source")
+	}
+	i.driver.source = i
+}
+
+func (i *Image) ReplacePixels(pixels []byte, x, y, width, height int) {
+	if i.screen {
+		// The screen image doesn't have a texture to replace pixels.
+		panic("not reached")
+	}
+	for j := 0; j < height; j++ {
+		copy(i.pixels[4*(x+(y+j)*i.bufferWidth):4*(x+width+(y+j)*i.bufferWidth)], pixels[4*width*j:4*width*(j+1)])
+	}
+}
+
+// BlitToDefaultFramebuffer does nothing since there is no default framebuffer.
+func (i *Image) BlitToDefaultFramebuffer(dstWidth, dstHeight int) error {
+	return nil
+}
@@ -0,0 +1,297 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package software
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/internal/affine"
+	"github.com/hajimehoshi/ebiten/internal/graphics"
+)
+
+// gamma is the gamma value used for the conversion between the linear and sRGB spaces.
+const gamma = 2.2
+
+type color [4]float64
+
+// renderer renders triangles in the same way as the OpenGL driver's shaders.
+type renderer struct {
+	src     *Image
+	dst     *Image
+	colorM  *affine.ColorM
+	filter  graphics.Filter
+	address graphics.Address
+	mask    graphics.ColorMask
+	srcOp   graphics.Operation
+	dstOp   graphics.Operation
+}
+
+// edge returns a value that is positive when p is on the left side of the edge from u to v
+// in the coordinate system where the Y axis is downward.
+func edge(u, v []float32, px, py float64) float64 {
+	return (float64(v[0])-float64(u[0]))*(py-float64(u[1])) - (float64(v[1])-float64(u[1]))*(px-float64(u[0]))
+}
+
+// includesEdge reports whether the pixels exactly on the edge from u to v are rendered.
+// A shared edge between two adjacent triangles is traversed in the opposite directions,
+// and then the pixels on the edge are rendered exactly once.
+func includesEdge(u, v []float32) bool {
+	dx, dy := v[0]-u[0], v[1]-u[1]
+	return dy > 0 || (dy == 0 && dx < 0)
+}
+
+func (r *renderer) drawTriangle(a, b, c []float32) {
+	area := edge(a, b, float64(c[0]), float64(c[1]))
+	if area == 0 {
+		return
+	}
+	if area < 0 {
+		b, c = c, b
+		area = -area
+	}
+
+	x0 := math.Min(float64(a[0]), math.Min(float64(b[0]), float64(c[0])))
+	y0 := math.Min(float64(a[1]), math.Min(float64(b[1]), float64(c[1])))
+	x1 := math.Max(float64(a[0]), math.Max(float64(b[0]), float64(c[0])))
+	y1 := math.Max(float64(a[1]), math.Max(float64(b[1]), float64(c[1])))
+	minX := int(math.Max(0, math.Floor(x0)))
+	minY := int(math.Max(0, math.Floor(y0)))
+	maxX := int(math.Min(float64(r.dst.bufferWidth-1), math.Ceil(x1)))
+	maxY := int(math.Min(float64(r.dst.bufferHeight-1), math.Ceil(y1)))
+
+	inc0 := includesEdge(b, c)
+	inc1 := includesEdge(c, a)
+	inc2 := includesEdge(a, b)
+
+	attrs := make([]float64, len(a))
+	for j := minY; j <= maxY; j++ {
+		for i := minX; i <= maxX; i++ {
+			// Sample at the pixel center.
+			px, py := float64(i)+0.5, float64(j)+0.5
+			w0 := edge(b, c, px, py)
+			w1 := edge(c, a, px, py)
+			w2 := edge(a, b, px, py)
+			if w0 < 0 || w1 < 0 || w2 < 0 {
+				continue
+			}
+			if (w0 == 0 && !inc0) || (w1 == 0 && !inc1) || (w2 == 0 && !inc2) {
+				continue
+			}
+			l0, l1, l2 := w0/area, w1/area, w2/area
+			for k := range attrs {
+				attrs[k] = l0*float64(a[k]) + l1*float64(b[k]) + l2*float64(c[k])
+			}
+			r.blend(i, j, r.fragment(attrs))
+		}
+	}
+}
+
+// fragment returns the premultiplied color for the interpolated vertex attributes.
+func (r *renderer) fragment(attrs []float64) color {
+	u, v := attrs[2], attrs[3]
+	region := [4]float64{attrs[4], attrs[5], attrs[6], attrs[7]}
+
+	var clr color
+	switch r.filter {
+	case graphics.FilterNearest:
+		clr = r.sourceTexel(u, v, region)
+	default:
+		clr = r.linear(u, v, region)
+	}
+
+	// Un-premultiply alpha
+	if clr[3] > 0 {
+		for k := 0; k < 3; k++ {
+			clr[k] /= clr[3]
+		}
+	}
+	linear := r.colorM.IsInLinearSpace()
+	if linear {
+		for k := 0; k < 3; k++ {
+			clr[k] = math.Pow(clr[k], gamma)
+		}
+	}
+
+	// Apply the color matrix and the color scale.
+	body, translate := r.colorM.UnsafeElements()
+	var out color
+	for k := 0; k < 4; k++ {
+		v := float64(translate[k])
+		for l := 0; l < 4; l++ {
+			v += float64(body[k+l*4]) * clr[l]
+		}
+		out[k] = clamp(v * attrs[8+k])
+	}
+	if linear {
+		for k := 0; k < 3; k++ {
+			out[k] = math.Pow(out[k], 1/gamma)
+		}
+	}
+
+	// Premultiply alpha
+	for k := 0; k < 3; k++ {
+		out[k] *= out[3]
+	}
+	return out
+}
+
+// adjustTexelByAddress returns the texture coordinate for the address mode.
+func (r *renderer) adjustTexelByAddress(u, v float64, region [4]float64) (float64, float64) {
+	switch r.address {
+	case graphics.AddressRepeat:
+		w, h := region[2]-region[0], region[3]-region[1]
+		return floorMod(u-region[0], w) + region[0], floorMod(v-region[1], h) + region[1]
+	case graphics.AddressMirroredRepeat:
+		w, h := region[2]-region[0], region[3]-region[1]
+		tu, tv := floorMod(u-region[0], 2*w), floorMod(v-region[1], 2*h)
+		u, v = w-math.Abs(tu-w)+region[0], h-math.Abs(tv-h)+region[1]
+		// At the mirror boundary, (u, v) is the region's max edge, which is out of the region.
+		// Clamp it a little inner than the threshold of inRegion.
+		tw, th := 1/float64(r.src.bufferWidth), 1/float64(r.src.bufferHeight)
+		return math.Min(u, region[2]-tw/256), math.Min(v, region[3]-th/256)
+	}
+	return u, v
+}
+
+// inRegion reports whether the texture coordinate (u, v) is in the region.
+func (r *renderer) inRegion(u, v float64, region [4]float64) bool {
+	tw, th := 1/float64(r.src.bufferWidth), 1/float64(r.src.bufferHeight)
+	return region[0] <= u && region[1] <= v && u < region[2]-tw/512 && v < region[3]-th/512
+}
+
+// sourceTexel returns the texel at (u, v), or a transparent color if (u, v) is out of the region.
+func (r *renderer) sourceTexel(u, v float64, region [4]float64) color {
+	u, v = r.adjustTexelByAddress(u, v, region)
+	if !r.inRegion(u, v, region) {
+		return color{}
+	}
+	return r.texel(u, v)
+}
+
+func (r *renderer) linear(u, v float64, region [4]float64) color {
+	tw, th := 1/float64(r.src.bufferWidth), 1/float64(r.src.bufferHeight)
+	u0, v0 := u-tw/2, v-th/2
+	u1, v1 := u+tw/2, v+th/2
+
+	// Adjust the second texel in the same way as the OpenGL driver's adjustTexel.
+	if f := (u1 - u0) * float64(r.src.bufferWidth); f == math.Floor(f) {
+		u1 -= tw / 512
+	}
+	if f := (v1 - v0) * float64(r.src.bufferHeight); f == math.Floor(f) {
+		v1 -= th / 512
+	}
+	u0, v0 = r.adjustTexelByAddress(u0, v0, region)
+	u1, v1 = r.adjustTexelByAddress(u1, v1, region)
+	rateX := fract(u0 * float64(r.src.bufferWidth))
+	rateY := fract(v0 * float64(r.src.bufferHeight))
+
+	c0 := r.texel(u0, v0)
+	c1 := r.texel(u1, v0)
+	c2 := r.texel(u0, v1)
+	c3 := r.texel(u1, v1)
+	if u0 < region[0] {
+		c0, c2 = color{}, color{}
+	}
+	if v0 < region[1] {
+		c0, c1 = color{}, color{}
+	}
+	if region[2]-tw/512 <= u1 {
+		c1, c3 = color{}, color{}
+	}
+	if region[3]-th/512 <= v1 {
+		c2, c3 = color{}, color{}
+	}
+
+	var clr color
+	for k := 0; k < 4; k++ {
+		top := c0[k]*(1-rateX) + c1[k]*rateX
+		bottom := c2[k]*(1-rateX) + c3[k]*rateX
+		clr[k] = top*(1-rateY) + bottom*rateY
+	}
+	return clr
+}
+
+// texel returns the texel at (u, v) of the source image. (u, v) is clamped to the edges.
+func (r *renderer) texel(u, v float64) color {
+	x := clampInt(int(math.Floor(u*float64(r.src.bufferWidth))), 0, r.src.bufferWidth-1)
+	y := clampInt(int(math.Floor(v*float64(r.src.bufferHeight))), 0, r.src.bufferHeight-1)
+	idx := 4 * (x + y*r.src.bufferWidth)
+	p := r.src.pixels[idx : idx+4]
+	return color{float64(p[0]) / 0xff, float64(p[1]) / 0xff, float64(p[2]) / 0xff, float64(p[3]) / 0xff}
+}
+
+// blend blends the premultiplied color src to the destination pixel at (x, y) with the composite mode.
+func (r *renderer) blend(x, y int, src color) {
+	idx := 4 * (x + y*r.dst.bufferWidth)
+	p := r.dst.pixels[idx : idx+4]
+	dst := color{float64(p[0]) / 0xff, float64(p[1]) / 0xff, float64(p[2]) / 0xff, float64(p[3]) / 0xff}
+	for k := 0; k < 4; k++ {
+		if r.mask&(1<<uint(k)) != 0 {
+			continue
+		}
+		v := src[k]*factor(r.srcOp, k, src, dst) + dst[k]*factor(r.dstOp, k, src, dst)
+		p[k] = uint8(math.Floor(clamp(v)*0xff + 0.5))
+	}
+}
+
+// factor returns the blending factor of the operation for the channel k.
+func factor(op graphics.Operation, k int, src, dst color) float64 {
+	switch op {
+	case graphics.Zero:
+		return 0
+	case graphics.One:
+		return 1
+	case graphics.SrcAlpha:
+		return src[3]
+	case graphics.DstAlpha:
+		return dst[3]
+	case graphics.OneMinusSrcAlpha:
+		return 1 - src[3]
+	case graphics.OneMinusDstAlpha:
+		return 1 - dst[3]
+	case graphics.SrcColor:
+		return src[k]
+	case graphics.DstColor:
+		return dst[k]
+	case graphics.OneMinusSrcColor:
+		return 1 - src[k]
+	case graphics.OneMinusDstColor:
+		return 1 - dst[k]
+	default:
+		panic("not reached")
+	}
+}
+
+func floorMod(x, y float64) float64 {
+	return x - y*math.Floor(x/y)
+}
+
+func fract(x float64) float64 {
+	return x - math.Floor(x)
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
@@ -0,0 +1,52 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package input
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// gamepadAxisDeadZone is the dead zone of gamepad axes as bits of float64.
+var gamepadAxisDeadZone uint64
+
+// GamepadAxisDeadZone returns the dead zone of gamepad axes.
+func GamepadAxisDeadZone() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&gamepadAxisDeadZone))
+}
+
+// SetGamepadAxisDeadZone sets the dead zone of gamepad axes.
+func SetGamepadAxisDeadZone(deadZone float64) {
+	atomic.StoreUint64(&gamepadAxisDeadZone, math.Float64bits(deadZone))
+}
+
+// applyDeadZone applies the dead zone to the axis value v.
+//
+// A value whose absolute value is less than or equal to deadZone is treated as 0,
+// and the rest is rescaled so that the value changes continuously from 0 to 1 (or -1).
+// The result is clamped to [-1, 1].
+func applyDeadZone(v float64, deadZone float64) float64 {
+	a := math.Abs(v)
+	if a <= deadZone {
+		return 0
+	}
+	if a > 1 {
+		a = 1
+	}
+	if deadZone > 0 {
+		a = (a - deadZone) / (1 - deadZone)
+	}
+	return math.Copysign(a, v)
+}
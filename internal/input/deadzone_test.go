@@ -0,0 +1,46 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package input
+
+import (
+	"math"
+	"testing"
+)
+
+func TestApplyDeadZone(t *testing.T) {
+	cases := []struct {
+		Value    float64
+		DeadZone float64
+		Want     float64
+	}{
+		{0.5, 0, 0.5},
+		{-0.5, 0, -0.5},
+		{0.1, 0.2, 0},
+		{-0.2, 0.2, 0},
+		{0.6, 0.2, 0.5},
+		{-0.6, 0.2, -0.5},
+		{1, 0.2, 1},
+		{-1, 0.2, -1},
+		// Values beyond the range are clamped.
+		{1.5, 0, 1},
+		{-1.01, 0.2, -1},
+	}
+	for _, c := range cases {
+		got := applyDeadZone(c.Value, c.DeadZone)
+		if math.Abs(got-c.Want) > 1e-9 {
+			t.Errorf("applyDeadZone(%v, %v): got: %v, want: %v", c.Value, c.DeadZone, got, c.Want)
+		}
+	}
+}
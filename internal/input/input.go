@@ -14,6 +14,10 @@
 
 package input
 
+import (
+	"github.com/hajimehoshi/ebiten/internal/gamepaddb"
+)
+
 var theInput = &Input{}
 
 func Get() *Input {
@@ -48,7 +52,7 @@ func (i *Input) GamepadIDs() []int {
 func (i *Input) GamepadAxisNum(id int) int {
 	i.m.RLock()
 	defer i.m.RUnlock()
-	if len(i.gamepads) <= id {
+	if id < 0 || len(i.gamepads) <= id {
 		return 0
 	}
 	return i.gamepads[id].axisNum
@@ -57,16 +61,20 @@ func (i *Input) GamepadAxisNum(id int) int {
 func (i *Input) GamepadAxis(id int, axis int) float64 {
 	i.m.RLock()
 	defer i.m.RUnlock()
-	if len(i.gamepads) <= id {
+	if id < 0 || len(i.gamepads) <= id {
+		return 0
+	}
+	g := i.gamepads[id]
+	if axis < 0 || g.axisNum <= axis {
 		return 0
 	}
-	return i.gamepads[id].axes[axis]
+	return applyDeadZone(g.axes[axis], GamepadAxisDeadZone())
 }
 
 func (i *Input) GamepadButtonNum(id int) int {
 	i.m.RLock()
 	defer i.m.RUnlock()
-	if len(i.gamepads) <= id {
+	if id < 0 || len(i.gamepads) <= id {
 		return 0
 	}
 	return i.gamepads[id].buttonNum
@@ -75,12 +83,77 @@ func (i *Input) GamepadButtonNum(id int) int {
 func (i *Input) IsGamepadButtonPressed(id int, button GamepadButton) bool {
 	i.m.RLock()
 	defer i.m.RUnlock()
-	if len(i.gamepads) <= id {
+	if id < 0 || len(i.gamepads) <= id {
 		return false
 	}
 	return i.gamepads[id].buttonPressed[button]
 }
 
+func (i *Input) GamepadName(id int) string {
+	i.m.RLock()
+	defer i.m.RUnlock()
+	if id < 0 || len(i.gamepads) <= id {
+		return ""
+	}
+	return i.gamepads[id].name
+}
+
+func (i *Input) IsStandardGamepadLayoutAvailable(id int) bool {
+	i.m.RLock()
+	defer i.m.RUnlock()
+	if id < 0 || len(i.gamepads) <= id {
+		return false
+	}
+	g := &i.gamepads[id]
+	if g.standard {
+		return true
+	}
+	return gamepaddb.Get(g.name) != nil
+}
+
+func (i *Input) IsStandardGamepadButtonPressed(id int, button gamepaddb.StandardButton) bool {
+	i.m.RLock()
+	defer i.m.RUnlock()
+	if id < 0 || len(i.gamepads) <= id {
+		return false
+	}
+	g := &i.gamepads[id]
+	if g.standard {
+		// The button indices are defined by the standard gamepad layout of the Gamepad API.
+		// See https://www.w3.org/TR/gamepad/#remapping.
+		b, ok := standardLayoutButtons[button]
+		if !ok {
+			return false
+		}
+		return g.buttonPressed[b]
+	}
+	m := gamepaddb.Get(g.name)
+	if m == nil {
+		return false
+	}
+	return m.IsButtonPressed(button, g.buttonPressed[:g.buttonNum], g.axes[:g.axisNum], g.hats[:g.hatNum])
+}
+
+var standardLayoutButtons = map[gamepaddb.StandardButton]int{
+	gamepaddb.StandardButtonA:             0,
+	gamepaddb.StandardButtonB:             1,
+	gamepaddb.StandardButtonX:             2,
+	gamepaddb.StandardButtonY:             3,
+	gamepaddb.StandardButtonLeftShoulder:  4,
+	gamepaddb.StandardButtonRightShoulder: 5,
+	gamepaddb.StandardButtonLeftTrigger:   6,
+	gamepaddb.StandardButtonRightTrigger:  7,
+	gamepaddb.StandardButtonBack:          8,
+	gamepaddb.StandardButtonStart:         9,
+	gamepaddb.StandardButtonLeftStick:     10,
+	gamepaddb.StandardButtonRightStick:    11,
+	gamepaddb.StandardButtonDPadUp:        12,
+	gamepaddb.StandardButtonDPadDown:      13,
+	gamepaddb.StandardButtonDPadLeft:      14,
+	gamepaddb.StandardButtonDPadRight:     15,
+	gamepaddb.StandardButtonGuide:         16,
+}
+
 var emptyTouches = []*Touch{}
 
 func (in *Input) Touches() []*Touch {
@@ -102,10 +175,16 @@ func (in *Input) Touches() []*Touch {
 
 type gamePad struct {
 	valid         bool
+	name          string
+	standard      bool
 	axisNum       int
 	axes          [16]float64
 	buttonNum     int
 	buttonPressed [256]bool
+
+	// hats are the hat states as bitmasks of gamepaddb.HatUp, HatRight, HatDown and HatLeft.
+	hatNum int
+	hats   [16]int
 }
 
 type Touch struct {
@@ -20,7 +20,9 @@
 package input
 
 import (
+	"math"
 	"sync"
+	"time"
 	"unicode"
 
 	"github.com/hajimehoshi/ebiten/internal/glfw"
@@ -34,6 +36,8 @@ type Input struct {
 	scrollY              float64
 	cursorX              int
 	cursorY              int
+	cursorXInFloat       float64
+	cursorYInFloat       float64
 	gamepads             [16]gamePad
 	touches              []*Touch // This is not updated until GLFW 3.3 is available (#417)
 	runeBuffer           []rune
@@ -53,11 +57,20 @@ func (i *Input) ClearRuneBuffer() {
 }
 
 func (i *Input) ResetScrollValues() {
-	i.m.RLock()
-	defer i.m.RUnlock()
+	i.m.Lock()
+	defer i.m.Unlock()
 	i.scrollX, i.scrollY = 0, 0
 }
 
+// addScrollValues accumulates the scroll offsets until ResetScrollValues is called.
+// The offsets are not rounded so that high-resolution deltas from touchpads are kept.
+func (i *Input) addScrollValues(xoff, yoff float64) {
+	i.m.Lock()
+	defer i.m.Unlock()
+	i.scrollX += xoff
+	i.scrollY += yoff
+}
+
 func (i *Input) IsKeyPressed(key Key) bool {
 	i.m.RLock()
 	defer i.m.RUnlock()
@@ -75,6 +88,13 @@ func (i *Input) IsKeyPressed(key Key) bool {
 	return false
 }
 
+// CursorPositionInFloat returns the cursor position in the same coordinates as CursorPosition without rounding.
+func (i *Input) CursorPositionInFloat() (x, y float64) {
+	i.m.RLock()
+	defer i.m.RUnlock()
+	return i.cursorXInFloat, i.cursorYInFloat
+}
+
 func (i *Input) IsMouseButtonPressed(button MouseButton) bool {
 	i.m.RLock()
 	defer i.m.RUnlock()
@@ -117,18 +137,19 @@ func (i *Input) Update(window *glfw.Window, scale float64) {
 			}
 		})
 		window.SetScrollCallback(func(w *glfw.Window, xoff float64, yoff float64) {
-			i.m.Lock()
-			i.scrollX = xoff
-			i.scrollY = yoff
-			i.m.Unlock()
+			// The callback can be called multiple times in a frame.
+			i.addScrollValues(xoff, yoff)
 		})
 		i.callbacksInitialized = true
 	}
 	if i.keyPressed == nil {
 		i.keyPressed = map[glfw.Key]bool{}
 	}
+	// Keys are treated as released while the window is not focused, so that key presses for other
+	// windows don't leak when the game runs in background.
+	focused := window.GetAttrib(glfw.Focused) != 0
 	for gk := range glfwKeyCodeToKey {
-		i.keyPressed[gk] = window.GetKey(gk) == glfw.Press
+		i.keyPressed[gk] = focused && window.GetKey(gk) == glfw.Press
 	}
 	if i.mouseButtonPressed == nil {
 		i.mouseButtonPressed = map[glfw.MouseButton]bool{}
@@ -137,14 +158,17 @@ func (i *Input) Update(window *glfw.Window, scale float64) {
 		i.mouseButtonPressed[gb] = window.GetMouseButton(gb) == glfw.Press
 	}
 	x, y := window.GetCursorPos()
-	i.cursorX = int(x / scale)
-	i.cursorY = int(y / scale)
+	i.cursorXInFloat = x / scale
+	i.cursorYInFloat = y / scale
+	i.cursorX = int(math.Floor(i.cursorXInFloat))
+	i.cursorY = int(math.Floor(i.cursorYInFloat))
 	for id := glfw.Joystick(0); id < glfw.Joystick(len(i.gamepads)); id++ {
 		i.gamepads[id].valid = false
 		if !glfw.JoystickPresent(id) {
 			continue
 		}
 		i.gamepads[id].valid = true
+		i.gamepads[id].name = glfw.GetJoystickName(id)
 
 		axes32 := glfw.GetJoystickAxes(id)
 		i.gamepads[id].axisNum = len(axes32)
@@ -164,5 +188,12 @@ func (i *Input) Update(window *glfw.Window, scale float64) {
 			}
 			i.gamepads[id].buttonPressed[b] = glfw.Action(buttons[b]) == glfw.Press
 		}
+
+		// TODO: GLFW 3.2 doesn't provide hat states. Use glfwGetJoystickHats after updating to GLFW 3.3.
+		i.gamepads[id].hatNum = 0
 	}
 }
+
+func (i *Input) VibrateGamepad(id int, strongMagnitude, weakMagnitude float64, duration time.Duration) {
+	// GLFW doesn't support vibration so far.
+}
@@ -0,0 +1,142 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build darwin freebsd linux windows
+// +build !js
+// +build !android
+// +build !ios
+
+package input
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/internal/gamepaddb"
+)
+
+func TestScrollValues(t *testing.T) {
+	i := &Input{}
+
+	if x, y := i.Wheel(); x != 0 || y != 0 {
+		t.Errorf("Wheel(): got: (%v, %v), want: (0, 0)", x, y)
+	}
+
+	// Scroll events in a frame are accumulated without rounding.
+	i.addScrollValues(0, 1)
+	i.addScrollValues(0.25, -0.125)
+	i.addScrollValues(0.5, 2)
+	if x, y := i.Wheel(); x != 0.75 || y != 2.875 {
+		t.Errorf("Wheel(): got: (%v, %v), want: (0.75, 2.875)", x, y)
+	}
+
+	// The offsets are reset for the next frame.
+	i.ResetScrollValues()
+	if x, y := i.Wheel(); x != 0 || y != 0 {
+		t.Errorf("Wheel() after reset: got: (%v, %v), want: (0, 0)", x, y)
+	}
+
+	i.addScrollValues(-1, 0)
+	if x, y := i.Wheel(); x != -1 || y != 0 {
+		t.Errorf("Wheel() in the next frame: got: (%v, %v), want: (-1, 0)", x, y)
+	}
+}
+
+func TestGamepads(t *testing.T) {
+	defer SetGamepadAxisDeadZone(0)
+
+	// Two gamepads are connected at 0 and 2.
+	i := &Input{}
+	i.gamepads[0] = gamePad{
+		valid:   true,
+		axisNum: 2,
+		axes:    [16]float64{0.05, -0.5},
+	}
+	i.gamepads[2] = gamePad{
+		valid:   true,
+		axisNum: 3,
+		axes:    [16]float64{-1, 0.1, 1},
+	}
+
+	if got, want := i.GamepadIDs(), []int{0, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GamepadIDs(): got: %v, want: %v", got, want)
+	}
+
+	cases := []struct {
+		ID       int
+		Axis     int
+		DeadZone float64
+		Want     float64
+	}{
+		{0, 0, 0, 0.05},
+		{0, 1, 0, -0.5},
+		{0, 0, 0.1, 0},
+		{0, 1, 0.1, -0.4 / 0.9},
+		{2, 0, 0.2, -1},
+		{2, 1, 0.1, 0},
+		{2, 2, 0.2, 1},
+
+		// Axes and gamepads that don't exist.
+		{0, 2, 0, 0},
+		{0, -1, 0, 0},
+		{1, 0, 0, 0},
+		{-1, 0, 0, 0},
+		{16, 0, 0, 0},
+	}
+	for _, c := range cases {
+		SetGamepadAxisDeadZone(c.DeadZone)
+		got := i.GamepadAxis(c.ID, c.Axis)
+		if math.Abs(got-c.Want) > 1e-9 {
+			t.Errorf("GamepadAxis(%d, %d) with dead zone %v: got: %v, want: %v", c.ID, c.Axis, c.DeadZone, got, c.Want)
+		}
+	}
+
+	// The gamepad 0 is disconnected.
+	i.gamepads[0].valid = false
+	if got, want := i.GamepadIDs(), []int{2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GamepadIDs() after disconnection: got: %v, want: %v", got, want)
+	}
+}
+
+func TestGamepadNegativeID(t *testing.T) {
+	i := &Input{}
+	i.gamepads[0] = gamePad{
+		valid:     true,
+		name:      "gamepad",
+		axisNum:   1,
+		buttonNum: 1,
+	}
+
+	// A negative ID must be treated as an unknown gamepad without panicking.
+	const id = -1
+	if got := i.GamepadName(id); got != "" {
+		t.Errorf("GamepadName(%d): got: %q, want: \"\"", id, got)
+	}
+	if got := i.GamepadAxisNum(id); got != 0 {
+		t.Errorf("GamepadAxisNum(%d): got: %d, want: 0", id, got)
+	}
+	if got := i.GamepadButtonNum(id); got != 0 {
+		t.Errorf("GamepadButtonNum(%d): got: %d, want: 0", id, got)
+	}
+	if i.IsGamepadButtonPressed(id, GamepadButton0) {
+		t.Errorf("IsGamepadButtonPressed(%d, 0): got: true, want: false", id)
+	}
+	if i.IsStandardGamepadLayoutAvailable(id) {
+		t.Errorf("IsStandardGamepadLayoutAvailable(%d): got: true, want: false", id)
+	}
+	if i.IsStandardGamepadButtonPressed(id, gamepaddb.StandardButtonA) {
+		t.Errorf("IsStandardGamepadButtonPressed(%d, A): got: true, want: false", id)
+	}
+}
@@ -17,6 +17,7 @@
 package input
 
 import (
+	"time"
 	"unicode"
 
 	"github.com/gopherjs/gopherwasm/js"
@@ -162,6 +163,8 @@ func (i *Input) UpdateGamepads() {
 			continue
 		}
 		i.gamepads[id].valid = true
+		i.gamepads[id].name = gamepad.Get("id").String()
+		i.gamepads[id].standard = gamepad.Get("mapping").String() == "standard"
 
 		axes := gamepad.Get("axes")
 		axesNum := axes.Get("length").Int()
@@ -187,6 +190,34 @@ func (i *Input) UpdateGamepads() {
 	}
 }
 
+func (i *Input) VibrateGamepad(id int, strongMagnitude, weakMagnitude float64, duration time.Duration) {
+	nav := js.Global().Get("navigator")
+	if nav.Get("getGamepads") == js.Undefined() {
+		return
+	}
+	gamepads := nav.Call("getGamepads")
+	if gamepads.Get("length").Int() <= id {
+		return
+	}
+	gamepad := gamepads.Index(id)
+	if gamepad == js.Undefined() || gamepad == js.Null() {
+		return
+	}
+	// vibrationActuator is not standardized yet and is available only on some browsers like Chrome.
+	a := gamepad.Get("vibrationActuator")
+	if a == js.Undefined() || a == js.Null() {
+		return
+	}
+	if a.Get("playEffect") == js.Undefined() {
+		return
+	}
+	a.Call("playEffect", "dual-rumble", map[string]interface{}{
+		"duration":        float64(duration) / float64(time.Millisecond),
+		"strongMagnitude": strongMagnitude,
+		"weakMagnitude":   weakMagnitude,
+	})
+}
+
 func OnKeyDown(e js.Value) {
 	c := e.Get("code")
 	if c == js.Undefined() {
@@ -249,8 +280,9 @@ func OnMouseMove(e js.Value) {
 
 func OnWheel(e js.Value) {
 	// TODO: What if e.deltaMode is not DOM_DELTA_PIXEL?
-	theInput.wheelX = -e.Get("deltaX").Float()
-	theInput.wheelY = -e.Get("deltaY").Float()
+	// Accumulate the deltas since the wheel event can be fired multiple times in a frame.
+	theInput.wheelX -= e.Get("deltaX").Float()
+	theInput.wheelY -= e.Get("deltaY").Float()
 }
 
 func OnTouchStart(e js.Value) {
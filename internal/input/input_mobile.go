@@ -18,6 +18,7 @@ package input
 
 import (
 	"sync"
+	"time"
 )
 
 type Input struct {
@@ -44,6 +45,10 @@ func (i *Input) IsMouseButtonPressed(key MouseButton) bool {
 	return false
 }
 
+func (i *Input) VibrateGamepad(id int, strongMagnitude, weakMagnitude float64, duration time.Duration) {
+	// Gamepads are not supported on mobiles so far.
+}
+
 func (i *Input) UpdateTouches(touches []*Touch) {
 	i.m.Lock()
 	i.touches = touches // TODO: Need copy?
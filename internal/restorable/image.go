@@ -32,6 +32,9 @@ type drawImageHistoryItem struct {
 	mode     graphics.CompositeMode
 	filter   graphics.Filter
 	address  graphics.Address
+	mask     graphics.ColorMask
+	shader   *graphicscommand.Shader
+	uniforms graphics.Uniforms
 }
 
 // Image represents an image that can be restored when GL context is lost.
@@ -111,7 +114,7 @@ func (i *Image) clear() {
 		0, 0,
 		1, 1, 1, 1)
 	is := graphics.QuadIndices()
-	i.image.DrawImage(dummyImage.image, vs, is, nil, graphics.CompositeModeClear, graphics.FilterNearest, graphics.AddressClampToZero)
+	i.image.DrawImage(dummyImage.image, vs, is, nil, graphics.CompositeModeClear, graphics.FilterNearest, graphics.AddressClampToZero, graphics.ColorMaskNone, nil, nil)
 
 	i.basePixels = nil
 	i.drawImageHistory = nil
@@ -201,7 +204,10 @@ func (i *Image) ReplacePixels(pixels []byte, x, y, width, height int) {
 	}
 
 	if len(i.drawImageHistory) > 0 {
-		panic("restorable: ReplacePixels for a part after DrawImage is forbidden")
+		// The pixels can't be restored by replaying the history with this partial replacement.
+		// Make the image stale so that the pixels are read from GPU later.
+		i.makeStale()
+		return
 	}
 
 	if i.stale {
@@ -227,7 +233,7 @@ func (i *Image) ReplacePixels(pixels []byte, x, y, width, height int) {
 }
 
 // DrawImage draws a given image img to the image.
-func (i *Image) DrawImage(img *Image, vertices []float32, indices []uint16, colorm *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address) {
+func (i *Image) DrawImage(img *Image, vertices []float32, indices []uint16, colorm *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address, mask graphics.ColorMask, shader *graphicscommand.Shader, uniforms graphics.Uniforms) {
 	if len(vertices) == 0 {
 		return
 	}
@@ -236,13 +242,13 @@ func (i *Image) DrawImage(img *Image, vertices []float32, indices []uint16, colo
 	if img.stale || img.volatile || i.screen || !IsRestoringEnabled() {
 		i.makeStale()
 	} else {
-		i.appendDrawImageHistory(img, vertices, indices, colorm, mode, filter, address)
+		i.appendDrawImageHistory(img, vertices, indices, colorm, mode, filter, address, mask, shader, uniforms)
 	}
-	i.image.DrawImage(img.image, vertices, indices, colorm, mode, filter, address)
+	i.image.DrawImage(img.image, vertices, indices, colorm, mode, filter, address, mask, shader, uniforms)
 }
 
 // appendDrawImageHistory appends a draw-image history item to the image.
-func (i *Image) appendDrawImageHistory(image *Image, vertices []float32, indices []uint16, colorm *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address) {
+func (i *Image) appendDrawImageHistory(image *Image, vertices []float32, indices []uint16, colorm *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address, mask graphics.ColorMask, shader *graphicscommand.Shader, uniforms graphics.Uniforms) {
 	if i.stale || i.volatile || i.screen {
 		return
 	}
@@ -261,6 +267,9 @@ func (i *Image) appendDrawImageHistory(image *Image, vertices []float32, indices
 		mode:     mode,
 		filter:   filter,
 		address:  address,
+		mask:     mask,
+		shader:   shader,
+		uniforms: uniforms,
 	}
 	i.drawImageHistory = append(i.drawImageHistory, item)
 }
@@ -391,7 +400,7 @@ func (i *Image) restore() error {
 		if c.image.hasDependency() {
 			panic("not reached")
 		}
-		gimg.DrawImage(c.image.image, c.vertices, c.indices, c.colorm, c.mode, c.filter, c.address)
+		gimg.DrawImage(c.image.image, c.vertices, c.indices, c.colorm, c.mode, c.filter, c.address, c.mask, c.shader, c.uniforms)
 	}
 	i.image = gimg
 
@@ -426,3 +435,8 @@ func (i *Image) IsInvalidated() (bool, error) {
 
 	return i.image.IsInvalidated(), nil
 }
+
+// BlitToDefaultFramebuffer copies the image to the default framebuffer of the current context.
+func (i *Image) BlitToDefaultFramebuffer(dstWidth, dstHeight int) error {
+	return i.image.BlitToDefaultFramebuffer(dstWidth, dstHeight)
+}
@@ -61,6 +61,11 @@ func ResolveStaleImages() {
 	theImages.resolveStaleImages()
 }
 
+// Finish flushes the queued draw commands and waits for them to be completed.
+func Finish() {
+	graphicscommand.FinishCommands()
+}
+
 // Restore restores the images.
 //
 // Restoring means to make all *graphicscommand.Image objects have their textures and framebuffers.
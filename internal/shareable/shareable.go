@@ -15,12 +15,14 @@
 package shareable
 
 import (
+	"fmt"
 	"image"
 	"runtime"
 	"sync"
 
 	"github.com/hajimehoshi/ebiten/internal/affine"
 	"github.com/hajimehoshi/ebiten/internal/graphics"
+	"github.com/hajimehoshi/ebiten/internal/graphicscommand"
 	"github.com/hajimehoshi/ebiten/internal/packing"
 	"github.com/hajimehoshi/ebiten/internal/restorable"
 )
@@ -135,7 +137,7 @@ func (i *Image) ensureNotShared() {
 	vw, vh := i.backend.restorable.Size()
 	vs := graphics.QuadVertices(vw, vh, x, y, x+w, y+h, 1, 0, 0, 1, 0, 0, 1, 1, 1, 1)
 	is := graphics.QuadIndices()
-	newImg.DrawImage(i.backend.restorable, vs, is, nil, graphics.CompositeModeCopy, graphics.FilterNearest, graphics.AddressClampToZero)
+	newImg.DrawImage(i.backend.restorable, vs, is, nil, graphics.CompositeModeCopy, graphics.FilterNearest, graphics.AddressClampToZero, graphics.ColorMaskNone, nil, nil)
 
 	i.dispose(false)
 	i.backend = &backend{
@@ -218,7 +220,7 @@ func (i *Image) PutVertex(dest []float32, dx, dy, sx, sy float32, bx0, by0, bx1,
 
 const MaxCountForShare = 10
 
-func (i *Image) DrawImage(img *Image, vertices []float32, indices []uint16, colorm *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address) {
+func (i *Image) DrawImage(img *Image, vertices []float32, indices []uint16, colorm *affine.ColorM, mode graphics.CompositeMode, filter graphics.Filter, address graphics.Address, mask graphics.ColorMask, shader *graphicscommand.Shader, uniforms graphics.Uniforms) {
 	backendsM.Lock()
 	defer backendsM.Unlock()
 
@@ -240,7 +242,7 @@ func (i *Image) DrawImage(img *Image, vertices []float32, indices []uint16, colo
 		panic("shareable: Image.DrawImage: img must be different from the receiver")
 	}
 
-	i.backend.restorable.DrawImage(img.backend.restorable, vertices, indices, colorm, mode, filter, address)
+	i.backend.restorable.DrawImage(img.backend.restorable, vertices, indices, colorm, mode, filter, address, mask, shader, uniforms)
 
 	i.countForShare = 0
 
@@ -285,7 +287,7 @@ func (i *Image) Fill(r, g, b, a uint8) {
 		af := float32(a) / 0xff
 		colorm = colorm.Translate(rf, gf, bf, af)
 	}
-	i.DrawImage(emptyImage, vs, is, colorm, graphics.CompositeModeCopy, graphics.FilterNearest, graphics.AddressClampToZero)
+	i.DrawImage(emptyImage, vs, is, colorm, graphics.CompositeModeCopy, graphics.FilterNearest, graphics.AddressClampToZero, graphics.ColorMaskNone, nil, nil)
 }
 
 func (i *Image) ReplacePixels(p []byte) {
@@ -314,6 +316,33 @@ func (i *Image) replacePixels(p []byte) {
 	i.backend.restorable.ReplacePixels(p, x, y, w, h)
 }
 
+// ReplacePixelsRegion replaces the pixels of the region (x, y)-(x+width, y+height) of the image with p.
+//
+// The other pixels of the image are kept.
+func (i *Image) ReplacePixelsRegion(p []byte, x, y, width, height int) {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+
+	if i.disposed {
+		panic("shareable: the image must not be disposed")
+	}
+	if x < 0 || y < 0 || width <= 0 || height <= 0 || i.width < x+width || i.height < y+height {
+		panic(fmt.Sprintf("shareable: out of range x: %d, y: %d, width: %d, height: %d", x, y, width, height))
+	}
+	if l := 4 * width * height; len(p) != l {
+		panic(fmt.Sprintf("shareable: len(p) was %d but must be %d", len(p), l))
+	}
+	if i.backend == nil {
+		// The image is not allocated yet, i.e. the image is cleared. Clear the whole region first.
+		i.allocate(true)
+		ox, oy, w, h := i.region()
+		i.backend.restorable.ReplacePixels(nil, ox, oy, w, h)
+	}
+
+	ox, oy, _, _ := i.region()
+	i.backend.restorable.ReplacePixels(p, ox+x, oy+y, width, height)
+}
+
 func (i *Image) At(x, y int) (byte, byte, byte, byte) {
 	backendsM.Lock()
 	defer backendsM.Unlock()
@@ -405,6 +434,25 @@ func (i *Image) IsInvalidated() (bool, error) {
 	return v, err
 }
 
+// BlitToDefaultFramebuffer copies the image to the default framebuffer of the current context.
+//
+// The image must not be shared, e.g., a volatile image.
+func (i *Image) BlitToDefaultFramebuffer(dstWidth, dstHeight int) error {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+	if i.disposed {
+		panic("shareable: the image must not be disposed")
+	}
+	if i.backend == nil {
+		// Not allocated yet.
+		return nil
+	}
+	if i.isShared() {
+		panic("shareable: the image must not be shared")
+	}
+	return i.backend.restorable.BlitToDefaultFramebuffer(dstWidth, dstHeight)
+}
+
 func NewImage(width, height int) *Image {
 	// Actual allocation is done lazily.
 	return &Image{
@@ -511,6 +559,12 @@ func ResolveStaleImages() {
 	restorable.ResolveStaleImages()
 }
 
+func Finish() {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+	restorable.Finish()
+}
+
 func IsRestoringEnabled() bool {
 	// As IsRestoringEnabled is an immutable state, no need to lock here.
 	return restorable.IsRestoringEnabled()
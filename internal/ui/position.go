@@ -0,0 +1,32 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"math"
+)
+
+// screenPosition converts a position in the window to a position on the game screen.
+//
+// x and y are the position in the window in the game screen's scale, i.e., divided by the screen scale.
+// ox and oy are the screen padding in device pixels, and scale is the actual screen scale
+// including the device scale factor.
+//
+// This is the inverse of the transformation that renders the game screen to the window.
+// The result is floored so that a position on a pixel always maps to the pixel, and positions
+// outside of the screen map to negative values or values beyond the screen size consistently.
+func screenPosition(x, y float64, ox, oy float64, scale float64) (int, int) {
+	return int(math.Floor(x - ox/scale)), int(math.Floor(y - oy/scale))
+}
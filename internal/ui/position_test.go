@@ -0,0 +1,58 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"testing"
+)
+
+func TestScreenPosition(t *testing.T) {
+	cases := []struct {
+		// DeviceX and DeviceY are the position in device pixels in the window.
+		DeviceX float64
+		DeviceY float64
+		Scale   float64
+		OX      float64
+		OY      float64
+		WantX   int
+		WantY   int
+	}{
+		{0, 0, 2, 0, 0, 0, 0},
+		{1, 1, 2, 0, 0, 0, 0},
+		{2, 3, 2, 0, 0, 1, 1},
+		{2, 3, 3, 0, 0, 0, 1},
+		{299, 300, 3, 0, 0, 99, 100},
+		// Each pixel on the screen covers [3n, 3n+3) in the window at scale 3.
+		{5.9, 6, 3, 0, 0, 1, 2},
+
+		// With padding, e.g. in fullscreen mode.
+		{100, 10, 2, 100, 0, 0, 5},
+		{99, 10, 2, 100, 0, -1, 5},
+		{100, 40, 3, 10, 31, 30, 3},
+
+		// Positions outside of the screen are not rounded toward zero.
+		{-1, -1, 2, 0, 0, -1, -1},
+		{-0.5, 0, 3, 0, 0, -1, 0},
+	}
+	for _, c := range cases {
+		// The input package reports positions divided by the screen scale.
+		x, y := c.DeviceX/c.Scale, c.DeviceY/c.Scale
+		gotX, gotY := screenPosition(x, y, c.OX, c.OY, c.Scale)
+		if gotX != c.WantX || gotY != c.WantY {
+			t.Errorf("screenPosition for (%v, %v) with scale %v and padding (%v, %v): got: (%d, %d), want: (%d, %d)",
+				c.DeviceX, c.DeviceY, c.Scale, c.OX, c.OY, gotX, gotY, c.WantX, c.WantY)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+// scaleToFit returns the maximum scale to fit the screen (screenWidth x screenHeight) in the area
+// (width x height) keeping the aspect ratio.
+func scaleToFit(screenWidth, screenHeight int, width, height float64) float64 {
+	sw := width / float64(screenWidth)
+	sh := height / float64(screenHeight)
+	if sw < sh {
+		return sw
+	}
+	return sh
+}
@@ -0,0 +1,53 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"testing"
+)
+
+func TestScaleToFit(t *testing.T) {
+	cases := []struct {
+		ScreenWidth  int
+		ScreenHeight int
+		Width        float64
+		Height       float64
+		Want         float64
+	}{
+		{320, 240, 320, 240, 1},
+		{320, 240, 640, 480, 2},
+		{320, 240, 960, 720, 3},
+		{320, 240, 160, 120, 0.5},
+		// The aspect ratio is kept.
+		{320, 240, 1280, 480, 2},
+		{320, 240, 640, 960, 2},
+		{256, 240, 800, 600, 2.5},
+
+		// Fullscreen on monitors, e.g. the video mode size divided by the device scale.
+		{320, 240, 1920, 1080, 4.5},
+		{320, 240, 2560 / 2.0, 1440 / 2.0, 3},
+		{640, 480, 1366, 768, 1.6},
+	}
+	for _, c := range cases {
+		got := scaleToFit(c.ScreenWidth, c.ScreenHeight, c.Width, c.Height)
+		if got != c.Want {
+			t.Errorf("scaleToFit(%d, %d, %v, %v): got: %v, want: %v", c.ScreenWidth, c.ScreenHeight, c.Width, c.Height, got, c.Want)
+		}
+		// The scaled screen must fit the given size.
+		if w, h := float64(c.ScreenWidth)*got, float64(c.ScreenHeight)*got; w > c.Width || h > c.Height {
+			t.Errorf("scaleToFit(%d, %d, %v, %v): the scaled screen (%v, %v) doesn't fit", c.ScreenWidth, c.ScreenHeight, c.Width, c.Height, w, h)
+		}
+	}
+}
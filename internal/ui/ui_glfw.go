@@ -26,6 +26,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hajimehoshi/ebiten/internal/clock"
 	"github.com/hajimehoshi/ebiten/internal/devicescale"
 	"github.com/hajimehoshi/ebiten/internal/glfw"
 	"github.com/hajimehoshi/ebiten/internal/graphicscommand"
@@ -53,6 +54,7 @@ type userInterface struct {
 	origPosY             int
 	runnableInBackground bool
 	vsync                bool
+	swapInterval         int
 
 	lastActualScale float64
 
@@ -62,6 +64,11 @@ type userInterface struct {
 	initWindowResizable bool
 	initIconImages      []image.Image
 
+	// initWindowWidth and initWindowHeight are the window size specified before Run.
+	// These are 0 when the window size is not specified.
+	initWindowWidth  int
+	initWindowHeight int
+
 	reqWidth  int
 	reqHeight int
 
@@ -75,6 +82,7 @@ var (
 		initCursorVisible:   true,
 		initWindowDecorated: true,
 		vsync:               true,
+		swapInterval:        1,
 	}
 )
 
@@ -258,6 +266,19 @@ func (u *userInterface) setInitIconImages(iconImages []image.Image) {
 	u.m.Unlock()
 }
 
+func (u *userInterface) getInitWindowSize() (int, int) {
+	u.m.Lock()
+	w, h := u.initWindowWidth, u.initWindowHeight
+	u.m.Unlock()
+	return w, h
+}
+
+func (u *userInterface) setInitWindowSize(width, height int) {
+	u.m.Lock()
+	u.initWindowWidth, u.initWindowHeight = width, height
+	u.m.Unlock()
+}
+
 func ScreenSizeInFullscreen() (int, int) {
 	u := currentUI
 	if !u.isRunning() {
@@ -298,6 +319,21 @@ func SetScreenScale(scale float64) bool {
 	return r
 }
 
+func SetWindowSize(width, height int) bool {
+	u := currentUI
+	if !u.isRunning() {
+		// The size is applied when the window is created in Run.
+		u.setInitWindowSize(width, height)
+		return false
+	}
+	r := false
+	_ = mainthread.Run(func() error {
+		r = u.setScreenSize(u.width, u.height, scaleToFit(u.width, u.height, float64(width), float64(height)), u.fullscreen(), u.vsync)
+		return nil
+	})
+	return r
+}
+
 func ScreenScale() float64 {
 	u := currentUI
 	if !u.isRunning() {
@@ -380,6 +416,41 @@ func IsVsyncEnabled() bool {
 	return r
 }
 
+func SetSwapInterval(interval int) {
+	u := currentUI
+	if interval == 0 {
+		SetVsyncEnabled(false)
+		return
+	}
+	if !u.isRunning() {
+		u.m.Lock()
+		u.vsync = true
+		u.swapInterval = interval
+		u.m.Unlock()
+		return
+	}
+	_ = mainthread.Run(func() error {
+		u := currentUI
+		u.m.Lock()
+		u.swapInterval = interval
+		u.m.Unlock()
+		if !u.setScreenSize(u.width, u.height, u.scale, u.fullscreen(), true) && graphicscommand.Driver().IsGL() {
+			glfw.SwapInterval(interval)
+		}
+		return nil
+	})
+}
+
+func SwapInterval() int {
+	u := currentUI
+	u.m.Lock()
+	defer u.m.Unlock()
+	if !u.vsync {
+		return 0
+	}
+	return u.swapInterval
+}
+
 func SetWindowTitle(title string) {
 	if !currentUI.isRunning() {
 		return
@@ -441,13 +512,13 @@ func ScreenPadding() (x0, y0, x1, y1 float64) {
 }
 
 func AdjustedCursorPosition() (x, y int) {
-	return adjustCursorPosition(input.Get().CursorPosition())
+	return adjustCursorPosition(input.Get().CursorPositionInFloat())
 }
 
-func adjustCursorPosition(x, y int) (int, int) {
+func adjustCursorPosition(x, y float64) (int, int) {
 	u := currentUI
 	if !u.isRunning() {
-		return x, y
+		return screenPosition(x, y, 0, 0, 1)
 	}
 	ox, oy, _, _ := ScreenPadding()
 	s := 0.0
@@ -455,7 +526,7 @@ func adjustCursorPosition(x, y int) (int, int) {
 		s = currentUI.actualScreenScale()
 		return nil
 	})
-	return x - int(ox/s), y - int(oy/s)
+	return screenPosition(x, y, ox, oy, s)
 }
 
 func AdjustedTouches() []*input.Touch {
@@ -569,6 +640,12 @@ func DeviceScaleFactor() float64 {
 func Run(width, height int, scale float64, title string, g GraphicsContext, mainloop bool) error {
 	u := currentUI
 	_ = mainthread.Run(func() error {
+		// The graphics driver might be changed after initialize. Specify the client API again.
+		if graphicscommand.Driver().IsGL() {
+			glfw.WindowHint(glfw.ClientAPI, glfw.OpenGLAPI)
+		} else {
+			glfw.WindowHint(glfw.ClientAPI, glfw.NoAPI)
+		}
 		glfw.WindowHint(glfw.ContextVersionMajor, 2)
 		glfw.WindowHint(glfw.ContextVersionMinor, 1)
 
@@ -626,6 +703,11 @@ func Run(width, height int, scale float64, title string, g GraphicsContext, main
 		mx, my := m.GetPos()
 		v := m.GetVideoMode()
 
+		// The window size specified before Run overrides the given scale.
+		if ww, wh := u.getInitWindowSize(); ww > 0 && wh > 0 {
+			scale = scaleToFit(width, height, float64(ww), float64(wh))
+		}
+
 		// The game is in window mode (not fullscreen mode) at the first state.
 		// Don't refer u.initFullscreen here to avoid some GLFW problems.
 		u.setScreenSize(width, height, scale, false, u.vsync)
@@ -678,13 +760,7 @@ func (u *userInterface) getScale() float64 {
 	}
 	if u.fullscreenScale == 0 {
 		v := u.window.GetMonitor().GetVideoMode()
-		sw := float64(v.Width) / glfwScale() / float64(u.width)
-		sh := float64(v.Height) / glfwScale() / float64(u.height)
-		s := sw
-		if s > sh {
-			s = sh
-		}
-		u.fullscreenScale = s
+		u.fullscreenScale = scaleToFit(u.width, u.height, float64(v.Width)/glfwScale(), float64(v.Height)/glfwScale())
 	}
 	return u.fullscreenScale
 }
@@ -798,18 +874,15 @@ func (u *userInterface) loop(g GraphicsContext) error {
 			return err
 		}
 
-		u.m.Lock()
-		vsync := u.vsync
-		u.m.Unlock()
-
 		_ = mainthread.Run(func() error {
-			if !vsync {
-				u.swapBuffers()
-				return nil
-			}
 			u.swapBuffers()
 			return nil
 		})
+
+		// Sleep on this goroutine so that the main thread is not blocked.
+		// With vsync, swapping buffers already waits for the display, and the wait is 0
+		// unless the max FPS is lower than the display's refresh rate.
+		clock.Sleep(clock.FrameWait())
 	}
 }
 
@@ -922,7 +995,10 @@ func (u *userInterface) forceSetScreenSize(width, height int, scale float64, ful
 		// but is this correct? If glfw.SwapInterval(0) and the driver doesn't support triple
 		// buffering, what will happen?
 		if u.vsync {
-			glfw.SwapInterval(1)
+			u.m.Lock()
+			interval := u.swapInterval
+			u.m.Unlock()
+			glfw.SwapInterval(interval)
 		} else {
 			glfw.SwapInterval(0)
 		}
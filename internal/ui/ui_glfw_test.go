@@ -0,0 +1,40 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build darwin freebsd linux windows
+// +build !js
+// +build !android
+// +build !ios
+
+package ui
+
+import (
+	"testing"
+)
+
+func TestSetWindowSizeBeforeRun(t *testing.T) {
+	u := currentUI
+	if u.isRunning() {
+		t.Skip("the game is running")
+	}
+	defer u.setInitWindowSize(0, 0)
+
+	// SetWindowSize before Run must not panic, and the size must be kept for Run.
+	if SetWindowSize(640, 480) {
+		t.Errorf("SetWindowSize before Run: got: true, want: false")
+	}
+	if w, h := u.getInitWindowSize(); w != 640 || h != 480 {
+		t.Errorf("getInitWindowSize(): got: (%d, %d), want: (640, 480)", w, h)
+	}
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/gopherjs/gopherwasm/js"
 
+	"github.com/hajimehoshi/ebiten/internal/clock"
 	"github.com/hajimehoshi/ebiten/internal/devicescale"
 	"github.com/hajimehoshi/ebiten/internal/graphicsdriver/opengl"
 	"github.com/hajimehoshi/ebiten/internal/hooks"
@@ -39,6 +40,7 @@ type userInterface struct {
 	fullscreen           bool
 	runnableInBackground bool
 	vsync                bool
+	swapInterval         int
 
 	sizeChanged bool
 	windowFocus bool
@@ -48,10 +50,11 @@ type userInterface struct {
 }
 
 var currentUI = &userInterface{
-	sizeChanged: true,
-	windowFocus: true,
-	pageVisible: true,
-	vsync:       true,
+	sizeChanged:  true,
+	windowFocus:  true,
+	pageVisible:  true,
+	vsync:        true,
+	swapInterval: 1,
 }
 
 var (
@@ -73,6 +76,11 @@ func SetScreenScale(scale float64) bool {
 	return currentUI.setScreenSize(currentUI.width, currentUI.height, scale, currentUI.fullscreen)
 }
 
+func SetWindowSize(width, height int) bool {
+	u := currentUI
+	return u.setScreenSize(u.width, u.height, scaleToFit(u.width, u.height, float64(width), float64(height)), u.fullscreen)
+}
+
 func ScreenScale() float64 {
 	return currentUI.scale
 }
@@ -101,6 +109,22 @@ func IsVsyncEnabled() bool {
 	return currentUI.vsync
 }
 
+func SetSwapInterval(interval int) {
+	if interval == 0 {
+		currentUI.vsync = false
+		return
+	}
+	currentUI.vsync = true
+	currentUI.swapInterval = interval
+}
+
+func SwapInterval() int {
+	if !currentUI.vsync {
+		return 0
+	}
+	return currentUI.swapInterval
+}
+
 func ScreenPadding() (x0, y0, x1, y1 float64) {
 	return 0, 0, 0, 0
 }
@@ -110,7 +134,7 @@ func adjustPosition(x, y int) (int, int) {
 	x -= rect.Get("left").Int()
 	y -= rect.Get("top").Int()
 	scale := currentUI.getScale()
-	return int(float64(x) / scale), int(float64(y) / scale)
+	return screenPosition(float64(x)/scale, float64(y)/scale, 0, 0, 1)
 }
 
 func AdjustedCursorPosition() (x, y int) {
@@ -175,12 +199,7 @@ func (u *userInterface) getScale() float64 {
 	body := document.Get("body")
 	bw := body.Get("clientWidth").Float()
 	bh := body.Get("clientHeight").Float()
-	sw := bw / float64(u.width)
-	sh := bh / float64(u.height)
-	if sw > sh {
-		return sh
-	}
-	return sw
+	return scaleToFit(u.width, u.height, bw, bh)
 }
 
 func (u *userInterface) actualScreenScale() float64 {
@@ -240,16 +259,29 @@ func (u *userInterface) update(g GraphicsContext) error {
 func (u *userInterface) loop(g GraphicsContext) <-chan error {
 	ch := make(chan error)
 	var cf js.Callback
+	frames := 0
 	f := func([]js.Value) {
+		// requestAnimationFrame is called at every display refresh.
+		// Skip frames to emulate the swap interval.
+		if u.vsync && u.swapInterval > 1 {
+			frames++
+			if frames < u.swapInterval {
+				requestAnimationFrame.Invoke(cf)
+				return
+			}
+			frames = 0
+		}
 		if err := u.update(g); err != nil {
 			ch <- err
 			close(ch)
 			return
 		}
-		if u.vsync {
+		// With vsync, use setTimeout only when the max FPS requires to wait, i.e. the max FPS is
+		// lower than the display's refresh rate.
+		if d := clock.FrameWait(); u.vsync && d == 0 {
 			requestAnimationFrame.Invoke(cf)
 		} else {
-			setTimeout.Invoke(cf, 0)
+			setTimeout.Invoke(cf, d.Seconds()*1000)
 		}
 	}
 	cf = js.NewCallback(f)
@@ -288,6 +288,11 @@ func (u *userInterface) setScreenScale(scale float64) {
 	u.m.Unlock()
 }
 
+func SetWindowSize(width, height int) bool {
+	// Do nothing
+	return false
+}
+
 func ScreenScale() float64 {
 	u := currentUI
 	u.m.RLock()
@@ -313,13 +318,7 @@ func (u *userInterface) updateFullscreenScaleIfNeeded() {
 	if u.fullscreenWidthPx == 0 || u.fullscreenHeightPx == 0 {
 		return
 	}
-	w, h := u.width, u.height
-	scaleX := float64(u.fullscreenWidthPx) / float64(w)
-	scaleY := float64(u.fullscreenHeightPx) / float64(h)
-	scale := scaleX
-	if scale > scaleY {
-		scale = scaleY
-	}
+	scale := scaleToFit(u.width, u.height, float64(u.fullscreenWidthPx), float64(u.fullscreenHeightPx))
 	u.fullscreenScale = scale / getDeviceScale()
 	u.sizeChanged = true
 }
@@ -365,7 +364,7 @@ func (u *userInterface) adjustPosition(x, y int) (int, int) {
 	s := u.scaleImpl()
 	as := s * getDeviceScale()
 	u.m.Unlock()
-	return int(float64(x)/s - ox/as), int(float64(y)/s - oy/as)
+	return screenPosition(float64(x)/s, float64(y)/s, ox, oy, as)
 }
 
 func IsCursorVisible() bool {
@@ -424,6 +423,14 @@ func SetVsyncEnabled(enabled bool) {
 	// Do nothing
 }
 
+func SetSwapInterval(interval int) {
+	// Do nothing
+}
+
+func SwapInterval() int {
+	return 1
+}
+
 func UpdateTouches(touches []*input.Touch) {
 	input.Get().UpdateTouches(touches)
 }
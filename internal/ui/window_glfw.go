@@ -0,0 +1,136 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build darwin freebsd linux windows
+// +build !js
+// +build !android
+// +build !ios
+
+package ui
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/internal/glfw"
+	"github.com/hajimehoshi/ebiten/internal/graphicscommand"
+	"github.com/hajimehoshi/ebiten/internal/mainthread"
+)
+
+// Window represents an additional window.
+//
+// An additional window has its own OpenGL context, that shares the objects like textures with the main
+// window's context. Framebuffer objects are not shared.
+type Window struct {
+	window *glfw.Window
+	closed bool
+
+	m sync.Mutex
+}
+
+// NewWindow creates an additional window.
+//
+// NewWindow must be called while the main window is running.
+func NewWindow(width, height int, scale float64, title string) (*Window, error) {
+	u := currentUI
+	if !u.isRunning() {
+		return nil, errors.New("ui: NewWindow must be called while the game is running")
+	}
+	if !graphicscommand.Driver().IsGL() {
+		return nil, errors.New("ui: additional windows are available only with OpenGL")
+	}
+
+	w := &Window{}
+	if err := mainthread.Run(func() error {
+		glfw.WindowHint(glfw.Decorated, glfw.True)
+		glfw.WindowHint(glfw.Resizable, glfw.False)
+
+		s := scale * glfwScale()
+		window, err := glfw.CreateWindow(int(float64(width)*s), int(float64(height)*s), title, nil, u.window)
+		if err != nil {
+			return err
+		}
+		w.window = window
+
+		// The main window waits for vsync. Don't wait for vsync twice.
+		w.window.MakeContextCurrent()
+		glfw.SwapInterval(0)
+		u.window.MakeContextCurrent()
+
+		w.window.Show()
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Close closes the window.
+func (w *Window) Close() {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	if w.closed {
+		return
+	}
+	_ = mainthread.Run(func() error {
+		w.window.Destroy()
+		return nil
+	})
+	w.closed = true
+}
+
+// IsClosed returns a boolean value indicating whether the window is closed by Close or by the user.
+func (w *Window) IsClosed() bool {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	if w.closed {
+		return true
+	}
+	shouldClose := false
+	_ = mainthread.Run(func() error {
+		shouldClose = w.window.ShouldClose()
+		return nil
+	})
+	return shouldClose
+}
+
+// Present calls f with the window's context and then swaps the window's buffers.
+// f is given the window's framebuffer size.
+//
+// f is not called on the main thread so that f can use the graphics driver.
+// After Present, the main window's context is the current context again.
+func (w *Window) Present(f func(width, height int) error) error {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	if w.closed {
+		return nil
+	}
+
+	var width, height int
+	_ = mainthread.Run(func() error {
+		w.window.MakeContextCurrent()
+		width, height = w.window.GetFramebufferSize()
+		return nil
+	})
+	err := f(width, height)
+	_ = mainthread.Run(func() error {
+		w.window.SwapBuffers()
+		currentUI.window.MakeContextCurrent()
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build darwin freebsd linux windows
+// +build !js
+// +build !android
+// +build !ios
+
+package ui
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/internal/graphicscommand"
+)
+
+func TestNewWindowNotRunning(t *testing.T) {
+	if currentUI.isRunning() {
+		t.Skip("the game is running")
+	}
+	if _, err := NewWindow(320, 240, 1, "Test"); err == nil {
+		t.Errorf("NewWindow must return an error when the game is not running")
+	}
+}
+
+func TestNewWindowUnsupportedDriver(t *testing.T) {
+	if !currentUI.isRunning() {
+		currentUI.setRunning(true)
+		defer currentUI.setRunning(false)
+	}
+
+	if err := graphicscommand.SetDriver("software"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := graphicscommand.SetDriver(""); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// The software driver is not OpenGL. NewWindow must fail before creating a GLFW window.
+	if _, err := NewWindow(320, 240, 1, "Test"); err == nil {
+		t.Errorf("NewWindow must return an error with a non-OpenGL driver")
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build js android ios
+
+package ui
+
+import (
+	"errors"
+)
+
+// Window represents an additional window.
+//
+// Additional windows are not available on browsers and mobiles.
+type Window struct{}
+
+// NewWindow always returns an error on browsers and mobiles.
+func NewWindow(width, height int, scale float64, title string) (*Window, error) {
+	return nil, errors.New("ui: additional windows are not available on this environment")
+}
+
+func (w *Window) Close() {
+}
+
+func (w *Window) IsClosed() bool {
+	return true
+}
+
+func (w *Window) Present(f func(width, height int) error) error {
+	return nil
+}
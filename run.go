@@ -15,10 +15,15 @@
 package ebiten
 
 import (
+	"errors"
 	"image"
+	"math"
 	"sync/atomic"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/internal/clock"
+	"github.com/hajimehoshi/ebiten/internal/graphicscommand"
+	"github.com/hajimehoshi/ebiten/internal/shareable"
 	"github.com/hajimehoshi/ebiten/internal/ui"
 )
 
@@ -32,6 +37,7 @@ const FPS = DefaultTPS
 
 // CurrentFPS returns the current number of FPS (frames per second), that represents
 // how many swapping buffer happens per second.
+// The value is the average over the last second, and is updated every frame.
 //
 // On some environments, CurrentFPS doesn't return a reliable value since vsync doesn't work well there.
 // If you want to measure the application's speed, Use CurrentTPS.
@@ -86,6 +92,20 @@ func IsRunningSlowly() bool {
 	return IsDrawingSkipped()
 }
 
+// IsFrameDropped returns a boolean value indicating whether the previous frame was dropped.
+//
+// A frame is regarded as dropped when the time from the frame before exceeds 1.5 times the target frame time.
+// The target frame time is 1/MaxTPS seconds, but not shorter than 1/60 seconds as a typical display refresh interval.
+// For example, when MaxTPS is 60, a frame that took more than 25 milliseconds is dropped, which means that
+// at least one vsync was missed.
+//
+// This is useful for adaptive quality, e.g., reducing the number of particles when frames are dropped continuously.
+//
+// IsFrameDropped is concurrent-safe.
+func IsFrameDropped() bool {
+	return clock.IsFrameDropped()
+}
+
 var theGraphicsContext atomic.Value
 
 func run(width, height int, scale float64, title string, g *graphicsContext, mainloop bool) error {
@@ -117,6 +137,12 @@ func run(width, height int, scale float64, title string, g *graphicsContext, mai
 // Ebiten tries to call f 60 times a second by default. In other words,
 // TPS (ticks per second) is 60 by default.
 // This is not related to framerate (display's refresh rate).
+// The game loop is a fixed-timestep loop: f is called the number of times that matches the elapsed time,
+// so each call of f can be treated as 1/MaxTPS() seconds regardless of the framerate or vsync.
+// f might be called multiple times in a frame, or not be called in a frame.
+// When a frame takes long and f is called multiple times to catch up, IsDrawingSkipped reports true
+// except for the last call. How long the game catches up is configurable with SetMaxDeltaTime.
+// TPS is configurable with SetMaxTPS.
 //
 // f is not called when the window is in background by default.
 // This setting is configurable with SetRunnableInBackground.
@@ -228,6 +254,10 @@ func SetScreenSize(width, height int) {
 
 // SetScreenScale changes the scale of the screen.
 //
+// SetScreenScale can be called while the game is running, e.g., to let players choose the scale.
+// On windowed mode, the window is resized. A non-integer scale also works: the screen is enlarged
+// smoothly without blurring each pixel.
+//
 // Note that the actual screen is multiplied not only by the given scale but also
 // by the device scale on high-DPI display.
 // If you pass inverse of the device scale,
@@ -242,6 +272,28 @@ func SetScreenScale(scale float64) {
 	ui.SetScreenScale(scale)
 }
 
+// SetWindowSize changes the size of the window.
+//
+// The screen size is kept, and the screen scale is changed so that the screen fits the given size.
+// As the aspect ratio of the screen is kept, the actual window size is the screen size multiplied by
+// the new scale, which can be smaller than the given size in either direction.
+//
+// Unit is device-independent pixel.
+//
+// On fullscreen mode, the new scale is used after the fullscreen mode is exited.
+// SetWindowSize does nothing on mobiles.
+//
+// On desktops, SetWindowSize can be called before Run is called. Then, the given size is applied when
+// the window is created, and the scale passed to Run is ignored.
+//
+// SetWindowSize is concurrent-safe.
+func SetWindowSize(width, height int) {
+	if width <= 0 || height <= 0 {
+		panic("ebiten: width and height must be positive")
+	}
+	ui.SetWindowSize(width, height)
+}
+
 // ScreenScale returns the current screen scale.
 //
 // If Run is not called, this returns 0.
@@ -251,6 +303,136 @@ func ScreenScale() float64 {
 	return ui.ScreenScale()
 }
 
+var isScreenPixelPerfect int32
+
+// SetScreenPixelPerfect sets whether the screen is scaled in the pixel-perfect way.
+//
+// When pixel-perfect scaling is enabled and the actual scale of the screen is an integer,
+// each pixel of the screen image is rendered as an exact block of pixels on the window
+// without any smoothing, e.g., one pixel becomes a 3x3 block at the scale 3.
+// When the actual scale is not an integer, e.g., on a high-DPI display with a fractional
+// device scale factor, the screen is scaled with the same smoothing as when pixel-perfect
+// scaling is disabled, since nearest-neighbor scaling with a fractional scale makes pixels
+// uneven.
+//
+// The actual scale is the screen scale multiplied by the device scale factor, divided by the render scale.
+//
+// The initial value is false.
+//
+// SetScreenPixelPerfect is concurrent-safe.
+func SetScreenPixelPerfect(enabled bool) {
+	v := int32(0)
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&isScreenPixelPerfect, v)
+}
+
+// IsScreenPixelPerfect reports whether the screen is scaled in the pixel-perfect way.
+//
+// IsScreenPixelPerfect is concurrent-safe.
+func IsScreenPixelPerfect() bool {
+	return atomic.LoadInt32(&isScreenPixelPerfect) != 0
+}
+
+const (
+	minRenderScale = 0.25
+	maxRenderScale = 1
+)
+
+var currentRenderScale = math.Float64bits(1)
+
+// RenderScale returns the current render scale.
+//
+// RenderScale is concurrent-safe.
+func RenderScale() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&currentRenderScale))
+}
+
+// SetRenderScale sets the scale of the internal resolution of the screen.
+//
+// When the render scale is less than 1, the screen image passed to the run function is rendered
+// at the render scale times the screen size, and then the result is upscaled to the window.
+// This trades sharpness for fill-rate, and is useful for weak GPUs.
+// The screen image's size and coordinates are not changed regardless of the render scale.
+//
+// The given scale is clamped to the range from 0.25 to 1. The initial value is 1.
+//
+// As everything drawn on the screen is affected, texts and UI become blurry with a small render scale.
+// It is recommended to render UI to a separated image at the full resolution and to
+// adjust the render scale only for the scene.
+//
+// SetRenderScale is concurrent-safe.
+func SetRenderScale(scale float64) {
+	if math.IsNaN(scale) {
+		panic("ebiten: scale must not be NaN")
+	}
+	if scale < minRenderScale {
+		scale = minRenderScale
+	}
+	if scale > maxRenderScale {
+		scale = maxRenderScale
+	}
+	atomic.StoreUint64(&currentRenderScale, math.Float64bits(scale))
+}
+
+// Sync flushes all the queued drawing commands and waits for the GPU to complete them
+// (e.g., glFinish on OpenGL).
+//
+// Sync is useful for precise benchmarking of the GPU.
+//
+// Sync stalls the rendering pipeline. Do not call Sync in the regular game loop.
+//
+// Sync does nothing before the main loop (ebiten.Run) starts, as there are no drawing commands
+// that can be flushed yet.
+func Sync() {
+	if theGraphicsContext.Load() == nil {
+		return
+	}
+	shareable.Finish()
+}
+
+var shadersPrewarmRequested int32
+
+// PrewarmShaders requests to compile the shaders for rendering in advance.
+//
+// Some drivers compile shader variants lazily at the first draw call with a new state, and
+// this can cause a hitch at the first frame where a new filter is used.
+// PrewarmShaders issues tiny dummy draw calls with all the filters, address modes and composite modes,
+// and with the user-defined shaders, so that such compilation happens up front.
+// The custom composite modes are the ones created by CustomCompositeMode before the prewarming,
+// and the user-defined shaders are the ones created by NewShader and not disposed before the prewarming.
+// Call PrewarmShaders again after creating new ones to prewarm them.
+//
+// The prewarming is done at the start of the next frame, or the first frame if the main loop
+// has not started yet. This adds some time to that frame.
+//
+// PrewarmShaders is concurrent-safe, and can be called before Run.
+func PrewarmShaders() {
+	atomic.StoreInt32(&shadersPrewarmRequested, 1)
+}
+
+// SetGraphicsDriver selects the graphics driver by name.
+//
+// The available names are:
+//
+//     "opengl":   OpenGL (OpenGL ES on mobiles, WebGL on browsers)
+//     "metal":    Metal (macOS only)
+//     "software": A slow renderer on the CPU. Nothing is presented on the window so far.
+//     "":         The platform's default driver
+//
+// The default driver is Metal on macOS where Metal is available, and OpenGL otherwise.
+//
+// SetGraphicsDriver returns an error if the name is unknown or the driver is not available on the platform.
+//
+// SetGraphicsDriver must be called before Run. SetGraphicsDriver returns an error after Run is called.
+func SetGraphicsDriver(name string) error {
+	if theGraphicsContext.Load() != nil {
+		return errors.New("ebiten: SetGraphicsDriver must be called before Run")
+	}
+	return graphicscommand.SetDriver(name)
+}
+
 // IsCursorVisible returns a boolean value indicating whether
 // the cursor is visible or not.
 //
@@ -291,7 +473,8 @@ func IsFullscreen() bool {
 // to fit with the monitor. The current scale value is ignored.
 //
 // On desktops, Ebiten uses 'windowed' fullscreen mode, which doesn't change
-// your monitor's resolution.
+// your monitor's resolution. The monitor that the window currently belongs to is used.
+// The screen size is kept, and the images are not recreated by switching the mode.
 //
 // On browsers, the game screen is resized to fit with the body element (client) size.
 // Additionally, the game screen is automatically resized when the body element is resized.
@@ -434,6 +617,7 @@ func IsVsyncEnabled() bool {
 // If false, the game ignores the display's refresh rate.
 // The initial value is true.
 // By disabling vsync, the game works more efficiently but consumes more CPU.
+// To limit the framerate, use SetMaxFPS.
 //
 // Note that the state doesn't affect TPS (ticks per second, i.e. how many the run function is
 // updated per second).
@@ -445,6 +629,74 @@ func SetVsyncEnabled(enabled bool) {
 	ui.SetVsyncEnabled(enabled)
 }
 
+// MaxFPS returns the current maximum FPS set by SetMaxFPS.
+//
+// MaxFPS is concurrent-safe.
+func MaxFPS() int {
+	return clock.MaxFPS()
+}
+
+// SetMaxFPS sets the maximum FPS (frames per second).
+//
+// When vsync is disabled, the game renders frames as fast as possible and busy-loops by default.
+// SetMaxFPS limits the framerate by waiting between frames instead, which is useful for a custom
+// frame limiter or to save CPU. The frames are scheduled at fixed intervals from the first frame,
+// so the errors of the waits don't accumulate. The last sub-millisecond of each wait is a
+// spin-wait to compensate the OS's timer resolution.
+//
+// When vsync is enabled, SetMaxFPS works only when fps is lower than the display's refresh rate.
+// Each frame is still presented at the display's refresh, so the framerate averages fps while
+// each frame is shown for a whole number of refreshes.
+//
+// SetMaxFPS limits only the framerate. To limit the update rate, use SetMaxTPS.
+//
+// If fps is 0, FPS is not limited. The initial value is 0.
+// If fps is negative, SetMaxFPS panics.
+//
+// SetMaxFPS does nothing on mobiles so far.
+//
+// SetMaxFPS is concurrent-safe.
+func SetMaxFPS(fps int) {
+	if fps < 0 {
+		panic("ebiten: fps must be >= 0")
+	}
+	clock.SetMaxFPS(fps)
+}
+
+// SwapInterval returns the current swap interval, that represents how many display refreshes
+// happen per swapping buffers.
+// SwapInterval returns 0 if vsync is disabled.
+//
+// SwapInterval is concurrent-safe.
+func SwapInterval() int {
+	return ui.SwapInterval()
+}
+
+// SetSwapInterval sets the swap interval, that represents how many display refreshes
+// happen per swapping buffers.
+// For example, 2 means that the game renders at half of the display's refresh rate
+// (e.g., 30 FPS on a 60 Hz display), which can save power.
+//
+// 0 disables vsync as SetVsyncEnabled(false) does.
+// A positive value enables vsync. SetVsyncEnabled(true) doesn't reset the swap interval.
+// The initial value is 1.
+// If interval is negative, SetSwapInterval panics.
+//
+// On desktops, intervals more than 1 depend on the graphics driver, and might be treated as 1.
+// On browsers, intervals more than 1 are emulated by skipping animation frames.
+// SetSwapInterval does nothing on mobiles so far.
+//
+// The swap interval doesn't affect TPS. With a swap interval of 2 at 60 TPS on a 60 Hz display,
+// the run function is called twice per frame and IsDrawingSkipped returns true for the first call.
+//
+// SetSwapInterval is concurrent-safe.
+func SetSwapInterval(interval int) {
+	if interval < 0 {
+		panic("ebiten: interval must be >= 0")
+	}
+	ui.SetSwapInterval(interval)
+}
+
 // MaxTPS returns the current maximum TPS.
 //
 // MaxTPS is concurrent-safe.
@@ -454,6 +706,7 @@ func MaxTPS() int {
 
 // CurrentTPS returns the current TPS (ticks per second),
 // that represents how many update function is called in a second.
+// The value is the average over the last second, and is updated every frame.
 //
 // CurrentTPS is concurrent-safe.
 func CurrentTPS() float64 {
@@ -477,3 +730,48 @@ func SetMaxTPS(tps int) {
 	}
 	atomic.StoreInt32(&currentMaxTPS, int32(tps))
 }
+
+// DeltaTime returns the elapsed time that the current call of the updating function represents,
+// measured with the system clock.
+//
+// The elapsed time since the previous frame that called the updating function is divided by the number
+// of calls in the current frame, so the sum of DeltaTime over the calls matches the elapsed time.
+// This is useful for frame-rate-independent motion without assuming the TPS.
+// Note that DeltaTime fluctuates with the system clock, while each call is treated as 1/MaxTPS()
+// seconds by the game loop.
+//
+// For the first frame, DeltaTime returns 1/MaxTPS() seconds (1/60 seconds if TPS is uncapped).
+// The elapsed time is clamped by MaxDeltaTime, or 5/60 seconds if MaxDeltaTime is 0,
+// so that resuming the game, e.g. from background, doesn't report a huge delta.
+//
+// DeltaTime is concurrent-safe.
+func DeltaTime() time.Duration {
+	return clock.DeltaTime()
+}
+
+// MaxDeltaTime returns the current maximum delta time.
+//
+// MaxDeltaTime is concurrent-safe.
+func MaxDeltaTime() time.Duration {
+	return clock.MaxDeltaTime()
+}
+
+// SetMaxDeltaTime sets the maximum time that is simulated in one frame.
+//
+// When a frame takes long, e.g., due to loading resources, the updating function is called multiple times
+// to catch up with the system clock. SetMaxDeltaTime limits the time to catch up: the time exceeding d is
+// dropped instead of being simulated, then the updating function is called at most d * MaxTPS() times in a frame.
+// This prevents the game from getting slower and slower by too many catch-up updates.
+//
+// If d is 0, the default policy is used: when a frame takes too long, the game time is synced with the
+// system clock and the updating function is called only once. The initial value is 0.
+//
+// If d is negative, SetMaxDeltaTime panics.
+//
+// SetMaxDeltaTime is concurrent-safe.
+func SetMaxDeltaTime(d time.Duration) {
+	if d < 0 {
+		panic("ebiten: d must be >= 0")
+	}
+	clock.SetMaxDeltaTime(d)
+}
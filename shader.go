@@ -0,0 +1,233 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/internal/graphics"
+	"github.com/hajimehoshi/ebiten/internal/graphicscommand"
+)
+
+// Shader represents a user-defined shader.
+//
+// A shader customizes the final color of each pixel rendered by DrawImage or DrawTriangles.
+// The built-in rendering, e.g., filtering, color matrices and vertex colors, is done first,
+// and then the user-defined shader is applied.
+//
+// Note that this API is experimental.
+type Shader struct {
+	shader   *graphicscommand.Shader
+	disposed bool
+}
+
+// NewShader creates a new shader with a GLSL source.
+//
+// src must define a GLSL function 'vec4 shade(vec4 color)'.
+// shade takes a color in straight alpha after applying the color matrix and the vertex colors,
+// and returns a new color in straight alpha. The returned color is clamped to [0, 1].
+//
+// src can declare uniform variables of the types float, vec2, vec3, vec4 and mat4. The values of
+// the uniform variables are specified by DrawImageOptions.Uniforms or DrawTrianglesOptions.Uniforms.
+// A declared uniform variable that is not used in shade can be optimized out by the driver.
+// Such a variable is ignored.
+//
+// src must be valid in both GLSL and GLSL ES 1.0, and must not use the reserved keywords in any
+// versions of GLSL like 'filter'.
+// Identifiers in the built-in shader cannot be used as identifiers, and it is recommended
+// to add a prefix to the names in src.
+//
+// NewShader returns an error if src is invalid, e.g., src doesn't define shade or uses a reserved keyword.
+// When the game is running, NewShader also compiles src and returns an error with the driver's log if
+// compiling or linking fails. When NewShader is called before the game starts, src is compiled at the
+// first draw call with the shader, and an error at compiling src is reported as an error of Run.
+// Then, it is recommended to create shaders while the game is running, e.g., in the first update.
+//
+// User-defined shaders are available only on OpenGL so far. NewShader returns an error on the other
+// graphics drivers.
+func NewShader(src string) (*Shader, error) {
+	s, err := graphicscommand.NewShader(src)
+	if err != nil {
+		return nil, err
+	}
+	shader := &Shader{
+		shader: s,
+	}
+	addShader(s)
+	runtime.SetFinalizer(shader, (*Shader).Dispose)
+	return shader, nil
+}
+
+var (
+	// theShaders is the set of the internal shaders that are not disposed, for PrewarmShaders.
+	//
+	// theShaders doesn't refer to *Shader so that an unreachable *Shader is disposed by its finalizer,
+	// and then the internal shader is removed from theShaders.
+	theShaders  = map[*graphicscommand.Shader]struct{}{}
+	theShadersM sync.Mutex
+)
+
+func addShader(s *graphicscommand.Shader) {
+	theShadersM.Lock()
+	defer theShadersM.Unlock()
+	theShaders[s] = struct{}{}
+}
+
+func removeShader(s *graphicscommand.Shader) {
+	theShadersM.Lock()
+	defer theShadersM.Unlock()
+	delete(theShaders, s)
+}
+
+// shaders returns the internal shaders that are not disposed.
+func shaders() []*graphicscommand.Shader {
+	theShadersM.Lock()
+	defer theShadersM.Unlock()
+	ss := make([]*graphicscommand.Shader, 0, len(theShaders))
+	for s := range theShaders {
+		ss = append(ss, s)
+	}
+	return ss
+}
+
+// Reload replaces the shader's source with src, e.g., to iterate a shader without restarting the game.
+//
+// src is validated and compiled in the same way as NewShader before the shader is replaced.
+// If src is invalid or fails to compile, Reload returns an error and the shader keeps the previous
+// program. Otherwise, the following drawing calls with the shader use src, and the previous program
+// is disposed after the drawing calls before Reload.
+//
+// As with NewShader, when Reload is called before the game starts, src is compiled at the first draw call.
+//
+// Reloading a disposed shader panics.
+//
+// Note that this API is experimental.
+func (s *Shader) Reload(src string) error {
+	if s.disposed {
+		panic("ebiten: the shader to reload must not be disposed")
+	}
+	// graphicscommand.NewShader compiles src when possible. Replace the shader only after it succeeds.
+	ns, err := graphicscommand.NewShader(src)
+	if err != nil {
+		return err
+	}
+	// Disposing is queued after the drawing commands with the previous shader.
+	removeShader(s.shader)
+	s.shader.Dispose()
+	s.shader = ns
+	addShader(ns)
+	return nil
+}
+
+// Dispose disposes the shader.
+//
+// Drawing with a disposed shader panics.
+//
+// Dispose always returns nil.
+func (s *Shader) Dispose() error {
+	if s.disposed {
+		return nil
+	}
+	removeShader(s.shader)
+	s.shader.Dispose()
+	s.disposed = true
+	runtime.SetFinalizer(s, nil)
+	return nil
+}
+
+// checkUniforms panics if uniforms doesn't match with the uniform variables declared in s.
+// checkUniforms does nothing if s is nil.
+func (s *Shader) checkUniforms(uniforms graphics.Uniforms) {
+	if s == nil {
+		return
+	}
+	sizes := s.shader.UniformSizes()
+	for name, v := range uniforms {
+		size, ok := sizes[name]
+		if !ok {
+			panic(fmt.Sprintf("ebiten: uniform %q is not declared in the shader", name))
+		}
+		if len(v) != size {
+			panic(fmt.Sprintf("ebiten: uniform %q is declared as %s but the given value is %s", name, uniformTypeName(size), uniformTypeName(len(v))))
+		}
+	}
+}
+
+// uniformTypeName returns the GLSL type name of a uniform variable with the given number of float values.
+func uniformTypeName(size int) string {
+	switch size {
+	case 1:
+		return "float"
+	case 2:
+		return "vec2"
+	case 3:
+		return "vec3"
+	case 4:
+		return "vec4"
+	case 16:
+		return "mat4"
+	}
+	return fmt.Sprintf("(%d floats)", size)
+}
+
+// graphicsShader returns the internal shader of s, or nil if s is nil.
+func (s *Shader) graphicsShader() *graphicscommand.Shader {
+	if s == nil {
+		return nil
+	}
+	if s.disposed {
+		panic("ebiten: the given shader must not be disposed")
+	}
+	return s.shader
+}
+
+// Uniforms represents values of uniform variables of a shader, keyed by the variable names.
+//
+// The type of a value must be float32 or float64 for float, or []float32 with 2, 3, 4 or 16 elements
+// for vec2, vec3, vec4 or mat4 respectively. The elements of mat4 are in column-major order as GLSL.
+//
+// Note that this API is experimental.
+type Uniforms map[string]interface{}
+
+// graphicsUniforms converts u to the internal representation.
+// The returned values are copies so that the caller can modify u after drawing.
+func (u Uniforms) graphicsUniforms() graphics.Uniforms {
+	if len(u) == 0 {
+		return nil
+	}
+	us := make(graphics.Uniforms, len(u))
+	for name, v := range u {
+		switch v := v.(type) {
+		case float32:
+			us[name] = []float32{v}
+		case float64:
+			us[name] = []float32{float32(v)}
+		case []float32:
+			switch len(v) {
+			case 2, 3, 4, 16:
+			default:
+				panic(fmt.Sprintf("ebiten: the length of uniform %q must be 2, 3, 4 or 16 but %d", name, len(v)))
+			}
+			vs := make([]float32, len(v))
+			copy(vs, v)
+			us[name] = vs
+		default:
+			panic(fmt.Sprintf("ebiten: the type of uniform %q must be float32, float64 or []float32 but %T", name, v))
+		}
+	}
+	return us
+}
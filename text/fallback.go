@@ -0,0 +1,219 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"image"
+	"sync"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// GlyphChecker is the interface that reports whether a font face has a glyph for a rune.
+//
+// Some font faces report that they have glyphs for any runes, e.g., faces that render
+// a replacement glyph like '.notdef' instead. Such faces should implement GlyphChecker
+// to work with NewFallbackFace correctly.
+type GlyphChecker interface {
+	HasGlyph(r rune) bool
+}
+
+// hasGlyph reports whether face has a glyph for r.
+//
+// If face implements GlyphChecker, HasGlyph is used. Otherwise, ok of GlyphAdvance is used.
+func hasGlyph(face font.Face, r rune) bool {
+	if c, ok := face.(GlyphChecker); ok {
+		return c.HasGlyph(r)
+	}
+	_, ok := face.GlyphAdvance(r)
+	return ok
+}
+
+type trueTypeFace struct {
+	font.Face
+	f *truetype.Font
+}
+
+// NewTrueTypeFace returns a new font.Face for the TrueType font f.
+//
+// NewTrueTypeFace is same as truetype.NewFace except that the returned face implements
+// GlyphChecker. A face created by truetype.NewFace doesn't report missing glyphs,
+// so use NewTrueTypeFace for faces passed to NewFallbackFace.
+func NewTrueTypeFace(f *truetype.Font, opts *truetype.Options) font.Face {
+	return &trueTypeFace{
+		Face: truetype.NewFace(f, opts),
+		f:    f,
+	}
+}
+
+// HasGlyph implements GlyphChecker.
+func (t *trueTypeFace) HasGlyph(r rune) bool {
+	return t.f.Index(r) != 0
+}
+
+// noFace is a face index that represents no face has the glyph.
+const noFace = -1
+
+type fallbackFace struct {
+	faces   []font.Face
+	metrics font.Metrics
+
+	// indices is a cache of the indices of faces that provide glyphs.
+	indices map[rune]int
+
+	m sync.Mutex
+}
+
+// NewFallbackFace returns a font.Face that renders each rune with the first face that has
+// the glyph in faces.
+//
+// If none of faces has the glyph, a box (so-called 'tofu') is rendered instead.
+// Whether a face has a glyph or not is determined by HasGlyph if the face implements
+// GlyphChecker, or by ok of GlyphAdvance otherwise.
+//
+// Looking up a face for a rune calls HasGlyph or GlyphAdvance of each face in order until
+// the glyph is found, so the cost is proportional to the number of faces in the worst case.
+// The result is cached per rune, and the later lookups for the same rune cost only a map
+// access. The cache is never cleared.
+//
+// Kerning is applied only between two runes provided by the same face.
+// The metrics of the returned face are the maximum values among faces so that any glyph fits
+// in a line.
+//
+// Close of the returned face doesn't close the given faces.
+//
+// NewFallbackFace panics if faces is empty.
+func NewFallbackFace(faces ...font.Face) font.Face {
+	if len(faces) == 0 {
+		panic("text: faces must not be empty")
+	}
+
+	m := faces[0].Metrics()
+	for _, f := range faces[1:] {
+		fm := f.Metrics()
+		if m.Height < fm.Height {
+			m.Height = fm.Height
+		}
+		if m.Ascent < fm.Ascent {
+			m.Ascent = fm.Ascent
+		}
+		if m.Descent < fm.Descent {
+			m.Descent = fm.Descent
+		}
+	}
+
+	fs := make([]font.Face, len(faces))
+	copy(fs, faces)
+	return &fallbackFace{
+		faces:   fs,
+		metrics: m,
+		indices: map[rune]int{},
+	}
+}
+
+func (f *fallbackFace) faceIndex(r rune) int {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if i, ok := f.indices[r]; ok {
+		return i
+	}
+	i := noFace
+	for j, face := range f.faces {
+		if hasGlyph(face, r) {
+			i = j
+			break
+		}
+	}
+	f.indices[r] = i
+	return i
+}
+
+// tofuBounds returns the bounds of a tofu glyph relative to the dot, and its advance.
+func (f *fallbackFace) tofuBounds() (image.Rectangle, fixed.Int26_6) {
+	a := f.metrics.Ascent.Ceil()
+	w, h := a/2, a*3/4
+	if w < 3 {
+		w = 3
+	}
+	if h < 3 {
+		h = 3
+	}
+	return image.Rect(1, -h, 1+w, 0), fixed.I(w + 2)
+}
+
+// Close implements font.Face.
+func (f *fallbackFace) Close() error {
+	return nil
+}
+
+// Glyph implements font.Face.
+func (f *fallbackFace) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	if i := f.faceIndex(r); i != noFace {
+		return f.faces[i].Glyph(dot, r)
+	}
+
+	b, advance := f.tofuBounds()
+	alpha := image.NewAlpha(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for j := 0; j < b.Dy(); j++ {
+		for i := 0; i < b.Dx(); i++ {
+			if i == 0 || j == 0 || i == b.Dx()-1 || j == b.Dy()-1 {
+				alpha.Pix[j*alpha.Stride+i] = 0xff
+			}
+		}
+	}
+	dr = b.Add(image.Pt(dot.X.Round(), dot.Y.Round()))
+	return dr, alpha, image.Point{}, advance, true
+}
+
+// GlyphBounds implements font.Face.
+func (f *fallbackFace) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	if i := f.faceIndex(r); i != noFace {
+		return f.faces[i].GlyphBounds(r)
+	}
+
+	b, advance := f.tofuBounds()
+	bounds = fixed.Rectangle26_6{
+		Min: fixed.P(b.Min.X, b.Min.Y),
+		Max: fixed.P(b.Max.X, b.Max.Y),
+	}
+	return bounds, advance, true
+}
+
+// GlyphAdvance implements font.Face.
+func (f *fallbackFace) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
+	if i := f.faceIndex(r); i != noFace {
+		return f.faces[i].GlyphAdvance(r)
+	}
+
+	_, advance = f.tofuBounds()
+	return advance, true
+}
+
+// Kern implements font.Face.
+func (f *fallbackFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	i0, i1 := f.faceIndex(r0), f.faceIndex(r1)
+	if i0 == noFace || i0 != i1 {
+		return 0
+	}
+	return f.faces[i0].Kern(r0, r1)
+}
+
+// Metrics implements font.Face.
+func (f *fallbackFace) Metrics() font.Metrics {
+	return f.metrics
+}
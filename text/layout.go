@@ -0,0 +1,177 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// HorizontalAlign represents how lines are aligned horizontally.
+type HorizontalAlign int
+
+const (
+	// HorizontalAlignLeft aligns the left edges of lines with x.
+	HorizontalAlignLeft HorizontalAlign = iota
+
+	// HorizontalAlignCenter aligns the centers of lines with x.
+	HorizontalAlignCenter
+
+	// HorizontalAlignRight aligns the right edges of lines with x.
+	HorizontalAlignRight
+)
+
+// VerticalAlign represents how a text is aligned vertically.
+type VerticalAlign int
+
+const (
+	// VerticalAlignBaseline aligns the baseline of the first line with y.
+	VerticalAlignBaseline VerticalAlign = iota
+
+	// VerticalAlignTop aligns the top of the first line, that is the ascent, with y.
+	VerticalAlignTop
+
+	// VerticalAlignMiddle aligns the middle of the whole text with y.
+	VerticalAlignMiddle
+
+	// VerticalAlignBottom aligns the bottom of the last line, that is the descent, with y.
+	VerticalAlignBottom
+)
+
+// DrawOptions represents options to render a text.
+//
+// The zero value represents the same rendering as Draw except that newlines break lines.
+type DrawOptions struct {
+	// MaxWidth is the maximum width of lines.
+	// If MaxWidth is positive, lines are broken in the same way as Wrap.
+	// If MaxWidth is 0 or negative, lines are broken only at newlines.
+	MaxWidth int
+
+	// HorizontalAlign is the horizontal alignment of each line.
+	// The default (zero) value is HorizontalAlignLeft.
+	HorizontalAlign HorizontalAlign
+
+	// VerticalAlign is the vertical alignment of the whole text.
+	// The default (zero) value is VerticalAlignBaseline.
+	VerticalAlign VerticalAlign
+}
+
+type layoutLine struct {
+	runes []rune
+
+	// x and y represent the dot position of the line.
+	x fixed.Int26_6
+	y fixed.Int26_6
+}
+
+// layout breaks the text into lines and calculates the lines' positions.
+// layout returns the lines and the bounds of the whole text.
+func layout(face font.Face, text string, x, y int, options *DrawOptions) ([]layoutLine, image.Rectangle) {
+	if options == nil {
+		options = &DrawOptions{}
+	}
+
+	m := face.Metrics()
+	runes := wrapLines(face, text, options.MaxWidth)
+
+	// The height from the ascent of the first line to the descent of the last line.
+	h := m.Height*fixed.Int26_6(len(runes)-1) + m.Ascent + m.Descent
+
+	fy := fixed.I(y)
+	switch options.VerticalAlign {
+	case VerticalAlignBaseline:
+	case VerticalAlignTop:
+		fy += m.Ascent
+	case VerticalAlignMiddle:
+		fy += m.Ascent - h/2
+	case VerticalAlignBottom:
+		fy += m.Ascent - h
+	default:
+		panic("text: invalid vertical alignment")
+	}
+
+	lines := make([]layoutLine, len(runes))
+	minX, maxX := fixed.I(x), fixed.I(x)
+	for i, r := range runes {
+		w := measure(face, r)
+		fx := fixed.I(x)
+		switch options.HorizontalAlign {
+		case HorizontalAlignLeft:
+		case HorizontalAlignCenter:
+			fx -= w / 2
+		case HorizontalAlignRight:
+			fx -= w
+		default:
+			panic("text: invalid horizontal alignment")
+		}
+		lines[i] = layoutLine{
+			runes: r,
+			x:     fx,
+			y:     fy + m.Height*fixed.Int26_6(i),
+		}
+		if fx < minX {
+			minX = fx
+		}
+		if fx+w > maxX {
+			maxX = fx + w
+		}
+	}
+
+	top := fy - m.Ascent
+	b := image.Rect(minX.Floor(), top.Floor(), maxX.Ceil(), (top + h).Ceil())
+	return lines, b
+}
+
+// DrawWithOptions draws a given text on a given destination image dst with the given options.
+//
+// Unlike Draw, newlines ('\n') in text break lines. The line height is the height of the face's metrics.
+// (x, y) represents the position that the text is aligned with. See DrawOptions for details.
+// If options is nil, the default options are used, where (x, y) represents a 'dot' (period) position of the first line.
+//
+// DrawWithOptions returns the bounds of the drawn text on dst. The bounds' vertical range is from the ascent of
+// the first line to the descent of the last line. The bounds are useful for layouting.
+//
+// DrawWithOptions is concurrent-safe.
+func DrawWithOptions(dst *ebiten.Image, text string, face font.Face, x, y int, clr color.Color, options *DrawOptions) image.Rectangle {
+	textM.Lock()
+	defer textM.Unlock()
+
+	lines, b := layout(face, text, x, y, options)
+	colorm := colorToColorM(clr)
+	for _, l := range lines {
+		drawRunes(dst, l.runes, face, l.x, l.y, colorm)
+	}
+	return b
+}
+
+// DrawWrapped draws a given text on a given destination image dst, breaking lines so that each line fits in maxWidth.
+//
+// (x, y) represents a 'dot' (period) position of the first line.
+// The lines are broken in the same way as Wrap, and the line height is the height of the face's metrics.
+//
+// DrawWrapped returns the bounds of the drawn text on dst. The bounds' vertical range is from the ascent of the
+// first line to the descent of the last line. The bounds are useful for layouting.
+//
+// DrawWrapped is concurrent-safe.
+func DrawWrapped(dst *ebiten.Image, text string, face font.Face, x, y int, maxWidth int, clr color.Color) image.Rectangle {
+	return DrawWithOptions(dst, text, face, x, y, clr, &DrawOptions{
+		MaxWidth: maxWidth,
+	})
+}
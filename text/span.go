@@ -0,0 +1,102 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Span represents a part of a text with its own style.
+type Span struct {
+	// Text is the text of the span.
+	Text string
+
+	// Face is the font face of the span.
+	// If Face is nil, the default face given to DrawSpans is used.
+	Face font.Face
+
+	// Color is the color of the span.
+	// If Color is nil, the default color given to DrawSpans is used.
+	Color color.Color
+}
+
+// DrawSpans draws the given spans in one line on a given destination image dst.
+//
+// face and clr are the default font face and the default color for the spans that don't specify them.
+// (x, y) represents a 'dot' (period) position.
+//
+// The glyphs are placed in the same way as Draw regardless of the colors:
+// drawing the spans with the same face results in the same positions as drawing the concatenated text.
+// Different faces, e.g., with different sizes, share the same baseline.
+//
+// DrawSpans returns the bounds of the drawn spans on dst. The bounds' vertical range is from the largest ascent
+// to the largest descent of the used faces.
+//
+// DrawSpans is concurrent-safe.
+func DrawSpans(dst *ebiten.Image, spans []Span, face font.Face, x, y int, clr color.Color) image.Rectangle {
+	textM.Lock()
+	defer textM.Unlock()
+
+	fx := fixed.I(x)
+	fy := fixed.I(y)
+	var ascent, descent fixed.Int26_6
+
+	var prevFace font.Face
+	prevR := rune(-1)
+	for _, s := range spans {
+		f := s.Face
+		if f == nil {
+			f = face
+		}
+		c := s.Color
+		if c == nil {
+			c = clr
+		}
+
+		m := f.Metrics()
+		if ascent < m.Ascent {
+			ascent = m.Ascent
+		}
+		if descent < m.Descent {
+			descent = m.Descent
+		}
+
+		// Kerning is applied only between the runes of the same face.
+		if f != prevFace {
+			prevR = -1
+		}
+
+		runes := []rune(s.Text)
+		if len(runes) == 0 {
+			continue
+		}
+		if prevR >= 0 {
+			fx += f.Kern(prevR, runes[0])
+		}
+		drawRunes(dst, runes, f, fx, fy, colorToColorM(c))
+		fx += measure(f, runes)
+
+		prevFace = f
+		prevR = runes[len(runes)-1]
+	}
+
+	return image.Rect(x, (fy - ascent).Floor(), fx.Ceil(), (fy + descent).Ceil())
+}
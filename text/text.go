@@ -270,23 +270,21 @@ func colorToColorM(clr color.Color) ebiten.ColorM {
 // Draw is concurrent-safe.
 func Draw(dst *ebiten.Image, text string, face font.Face, x, y int, clr color.Color) {
 	textM.Lock()
+	drawRunes(dst, []rune(text), face, fixed.I(x), fixed.I(y), colorToColorM(clr))
+	textM.Unlock()
+}
 
-	fx := fixed.I(x)
+// drawRunes draws runes on dst from the dot position (x, y) in one line.
+func drawRunes(dst *ebiten.Image, runes []rune, face font.Face, x, y fixed.Int26_6, colorm ebiten.ColorM) {
 	prevR := rune(-1)
-
-	runes := []rune(text)
 	glyphImgs := getGlyphImages(face, runes)
-	colorm := colorToColorM(clr)
-
 	for i, r := range runes {
 		if prevR >= 0 {
-			fx += face.Kern(prevR, r)
+			x += face.Kern(prevR, r)
 		}
-		drawGlyph(dst, face, r, glyphImgs[i], fx, fixed.I(y), colorm)
-		fx += glyphAdvance(face, r)
+		drawGlyph(dst, face, r, glyphImgs[i], x, y, colorm)
+		x += glyphAdvance(face, r)
 
 		prevR = r
 	}
-
-	textM.Unlock()
 }
@@ -16,11 +16,15 @@ package text_test
 
 import (
 	"errors"
+	"image"
 	"image/color"
 	"os"
+	"reflect"
 	"testing"
 
 	"github.com/hajimehoshi/bitmapfont"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
 
 	"github.com/hajimehoshi/ebiten"
 	"github.com/hajimehoshi/ebiten/internal/testflock"
@@ -68,3 +72,207 @@ func TestTextColor(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestWrap(t *testing.T) {
+	cases := []struct {
+		Text     string
+		MaxWidth int
+		Want     []string
+	}{
+		{
+			Text:     "The quick brown fox jumps over the lazy dog",
+			MaxWidth: 60,
+			Want:     []string{"The quick", "brown fox", "jumps over", "the lazy", "dog"},
+		},
+		{
+			Text:     "Hello   \nWorld  ",
+			MaxWidth: 60,
+			Want:     []string{"Hello", "World"},
+		},
+		{
+			Text:     "abcdefghijklmnopqrstuvwxyz",
+			MaxWidth: 60,
+			Want:     []string{"abcdefghij", "klmnopqrst", "uvwxyz"},
+		},
+		{
+			Text:     "吾輩は猫である。",
+			MaxWidth: 60,
+			Want:     []string{"吾輩は猫で", "ある。"},
+		},
+		{
+			Text:     "a\n\nb",
+			MaxWidth: 60,
+			Want:     []string{"a", "", "b"},
+		},
+	}
+	for _, c := range cases {
+		got := Wrap(c.Text, bitmapfont.Gothic12r, c.MaxWidth)
+		if !reflect.DeepEqual(got, c.Want) {
+			t.Errorf("Wrap(%q, %d): got %q; want %q", c.Text, c.MaxWidth, got, c.Want)
+		}
+	}
+}
+
+func TestDrawWrapped(t *testing.T) {
+	img, _ := ebiten.NewImage(100, 100, ebiten.FilterNearest)
+	got := DrawWrapped(img, "The quick brown fox jumps over the lazy dog", bitmapfont.Gothic12r, 10, 20, 60, color.White)
+	// Gothic12r's advance is 6, height is 16, ascent is 12 and descent is 4.
+	want := image.Rect(10, 20-12, 10+60, 20+16*4+4)
+	if got != want {
+		t.Errorf("DrawWrapped: got %v; want %v", got, want)
+	}
+}
+
+func TestDrawWithOptionsAlignment(t *testing.T) {
+	const text = "a\nabc"
+	cases := []struct {
+		Align      HorizontalAlign
+		X          int
+		WantLineXs []int
+		WantBounds image.Rectangle
+	}{
+		{HorizontalAlignLeft, 30, []int{30, 30}, image.Rect(30, 0, 48, 32)},
+		{HorizontalAlignCenter, 50, []int{47, 41}, image.Rect(41, 0, 59, 32)},
+		{HorizontalAlignRight, 60, []int{54, 42}, image.Rect(42, 0, 60, 32)},
+	}
+	for _, c := range cases {
+		img, _ := ebiten.NewImage(100, 100, ebiten.FilterNearest)
+		b := DrawWithOptions(img, text, bitmapfont.Gothic12r, c.X, 0, color.White, &DrawOptions{
+			HorizontalAlign: c.Align,
+			VerticalAlign:   VerticalAlignTop,
+		})
+		if b != c.WantBounds {
+			t.Errorf("align: %d, bounds: got %v; want %v", c.Align, b, c.WantBounds)
+		}
+
+		// Gothic12r's advance is 6 and height is 16.
+		for i, lineWidth := range []int{6, 18} {
+			minX, maxX := -1, -1
+			for j := 16 * i; j < 16*(i+1); j++ {
+				for i := 0; i < 100; i++ {
+					if _, _, _, a := img.At(i, j).RGBA(); a == 0 {
+						continue
+					}
+					if minX == -1 || i < minX {
+						minX = i
+					}
+					if maxX < i {
+						maxX = i
+					}
+				}
+			}
+			x := c.WantLineXs[i]
+			if minX < x || maxX >= x+lineWidth {
+				t.Errorf("align: %d, line: %d: got pixels in [%d, %d]; want in [%d, %d)", c.Align, i, minX, maxX, x, x+lineWidth)
+			}
+		}
+	}
+}
+
+func TestDrawSpans(t *testing.T) {
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	blue := color.RGBA{0, 0, 0xff, 0xff}
+
+	img, _ := ebiten.NewImage(30, 30, ebiten.FilterNearest)
+	b := DrawSpans(img, []Span{
+		{Text: "a", Color: red},
+		{Text: "b", Color: blue},
+		{Text: "c"},
+	}, bitmapfont.Gothic12r, 0, 12, color.White)
+
+	// Gothic12r's advance is 6, ascent is 12 and descent is 4.
+	if want := image.Rect(0, 0, 18, 16); b != want {
+		t.Errorf("bounds: got %v; want %v", b, want)
+	}
+
+	for i, want := range []color.RGBA{red, blue, {0xff, 0xff, 0xff, 0xff}} {
+		found := false
+		for j := 0; j < 16; j++ {
+			for k := 6 * i; k < 6*(i+1); k++ {
+				got := img.At(k, j).(color.RGBA)
+				if got.A == 0 {
+					continue
+				}
+				found = true
+				if got != want {
+					t.Errorf("span %d: img At(%d, %d): got %v; want %v", i, k, j, got, want)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("span %d: no pixels found", i)
+		}
+	}
+}
+
+func TestLayout(t *testing.T) {
+	const text = "Hello,\nWorld!"
+	options := &DrawOptions{
+		HorizontalAlign: HorizontalAlignCenter,
+		VerticalAlign:   VerticalAlignMiddle,
+	}
+
+	img0, _ := ebiten.NewImage(100, 100, ebiten.FilterNearest)
+	b0 := DrawWithOptions(img0, text, bitmapfont.Gothic12r, 50, 50, color.White, options)
+
+	img1, _ := ebiten.NewImage(100, 100, ebiten.FilterNearest)
+	l := NewLayout(text, bitmapfont.Gothic12r, options)
+	var geoM ebiten.GeoM
+	geoM.Translate(50, 50)
+	l.Draw(img1, geoM, color.White)
+
+	if got, want := l.Bounds().Add(image.Pt(50, 50)), b0; got != want {
+		t.Errorf("bounds: got %v; want %v", got, want)
+	}
+	for j := 0; j < 100; j++ {
+		for i := 0; i < 100; i++ {
+			got := img1.At(i, j)
+			want := img0.At(i, j)
+			if got != want {
+				t.Errorf("img1 At(%d, %d): got %v; want %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+type asciiFace struct {
+	font.Face
+}
+
+func (a *asciiFace) HasGlyph(r rune) bool {
+	return r < 0x80
+}
+
+func (a *asciiFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	return fixed.I(8), true
+}
+
+func TestFallbackFace(t *testing.T) {
+	f := NewFallbackFace(&asciiFace{bitmapfont.Gothic12r}, bitmapfont.Gothic12r)
+
+	cases := []struct {
+		Rune    rune
+		Advance fixed.Int26_6
+	}{
+		{'a', fixed.I(8)},     // asciiFace
+		{'あ', fixed.I(12)},    // bitmapfont.Gothic12r
+		{0x1f600, fixed.I(8)}, // tofu: half of the ascent + 2
+	}
+	for _, c := range cases {
+		got, ok := f.GlyphAdvance(c.Rune)
+		if !ok {
+			t.Errorf("GlyphAdvance(%q): ok must be true", c.Rune)
+		}
+		if got != c.Advance {
+			t.Errorf("GlyphAdvance(%q): got %v; want %v", c.Rune, got, c.Advance)
+		}
+	}
+
+	dr, mask, _, _, ok := f.Glyph(fixed.P(10, 20), 0x1f600)
+	if !ok || mask == nil {
+		t.Fatalf("Glyph(U+1F600) must return a tofu glyph")
+	}
+	if got, want := dr, image.Rect(11, 11, 17, 20); got != want {
+		t.Errorf("tofu rect: got %v; want %v", got, want)
+	}
+}
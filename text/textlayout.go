@@ -0,0 +1,140 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/image/font"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// layoutBatch represents glyph quads that share the same source image.
+type layoutBatch struct {
+	image    *ebiten.Image
+	vertices []ebiten.Vertex
+	indices  []uint16
+}
+
+// Layout represents a text whose layout is calculated in advance.
+//
+// Draw and DrawWithOptions calculate the glyphs' positions every time. For a static text like a label,
+// Layout avoids the calculation and draws the glyphs with fewer draw calls.
+//
+// Layout is immutable. Layout doesn't follow changes of the text, the face or the options that it was created with.
+// When any of them is changed, create a new Layout.
+//
+// Be careful that the font face held by Layout is also held by this package as Draw holds.
+type Layout struct {
+	batches []*layoutBatch
+	bounds  image.Rectangle
+
+	// tmp is a temporary buffer to transform vertices.
+	tmp []ebiten.Vertex
+}
+
+// NewLayout creates a new Layout of the text with the face and the options.
+//
+// The layout is calculated in the same way as DrawWithOptions with (0, 0) as the aligned position.
+// If options is nil, the default options are used.
+//
+// NewLayout is concurrent-safe.
+func NewLayout(text string, face font.Face, options *DrawOptions) *Layout {
+	textM.Lock()
+	defer textM.Unlock()
+
+	lines, b := layout(face, text, 0, 0, options)
+	l := &Layout{
+		bounds: b,
+	}
+
+	batches := map[*ebiten.Image]*layoutBatch{}
+	for _, line := range lines {
+		glyphImgs := getGlyphImages(face, line.runes)
+		x := line.x
+		prevR := rune(-1)
+		for i, r := range line.runes {
+			if prevR >= 0 {
+				x += face.Kern(prevR, r)
+			}
+			prevR = r
+
+			g := glyphImgs[i]
+			gx := x
+			x += glyphAdvance(face, r)
+			if g == nil {
+				continue
+			}
+
+			lb, ok := batches[g.image]
+			if !ok || len(lb.indices)+6 > ebiten.MaxIndicesNum {
+				lb = &layoutBatch{
+					image: g.image,
+				}
+				batches[g.image] = lb
+				l.batches = append(l.batches, lb)
+			}
+
+			bounds := getGlyphBounds(face, r)
+			dx := float32(fixed26_6ToFloat64(gx + bounds.Min.X))
+			dy := float32(fixed26_6ToFloat64(line.y + bounds.Min.Y))
+			w, h := float32(g.width), float32(g.height)
+			sx, sy := float32(g.x), float32(g.y)
+
+			n := uint16(len(lb.vertices))
+			lb.vertices = append(lb.vertices,
+				ebiten.Vertex{DstX: dx, DstY: dy, SrcX: sx, SrcY: sy, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+				ebiten.Vertex{DstX: dx + w, DstY: dy, SrcX: sx + w, SrcY: sy, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+				ebiten.Vertex{DstX: dx, DstY: dy + h, SrcX: sx, SrcY: sy + h, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+				ebiten.Vertex{DstX: dx + w, DstY: dy + h, SrcX: sx + w, SrcY: sy + h, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+			)
+			lb.indices = append(lb.indices, n, n+1, n+2, n+1, n+2, n+3)
+		}
+	}
+	return l
+}
+
+// Bounds returns the bounds of the text relative to the aligned position.
+//
+// The bounds' vertical range is from the ascent of the first line to the descent of the last line.
+func (l *Layout) Bounds() image.Rectangle {
+	return l.bounds
+}
+
+// Draw draws the text on a given destination image dst with the given geometry matrix and color.
+//
+// geoM is applied to the positions relative to the aligned position.
+// For example, if geoM is a translation by (x, y), the result is the same as DrawWithOptions with (x, y).
+//
+// Draw is not concurrent-safe for the same Layout.
+func (l *Layout) Draw(dst *ebiten.Image, geoM ebiten.GeoM, clr color.Color) {
+	textM.Lock()
+	colorm := colorToColorM(clr)
+	textM.Unlock()
+
+	op := &ebiten.DrawTrianglesOptions{}
+	op.ColorM = colorm
+	for _, b := range l.batches {
+		l.tmp = l.tmp[:0]
+		for _, v := range b.vertices {
+			x, y := geoM.Apply(float64(v.DstX), float64(v.DstY))
+			v.DstX, v.DstY = float32(x), float32(y)
+			l.tmp = append(l.tmp, v)
+		}
+		dst.DrawTriangles(l.tmp, b.indices, b.image, op)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// measure returns the width of the runes in one line.
+func measure(face font.Face, runes []rune) fixed.Int26_6 {
+	w := fixed.Int26_6(0)
+	prevR := rune(-1)
+	for _, r := range runes {
+		if prevR >= 0 {
+			w += face.Kern(prevR, r)
+		}
+		w += glyphAdvance(face, r)
+		prevR = r
+	}
+	return w
+}
+
+// isBreakableEverywhere returns a boolean value indicating whether a line can be broken before and after r.
+// This is true for the scripts that don't use spaces between words like CJK.
+func isBreakableEverywhere(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// splitWords splits the runes into words and spaces.
+// Each rune breakable everywhere is a word by itself.
+func splitWords(runes []rune) [][]rune {
+	var words [][]rune
+	start := 0
+	for i, r := range runes {
+		if i == start {
+			if isBreakableEverywhere(r) {
+				words = append(words, runes[i:i+1])
+				start = i + 1
+			}
+			continue
+		}
+		prev := runes[i-1]
+		if unicode.IsSpace(r) != unicode.IsSpace(prev) || isBreakableEverywhere(r) {
+			words = append(words, runes[start:i])
+			start = i
+			if isBreakableEverywhere(r) {
+				words = append(words, runes[i:i+1])
+				start = i + 1
+			}
+		}
+	}
+	if start < len(runes) {
+		words = append(words, runes[start:])
+	}
+	return words
+}
+
+// wrap breaks a paragraph without newlines into lines that fit in maxWidth.
+func wrap(face font.Face, runes []rune, maxWidth fixed.Int26_6) [][]rune {
+	var lines [][]rune
+	var line []rune
+	var spaces []rune
+	for _, word := range splitWords(runes) {
+		if unicode.IsSpace(word[0]) {
+			// Spaces at the beginning of a line are dropped.
+			if len(line) > 0 {
+				spaces = append(spaces, word...)
+			}
+			continue
+		}
+
+		if len(line) > 0 {
+			l := append(append(append([]rune{}, line...), spaces...), word...)
+			spaces = nil
+			if measure(face, l) <= maxWidth {
+				line = l
+				continue
+			}
+			lines = append(lines, line)
+			line = nil
+		}
+
+		// Hard-wrap the word if the word doesn't fit in maxWidth.
+		for len(word) > 0 {
+			n := len(word)
+			for n > 1 && measure(face, word[:n]) > maxWidth {
+				n--
+			}
+			line = append([]rune{}, word[:n]...)
+			word = word[n:]
+			if len(word) > 0 {
+				lines = append(lines, line)
+				line = nil
+			}
+		}
+	}
+	// Trailing spaces are dropped.
+	return append(lines, line)
+}
+
+// wrapLines splits the text into lines at newlines and then wraps each line.
+// If maxWidth <= 0, lines are not wrapped.
+func wrapLines(face font.Face, text string, maxWidth int) [][]rune {
+	var lines [][]rune
+	for _, p := range strings.Split(text, "\n") {
+		if maxWidth <= 0 {
+			lines = append(lines, []rune(p))
+			continue
+		}
+		lines = append(lines, wrap(face, []rune(p), fixed.I(maxWidth))...)
+	}
+	return lines
+}
+
+// Wrap breaks the text into lines so that each line fits in maxWidth with the given face.
+//
+// Lines are broken at word boundaries, i.e., spaces. A word longer than maxWidth is broken at any rune.
+// Texts without spaces like CJK can be broken at any rune. Explicit newlines ('\n') are kept.
+// Spaces at the beginning and the end of each line are removed.
+//
+// Wrap is concurrent-safe.
+func Wrap(text string, face font.Face, maxWidth int) []string {
+	textM.Lock()
+	defer textM.Unlock()
+
+	var lines []string
+	for _, l := range wrapLines(face, text, maxWidth) {
+		lines = append(lines, string(l))
+	}
+	return lines
+}
@@ -0,0 +1,242 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"image/color"
+	"math"
+	"sync"
+)
+
+type vignette struct {
+	enabled     bool
+	innerRadius float64
+	outerRadius float64
+	color       color.RGBA64
+
+	// shader renders the vignette with gl_FragCoord. shader is nil until the vignette is applied first.
+	// shaderUnavailable is true when user-defined shaders are not available on the graphics driver.
+	shader            *Shader
+	shaderSource      *Image
+	shaderUnavailable bool
+
+	// mask is the vignette image with the same size as the screen, used when shader is not available.
+	// mask is regenerated when the parameters or the screen size are changed.
+	mask  *Image
+	dirty bool
+
+	m sync.Mutex
+}
+
+var theVignette = &vignette{
+	innerRadius: 0.5,
+	outerRadius: 1.5,
+	color:       color.RGBA64{0, 0, 0, 0xffff},
+	dirty:       true,
+}
+
+// SetVignette sets the parameters of the vignette effect, that darkens the screen toward its edges.
+//
+// The radii are in normalized screen coordinates: the center of the screen is 0 and
+// the middles of the screen edges are 1. The corners are at √2.
+// Inside innerRadius, the screen is not affected. Outside outerRadius, the screen is
+// filled with clr. Between them, clr is blended smoothly.
+// As the coordinates are normalized, the effect doesn't depend on the screen resolution.
+//
+// The alpha value of clr represents the intensity of the effect.
+//
+// If innerRadius is negative or outerRadius is less than innerRadius, SetVignette panics.
+//
+// The initial values are 0.5, 1.5 and opaque black.
+//
+// SetVignette doesn't enable the effect. Use SetVignetteEnabled to enable it.
+//
+// SetVignette is concurrent-safe.
+func SetVignette(innerRadius, outerRadius float64, clr color.Color) {
+	if innerRadius < 0 {
+		panic("ebiten: innerRadius must be >= 0")
+	}
+	if outerRadius < innerRadius {
+		panic("ebiten: outerRadius must be >= innerRadius")
+	}
+	r, g, b, a := clr.RGBA()
+
+	theVignette.m.Lock()
+	defer theVignette.m.Unlock()
+	theVignette.innerRadius = innerRadius
+	theVignette.outerRadius = outerRadius
+	theVignette.color = color.RGBA64{uint16(r), uint16(g), uint16(b), uint16(a)}
+	theVignette.dirty = true
+}
+
+// IsVignetteEnabled returns a boolean value indicating whether the vignette effect is enabled.
+//
+// IsVignetteEnabled is concurrent-safe.
+func IsVignetteEnabled() bool {
+	theVignette.m.Lock()
+	defer theVignette.m.Unlock()
+	return theVignette.enabled
+}
+
+// SetVignetteEnabled sets a boolean value indicating whether the vignette effect is enabled.
+//
+// The vignette effect is applied to the screen once per rendered frame, after the game's update function
+// draws the frame.
+// The initial value is false.
+//
+// SetVignetteEnabled is concurrent-safe.
+func SetVignetteEnabled(enabled bool) {
+	theVignette.m.Lock()
+	defer theVignette.m.Unlock()
+	theVignette.enabled = enabled
+}
+
+// apply draws the vignette effect onto the given screen if the effect is enabled.
+func (v *vignette) apply(screen *Image) {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	if !v.enabled {
+		return
+	}
+
+	if v.applyWithShader(screen) {
+		return
+	}
+
+	w, h := screen.Size()
+	if v.mask != nil {
+		if mw, mh := v.mask.Size(); mw != w || mh != h {
+			_ = v.mask.Dispose()
+			v.mask = nil
+		}
+	}
+	if v.mask == nil {
+		v.mask, _ = NewImage(w, h, FilterDefault)
+		v.dirty = true
+	}
+	if v.dirty {
+		_ = v.mask.ReplacePixels(v.pixels(w, h))
+		v.dirty = false
+	}
+
+	op := &DrawImageOptions{}
+	_ = screen.DrawImage(v.mask, op)
+}
+
+const vignetteShaderSrc = `
+uniform vec2 vignette_size;
+uniform vec2 vignette_radii;
+uniform vec4 vignette_color;
+
+vec4 shade(vec4 color) {
+  // The normalized coordinates: the center of the screen is 0 and the middles of the edges are 1.
+  // The vignette is symmetric, so it doesn't matter whether the framebuffer's Y axis is flipped or not.
+  vec2 p = 2.0 * gl_FragCoord.xy / vignette_size - 1.0;
+  float d = length(p);
+  float t = step(vignette_radii.x, d);
+  if (vignette_radii.x < vignette_radii.y) {
+    t = smoothstep(vignette_radii.x, vignette_radii.y, d);
+  }
+  return vec4(vignette_color.rgb, vignette_color.a * t);
+}
+`
+
+// applyWithShader draws the vignette effect with a fragment shader, and reports whether it is drawn.
+//
+// gl_FragCoord is relative to the underlying framebuffer, so the shader is used only for a volatile screen,
+// that is never put on a shared texture. Otherwise, or when user-defined shaders are not available,
+// applyWithShader returns false and the caller falls back to the mask.
+func (v *vignette) applyWithShader(screen *Image) bool {
+	if v.shaderUnavailable {
+		return false
+	}
+	if !screen.mipmap.orig.IsVolatile() {
+		return false
+	}
+	if v.shader == nil {
+		s, err := NewShader(vignetteShaderSrc)
+		if err != nil {
+			v.shaderUnavailable = true
+			return false
+		}
+		v.shader = s
+		v.shaderSource, _ = NewImage(16, 16, FilterDefault)
+		_ = v.shaderSource.Fill(color.White)
+	}
+
+	w, h := screen.Size()
+	scale := 1.0
+	if screen.renderScale != 0 {
+		scale = screen.renderScale
+	}
+
+	// v.color is premultiplied, while shade returns a color in straight alpha.
+	var r, g, b float32
+	a := float32(v.color.A) / 0xffff
+	if a > 0 {
+		r = float32(v.color.R) / 0xffff / a
+		g = float32(v.color.G) / 0xffff / a
+		b = float32(v.color.B) / 0xffff / a
+	}
+
+	sw, sh := v.shaderSource.Size()
+	op := &DrawImageOptions{}
+	op.GeoM.Scale(float64(w)/float64(sw), float64(h)/float64(sh))
+	op.Shader = v.shader
+	op.Uniforms = Uniforms{
+		// The size of the screen in the framebuffer's pixels.
+		"vignette_size":  []float32{float32(float64(w) * scale), float32(float64(h) * scale)},
+		"vignette_radii": []float32{float32(v.innerRadius), float32(v.outerRadius)},
+		"vignette_color": []float32{r, g, b, a},
+	}
+	_ = screen.DrawImage(v.shaderSource, op)
+	return true
+}
+
+// pixels returns the premultiplied pixels of the vignette mask.
+func (v *vignette) pixels(width, height int) []byte {
+	pix := make([]byte, 4*width*height)
+	for j := 0; j < height; j++ {
+		y := 2*(float64(j)+0.5)/float64(height) - 1
+		for i := 0; i < width; i++ {
+			x := 2*(float64(i)+0.5)/float64(width) - 1
+			t := smoothstep(v.innerRadius, v.outerRadius, math.Hypot(x, y))
+			idx := 4 * (i + j*width)
+			pix[idx] = uint8(float64(v.color.R>>8) * t)
+			pix[idx+1] = uint8(float64(v.color.G>>8) * t)
+			pix[idx+2] = uint8(float64(v.color.B>>8) * t)
+			pix[idx+3] = uint8(float64(v.color.A>>8) * t)
+		}
+	}
+	return pix
+}
+
+func smoothstep(edge0, edge1, x float64) float64 {
+	if edge0 == edge1 {
+		if x < edge0 {
+			return 0
+		}
+		return 1
+	}
+	t := (x - edge0) / (edge1 - edge0)
+	if t < 0 {
+		t = 0
+	}
+	if t > 1 {
+		t = 1
+	}
+	return t * t * (3 - 2*t)
+}
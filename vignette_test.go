@@ -0,0 +1,120 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten_test
+
+import (
+	"image/color"
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten"
+)
+
+func TestVignetteMaskResolutionIndependent(t *testing.T) {
+	clr := color.RGBA64{0x8000, 0x4000, 0x2000, 0xffff}
+	// The pixel centers of the small mask coincide with the pixel centers of the large mask in the normalized
+	// coordinates, as the large mask has three times as many pixels in each direction.
+	for _, size := range []struct {
+		Width  int
+		Height int
+	}{
+		{3, 3},
+		{4, 2},
+		{10, 7},
+	} {
+		const n = 3
+		small := VignettePixelsForTesting(0.2, 1.2, clr, size.Width, size.Height)
+		large := VignettePixelsForTesting(0.2, 1.2, clr, n*size.Width, n*size.Height)
+		for j := 0; j < size.Height; j++ {
+			for i := 0; i < size.Width; i++ {
+				k := 4 * (i + j*size.Width)
+				l := 4 * ((n*i + n/2) + (n*j+n/2)*n*size.Width)
+				for c := 0; c < 4; c++ {
+					got, want := int(large[l+c]), int(small[k+c])
+					if got < want-1 || want+1 < got {
+						t.Errorf("size: %dx%d, (%d, %d), component %d: got: %d, want: %d", size.Width, size.Height, i, j, c, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestVignetteDisabled(t *testing.T) {
+	defer func() {
+		SetVignetteEnabled(false)
+		SetVignette(0.5, 1.5, color.Black)
+	}()
+
+	const w, h = 16, 16
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	SetVignette(0.5, 1.5, color.Black)
+
+	screen, _ := NewImage(w, h, FilterDefault)
+	screen.Fill(white)
+	SetVignetteEnabled(false)
+	ApplyVignetteForTesting(screen)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			if got := screen.At(i, j); got != white {
+				t.Errorf("screen.At(%d, %d) with the vignette disabled: got: %v, want: %v", i, j, got, white)
+			}
+		}
+	}
+
+	// Check that the same screen is affected when the vignette is enabled.
+	SetVignetteEnabled(true)
+	ApplyVignetteForTesting(screen)
+	if got := screen.At(w/2, h/2); got != white {
+		t.Errorf("screen.At(%d, %d) with the vignette enabled: got: %v, want: %v", w/2, h/2, got, white)
+	}
+	if got := screen.At(0, 0); got == white {
+		t.Errorf("screen.At(0, 0) with the vignette enabled must be darkened but not")
+	}
+}
+
+func TestVignetteShader(t *testing.T) {
+	defer func() {
+		SetVignetteEnabled(false)
+		SetVignette(0.5, 1.5, color.Black)
+	}()
+
+	const w, h = 16, 8
+	clr := color.RGBA64{0x8000, 0x4000, 0, 0xffff}
+	SetVignette(0.2, 1.2, clr)
+	SetVignetteEnabled(true)
+
+	// A volatile screen like the actual screen is rendered with the shader. The result must match the mask.
+	screen := NewScaledVolatileImageForTesting(w, h, 1)
+	screen.Fill(color.White)
+	ApplyVignetteForTesting(screen)
+
+	mask := VignettePixelsForTesting(0.2, 1.2, clr, w, h)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			k := 4 * (i + j*w)
+			a := int(mask[k+3])
+			want := color.RGBA{
+				uint8(int(mask[k]) + 0xff - a),
+				uint8(int(mask[k+1]) + 0xff - a),
+				uint8(int(mask[k+2]) + 0xff - a),
+				0xff,
+			}
+			got := screen.At(i, j).(color.RGBA)
+			if !sameColors(got, want, 3) {
+				t.Errorf("screen.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"image/color"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/internal/graphicscommand"
+	"github.com/hajimehoshi/ebiten/internal/ui"
+)
+
+// Window represents an additional window, e.g., an inspector of the main game view.
+type Window struct {
+	f         func(*Image) error
+	offscreen *Image
+	window    windowImpl
+}
+
+// windowImpl is the platform-dependent part of an additional window.
+//
+// *ui.Window implements windowImpl. windowImpl is an interface so that tests can replace it.
+type windowImpl interface {
+	Close()
+	IsClosed() bool
+	Present(f func(width, height int) error) error
+}
+
+var (
+	windows  []*Window
+	windowsM sync.Mutex
+)
+
+// NewWindow creates an additional window with the given updating function f.
+//
+// f is called once every frame after the main updating function, with the additional window's screen.
+// The screen is cleared before f is called as the main screen is.
+//
+// NewWindow must be called while the game is running, e.g., in the main updating function.
+//
+// An additional window has its own OpenGL context, that shares the textures with the main window's context.
+// All the images can be drawn on any window's screen, but the rendering is always done by the main context
+// and only the result is copied to the additional window. Thus, an additional window is not faster than
+// the main window, and vsync is in sync with the main window.
+//
+// Input is not routed to additional windows: the input functions like IsKeyPressed and CursorPosition always
+// report the states of the main window, regardless of which window has focus.
+//
+// When the user closes an additional window, the window is closed and f is never called again.
+// Closing an additional window doesn't terminate the game.
+//
+// Additional windows are available only on desktops with OpenGL.
+// On the other environments, NewWindow returns an error.
+//
+// NewWindow is concurrent-safe.
+func NewWindow(f func(*Image) error, width, height int, scale float64, title string) (*Window, error) {
+	uw, err := ui.NewWindow(width, height, scale, title)
+	if err != nil {
+		return nil, err
+	}
+	w := &Window{
+		f:         f,
+		offscreen: newVolatileImage(width, height),
+		window:    uw,
+	}
+
+	windowsM.Lock()
+	windows = append(windows, w)
+	windowsM.Unlock()
+	return w, nil
+}
+
+// Close closes the window.
+//
+// Close is concurrent-safe.
+func (w *Window) Close() {
+	w.window.Close()
+}
+
+// IsClosed returns a boolean value indicating whether the window is closed by Close or by the user.
+//
+// IsClosed is concurrent-safe.
+func (w *Window) IsClosed() bool {
+	return w.window.IsClosed()
+}
+
+// updateWindows updates the additional windows and copies their screens to the windows.
+//
+// If update is false, the windows' updating functions are not called but the windows are presented.
+func updateWindows(update bool) error {
+	windowsM.Lock()
+	ws := make([]*Window, 0, len(windows))
+	alive := windows[:0]
+	for _, w := range windows {
+		if w.window.IsClosed() {
+			w.window.Close()
+			_ = w.offscreen.Dispose()
+			continue
+		}
+		alive = append(alive, w)
+		ws = append(ws, w)
+	}
+	windows = alive
+	windowsM.Unlock()
+
+	if len(ws) == 0 {
+		return nil
+	}
+
+	if update {
+		for _, w := range ws {
+			w.offscreen.Fill(color.Transparent)
+			if err := w.f(w.offscreen); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The commands must be executed on the main context before switching the current context.
+	graphicscommand.FlushCommands()
+
+	for _, w := range ws {
+		s := w.offscreen.mipmap.original()
+		if err := w.window.Present(func(width, height int) error {
+			return s.BlitToDefaultFramebuffer(width, height)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten_test
+
+import (
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten"
+)
+
+func TestUpdateWindowsPrunesClosedWindows(t *testing.T) {
+	counts := map[int]int{}
+	newWindow := func(id int) *Window {
+		return NewFakeWindowForTesting(func(screen *Image) error {
+			counts[id]++
+			return nil
+		}, 16, 16)
+	}
+	w0 := newWindow(0)
+	w1 := newWindow(1)
+	w2 := newWindow(2)
+	defer func() {
+		w0.Close()
+		w1.Close()
+		w2.Close()
+		if err := UpdateWindowsForTesting(false); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	w1.Close()
+	w2.CloseByUserForTesting()
+
+	if err := UpdateWindowsForTesting(true); err != nil {
+		t.Fatal(err)
+	}
+	ws := WindowsForTesting()
+	if len(ws) != 1 || ws[0] != w0 {
+		t.Errorf("windows after updating: got: %v, want: [%v]", ws, w0)
+	}
+	if got, want := counts[0], 1; got != want {
+		t.Errorf("the update count of the alive window: got: %d, want: %d", got, want)
+	}
+	if got, want := counts[1]+counts[2], 0; got != want {
+		t.Errorf("the update count of the closed windows: got: %d, want: %d", got, want)
+	}
+	if got, want := w0.PresentCountForTesting(), 1; got != want {
+		t.Errorf("the present count of the alive window: got: %d, want: %d", got, want)
+	}
+	for _, w := range []*Window{w1, w2} {
+		if !w.IsClosed() {
+			t.Errorf("a pruned window must be closed")
+		}
+		if !w.IsOffscreenDisposedForTesting() {
+			t.Errorf("a pruned window's screen must be disposed")
+		}
+	}
+	if w0.IsOffscreenDisposedForTesting() {
+		t.Errorf("the alive window's screen must not be disposed")
+	}
+
+	// Without updating, the windows are only presented.
+	if err := UpdateWindowsForTesting(false); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := counts[0], 1; got != want {
+		t.Errorf("the update count of the alive window: got: %d, want: %d", got, want)
+	}
+	if got, want := w0.PresentCountForTesting(), 2; got != want {
+		t.Errorf("the present count of the alive window: got: %d, want: %d", got, want)
+	}
+
+	w0.Close()
+	if err := UpdateWindowsForTesting(true); err != nil {
+		t.Fatal(err)
+	}
+	if got := WindowsForTesting(); len(got) != 0 {
+		t.Errorf("windows after closing all: got: %v, want: []", got)
+	}
+}